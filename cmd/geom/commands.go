@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/clip"
+	"github.com/go-spatial/geom/planar/makevalid"
+	"github.com/go-spatial/geom/planar/prepared"
+	"github.com/go-spatial/geom/planar/simplify"
+	"github.com/go-spatial/geom/planar/triangulate"
+	"github.com/go-spatial/geom/planar/triangulate/constraineddelaunay"
+	"github.com/go-spatial/geom/slippy"
+)
+
+var ErrNoExtent = errors.New("an -extent is required")
+
+// ErrClipPolygon is returned by clip for polygons, which the clip package
+// does not support.
+var ErrClipPolygon = errors.New("polygons can not be clipped, use makevalid -extent")
+
+func setupConvert(fs *flag.FlagSet) operation {
+	return func(ctx context.Context, g geom.Geometry, w *writer) error {
+		return w.Geometry(g)
+	}
+}
+
+func setupMakevalid(fs *flag.FlagSet) operation {
+	var extent extentFlag
+	fs.Var(&extent, "extent", "clip the result to `minx,miny,maxx,maxy`")
+	return func(ctx context.Context, g geom.Geometry, w *writer) error {
+		mv := makevalid.Makevalid{}
+		if extent.extent != nil {
+			mv.Clipper = clip.Default
+		}
+		g, _, err := mv.Makevalid(ctx, g, extent.extent)
+		if err != nil {
+			return err
+		}
+		return w.Geometry(g)
+	}
+}
+
+func setupClip(fs *flag.FlagSet) operation {
+	var extent extentFlag
+	fs.Var(&extent, "extent", "the `minx,miny,maxx,maxy` to clip to (required)")
+	return func(ctx context.Context, g geom.Geometry, w *writer) error {
+		if extent.extent == nil {
+			return ErrNoExtent
+		}
+		g, err := clip.Geometry(ctx, g, extent.extent)
+		if err == clip.ErrUnsupportedGeometry {
+			return ErrClipPolygon
+		}
+		if err != nil {
+			return err
+		}
+		return w.Geometry(g)
+	}
+}
+
+func setupSimplify(fs *flag.FlagSet) operation {
+	tolerance := fs.Float64("tolerance", 0, "the distance within which points are removed")
+	return func(ctx context.Context, g geom.Geometry, w *writer) error {
+		g, err := planar.Simplify(ctx, simplify.DouglasPeucker{Tolerance: *tolerance}, g)
+		if err != nil {
+			return err
+		}
+		return w.Geometry(g)
+	}
+}
+
+func setupTriangulate(fs *flag.FlagSet) operation {
+	constrained := fs.Bool("constrained", false, "keep the lines of the geometries as edges")
+	tolerance := fs.Float64("tolerance", 0, "the distance within which points are the same")
+	return func(ctx context.Context, g geom.Geometry, w *writer) error {
+		var (
+			triangles geom.MultiPolygon
+			err       error
+		)
+		if *constrained {
+			tri := new(constraineddelaunay.Triangulator)
+			if err = tri.InsertGeometry(g); err != nil {
+				return err
+			}
+			triangles, err = tri.GetTriangles()
+		} else {
+			builder := triangulate.NewDelaunayTriangulationBuilder(*tolerance)
+			if err = builder.SetSites(g); err != nil {
+				return err
+			}
+			triangles, err = builder.GetTriangles()
+		}
+		if err != nil {
+			return err
+		}
+		return w.Geometry(triangles)
+	}
+}
+
+func setupTile(fs *flag.FlagSet) operation {
+	zoom := fs.Uint("zoom", 0, "the zoom of the tiles")
+	crs := fs.Int("crs", 4326, "the EPSG code of the coordinates; 4326 or 3857")
+	return func(ctx context.Context, g geom.Geometry, w *writer) error {
+		if *zoom > slippy.MaxZoom {
+			return fmt.Errorf("zoom %v is more than %v", *zoom, slippy.MaxZoom)
+		}
+		if *crs != 4326 && *crs != 3857 {
+			return fmt.Errorf("unsupported crs %v", *crs)
+		}
+		tiles, err := coveringTiles(ctx, g, *zoom, *crs == 3857)
+		if err != nil {
+			return err
+		}
+		for _, t := range tiles {
+			if err := w.Line(fmt.Sprintf("%v/%v/%v", t.Z, t.X, t.Y)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// the latitude at the edges of web mercator
+const maxLatitude = 85.0511287798066
+
+/*
+coveringTiles returns the tiles at the zoom that the geometry touches, in
+rows from the north west. The coordinates are longitude and latitude, or
+web mercator if webMercator is true.
+*/
+func coveringTiles(ctx context.Context, g geom.Geometry, zoom uint, webMercator bool) ([]*slippy.Tile, error) {
+	e, err := geom.NewExtentFromGeometry(g)
+	if err != nil {
+		return nil, err
+	}
+	last := uint(1)<<zoom - 1
+	clamp := func(i uint) uint {
+		if i > last {
+			return last
+		}
+		return i
+	}
+	var minX, minY, maxX, maxY uint
+	if webMercator {
+		m := slippy.WebMercatorMax
+		minX = clamp(slippy.WebX2Tile(zoom, math.Max(e.MinX(), -m)))
+		maxX = clamp(slippy.WebX2Tile(zoom, math.Min(e.MaxX(), m)))
+		minY = clamp(slippy.WebY2Tile(zoom, math.Min(e.MaxY(), m)))
+		maxY = clamp(slippy.WebY2Tile(zoom, math.Max(e.MinY(), -m)))
+	} else {
+		minX = clamp(slippy.Lon2Tile(zoom, math.Max(e.MinX(), -180)))
+		maxX = clamp(slippy.Lon2Tile(zoom, math.Min(e.MaxX(), 180)))
+		minY = clamp(slippy.Lat2Tile(zoom, math.Min(e.MaxY(), maxLatitude)))
+		maxY = clamp(slippy.Lat2Tile(zoom, math.Max(e.MinY(), -maxLatitude)))
+	}
+
+	intersects, err := intersecter(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	var tiles []*slippy.Tile
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			t := slippy.NewTile(zoom, x, y)
+			te := t.Extent4326()
+			if webMercator {
+				te = t.Extent3857()
+			}
+			touches, err := intersects(te)
+			if err != nil {
+				return nil, err
+			}
+			if touches {
+				tiles = append(tiles, t)
+			}
+		}
+	}
+	return tiles, nil
+}
+
+/*
+intersecter returns a function that reports if any of g is in an extent.
+Polygons are prepared once, as there are many extents to test.
+*/
+func intersecter(ctx context.Context, g geom.Geometry) (func(e *geom.Extent) (bool, error), error) {
+	switch g := g.(type) {
+	case geom.Polygoner, geom.MultiPolygoner:
+		p, err := prepared.New(g)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *geom.Extent) (bool, error) { return p.Intersects(e), nil }, nil
+
+	case geom.Collectioner:
+		var fns []func(e *geom.Extent) (bool, error)
+		for _, g := range g.Geometries() {
+			fn, err := intersecter(ctx, g)
+			if err != nil {
+				return nil, err
+			}
+			fns = append(fns, fn)
+		}
+		return func(e *geom.Extent) (bool, error) {
+			for _, fn := range fns {
+				if ok, err := fn(e); ok || err != nil {
+					return ok, err
+				}
+			}
+			return false, nil
+		}, nil
+	}
+	return func(e *geom.Extent) (bool, error) {
+		clipped, err := clip.Geometry(ctx, g, e)
+		if err != nil || clipped == nil {
+			return false, err
+		}
+		pts, err := geom.GetCoordinates(clipped)
+		return len(pts) > 0, err
+	}, nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+	"github.com/go-spatial/geom/encoding/svg"
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/geom/encoding/wkt"
+)
+
+// The formats geometries are read and written in. Auto is only for input,
+// and SVG only for output.
+const (
+	formatAuto    = "auto"
+	formatWKT     = "wkt"
+	formatWKB     = "wkb"
+	formatWKBHex  = "wkbhex"
+	formatGeoJSON = "geojson"
+	formatSVG     = "svg"
+)
+
+// ErrUnknownFormat is returned for a format that can not be read or written.
+type ErrUnknownFormat struct {
+	Format string
+}
+
+func (e ErrUnknownFormat) Error() string {
+	return fmt.Sprintf("unknown format %q", e.Format)
+}
+
+/*
+detectFormat guesses the format of the input; JSON is GeoJSON, text of hex
+digits is hex WKB, other text is WKT and anything else is binary WKB.
+*/
+func detectFormat(data []byte) string {
+	text := bytes.TrimSpace(data)
+	switch {
+	case len(text) == 0:
+		return formatWKT
+	case text[0] == '{':
+		return formatGeoJSON
+	case isHex(text):
+		return formatWKBHex
+	case isText(text):
+		return formatWKT
+	}
+	return formatWKB
+}
+
+func isHex(text []byte) bool {
+	for _, c := range text {
+		if !strings.ContainsRune("0123456789abcdefABCDEF \t\r\n", rune(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isText(text []byte) bool {
+	for _, c := range text {
+		if c < ' ' && c != '\t' && c != '\r' && c != '\n' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+readGeometries decodes the geometries of the input in the format. WKT and
+hex WKB inputs have a geometry on each line, blank lines are skipped.
+GeoJSON and binary WKB inputs are a single geometry; the geometries of a
+GeoJSON feature collection are returned as a collection.
+*/
+func readGeometries(data []byte, format string) ([]geom.Geometry, error) {
+	if format == formatAuto {
+		format = detectFormat(data)
+	}
+	switch format {
+	case formatWKT, formatWKBHex:
+		var geos []geom.Geometry
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			g, err := decodeLine(line, format)
+			if err != nil {
+				return nil, err
+			}
+			geos = append(geos, g)
+		}
+		return geos, nil
+
+	case formatWKB:
+		g, err := wkb.DecodeBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		return []geom.Geometry{g}, nil
+
+	case formatGeoJSON:
+		g, err := decodeGeoJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		return []geom.Geometry{g}, nil
+	}
+	return nil, ErrUnknownFormat{Format: format}
+}
+
+func decodeLine(line, format string) (geom.Geometry, error) {
+	if format == formatWKT {
+		return wkt.Decode(line)
+	}
+	data, err := hex.DecodeString(line)
+	if err != nil {
+		return nil, err
+	}
+	return wkb.DecodeBytes(data)
+}
+
+// decodeGeoJSON decodes a GeoJSON geometry, feature or feature collection.
+func decodeGeoJSON(data []byte) (geom.Geometry, error) {
+	// checked here for a clearer error than the geojson package gives
+	var typed struct {
+		Type *string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, err
+	}
+	if typed.Type == nil {
+		return nil, fmt.Errorf("GeoJSON object without a type")
+	}
+	var g geojson.Geometry
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+	switch f := g.Geometry.(type) {
+	case geojson.Feature:
+		return f.Geometry.Geometry, nil
+	case geojson.FeatureCollection:
+		col := make(geom.Collection, len(f.Features))
+		for i := range f.Features {
+			col[i] = f.Features[i].Geometry.Geometry
+		}
+		return col, nil
+	}
+	return g.Geometry, nil
+}
+
+/*
+writeGeometry writes the geometry in the format. Text formats are followed
+by a new line.
+*/
+func writeGeometry(w io.Writer, g geom.Geometry, format string) error {
+	switch format {
+	case formatWKT:
+		text, err := wkt.Encode(g)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, text)
+		return err
+
+	case formatWKB:
+		return wkb.Encode(w, g)
+
+	case formatWKBHex:
+		data, err := wkb.EncodeBytes(g)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, hex.EncodeToString(data))
+		return err
+
+	case formatGeoJSON:
+		data, err := json.Marshal(geojson.Geometry{Geometry: g})
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+
+	case formatSVG:
+		text, err := svg.Encode(g)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, text)
+		return err
+	}
+	return ErrUnknownFormat{Format: format}
+}
@@ -0,0 +1,199 @@
+/*
+Command geom converts geometries between formats, and runs the operations of
+this module on them, so library behaviour can be reproduced without writing
+Go.
+
+Usage:
+
+	geom <command> [flags] [file ...]
+
+The geometries are read from the files, or from stdin if there are none, and
+written to stdout. The commands are:
+
+	convert      write the geometries in another format
+	makevalid    make polygons valid
+	clip         clip the geometries to an extent
+	simplify     simplify the geometries with Douglas-Peucker
+	triangulate  write the Delaunay triangles of the geometries
+	tile         list the slippy tiles that cover the geometries
+
+All commands take -from, the input format (auto, wkt, wkb, wkbhex or
+geojson), and -to, the output format (wkt, wkb, wkbhex, geojson or svg). WKT
+and hex WKB inputs have a geometry on each line.
+
+Run geom <command> -h for the flags of a command.
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+/*
+operation runs a command on a geometry, and writes the result with w.
+*/
+type operation func(ctx context.Context, g geom.Geometry, w *writer) error
+
+// command is a subcommand; setup defines its flags, and returns the
+// operation that uses them.
+type command struct {
+	usage string
+	setup func(fs *flag.FlagSet) operation
+}
+
+var commands = map[string]command{
+	"convert":     {"write the geometries in another format", setupConvert},
+	"makevalid":   {"make polygons valid", setupMakevalid},
+	"clip":        {"clip the geometries to an extent", setupClip},
+	"simplify":    {"simplify the geometries with Douglas-Peucker", setupSimplify},
+	"triangulate": {"write the Delaunay triangles of the geometries", setupTriangulate},
+	"tile":        {"list the slippy tiles that cover the geometries", setupTile},
+}
+
+// writer writes the results of an operation to the output.
+type writer struct {
+	w      io.Writer
+	format string
+}
+
+func (w *writer) Geometry(g geom.Geometry) error {
+	return writeGeometry(w.w, g, w.format)
+}
+
+func (w *writer) Line(s string) error {
+	_, err := fmt.Fprintln(w.w, s)
+	return err
+}
+
+// extentFlag is a flag of an extent given as minx,miny,maxx,maxy.
+type extentFlag struct {
+	extent *geom.Extent
+}
+
+func (f *extentFlag) String() string {
+	if f.extent == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v,%v,%v,%v", f.extent[0], f.extent[1], f.extent[2], f.extent[3])
+}
+
+func (f *extentFlag) Set(s string) error {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return fmt.Errorf("expected minx,miny,maxx,maxy")
+	}
+	var e geom.Extent
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return err
+		}
+		e[i] = v
+	}
+	if e[0] > e[2] || e[1] > e[3] {
+		return fmt.Errorf("expected minx <= maxx and miny <= maxy")
+	}
+	f.extent = &e
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: geom <command> [flags] [file ...]")
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12v %v\n", name, commands[name].usage)
+	}
+}
+
+/*
+run runs the command line args, without the program name, and returns the
+exit code; 0 on success, 1 if a geometry could not be read, processed or
+written and 2 for a usage error.
+*/
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "geom: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", formatAuto, "input `format`: auto, wkt, wkb, wkbhex or geojson")
+	to := fs.String("to", formatWKT, "output `format`: wkt, wkb, wkbhex, geojson or svg")
+	op := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: geom %v [flags] [file ...]\n", args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	switch *to {
+	case formatWKT, formatWKB, formatWKBHex, formatGeoJSON, formatSVG:
+	default:
+		fmt.Fprintf(stderr, "geom: %v\n", ErrUnknownFormat{Format: *to})
+		return 2
+	}
+
+	inputs := []io.Reader{stdin}
+	names := []string{"stdin"}
+	if fs.NArg() > 0 {
+		inputs, names = nil, fs.Args()
+		for _, name := range names {
+			f, err := os.Open(name)
+			if err != nil {
+				fmt.Fprintf(stderr, "geom: %v\n", err)
+				return 1
+			}
+			defer f.Close()
+			inputs = append(inputs, f)
+		}
+	}
+
+	ctx := context.Background()
+	w := &writer{w: stdout, format: *to}
+	for i, r := range inputs {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			fmt.Fprintf(stderr, "geom: %v: %v\n", names[i], err)
+			return 1
+		}
+		geos, err := readGeometries(data, *from)
+		if err != nil {
+			fmt.Fprintf(stderr, "geom: %v: %v\n", names[i], err)
+			return 1
+		}
+		for _, g := range geos {
+			if err := op(ctx, g, w); err != nil {
+				fmt.Fprintf(stderr, "geom: %v: %v\n", names[i], err)
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	type tcase struct {
+		args   []string
+		stdin  string
+		stdout string
+		// a part of stderr
+		stderr string
+		code   int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+			if code != tc.code {
+				t.Errorf("exit code, expected %v got %v (%v)", tc.code, code, stderr.String())
+			}
+			if got := stdout.String(); got != tc.stdout {
+				t.Errorf("stdout, expected %q got %q", tc.stdout, got)
+			}
+			if !strings.Contains(stderr.String(), tc.stderr) {
+				t.Errorf("stderr, expected to contain %q got %q", tc.stderr, stderr.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"no command": {
+			stderr: "usage: geom <command>",
+			code:   2,
+		},
+		"unknown command": {
+			args:   []string{"buffer"},
+			stderr: `unknown command "buffer"`,
+			code:   2,
+		},
+		"unknown format": {
+			args:   []string{"convert", "-to", "kml"},
+			stderr: `unknown format "kml"`,
+			code:   2,
+		},
+		"wkt to geojson": {
+			args:   []string{"convert", "-to", "geojson"},
+			stdin:  "POINT (1 2)\n\nLINESTRING (0 0,1 1)\n",
+			stdout: `{"type":"Point","coordinates":[1,2]}` + "\n" + `{"type":"LineString","coordinates":[[0,0],[1,1]]}` + "\n",
+		},
+		"wkt to wkbhex": {
+			args:   []string{"convert", "-to", "wkbhex"},
+			stdin:  "POINT (1 2)",
+			stdout: "0101000000000000000000f03f0000000000000040\n",
+		},
+		"wkbhex to wkt": {
+			stdin:  "0101000000000000000000f03f0000000000000040",
+			args:   []string{"convert"},
+			stdout: "POINT (1 2)\n",
+		},
+		"wkb to wkt": {
+			args:   []string{"convert", "-from", "wkb"},
+			stdin:  "\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\x00\x40",
+			stdout: "POINT (1 2)\n",
+		},
+		"geojson feature collection": {
+			args:   []string{"convert"},
+			stdin:  `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null}]}`,
+			stdout: "GEOMETRYCOLLECTION (POINT (1 2))\n",
+		},
+		"geojson without type": {
+			args:   []string{"convert"},
+			stdin:  `{"coordinates":[1,2]}`,
+			stderr: "GeoJSON object without a type",
+			code:   1,
+		},
+		"bad wkt": {
+			args:   []string{"convert"},
+			stdin:  "POINT (1)",
+			stderr: "stdin: wkt: syntax error",
+			code:   1,
+		},
+		"clip": {
+			args:   []string{"clip", "-extent", "0,0,5,5"},
+			stdin:  "LINESTRING (-5 1,10 1)",
+			stdout: "MULTILINESTRING ((0 1,5 1))\n",
+		},
+		"clip without extent": {
+			args:   []string{"clip"},
+			stdin:  "POINT (1 1)",
+			stderr: ErrNoExtent.Error(),
+			code:   1,
+		},
+		"clip polygon": {
+			args:   []string{"clip", "-extent", "0,0,5,5"},
+			stdin:  "POLYGON ((0 0,10 0,10 10,0 0))",
+			stderr: ErrClipPolygon.Error(),
+			code:   1,
+		},
+		"bad extent": {
+			args:   []string{"clip", "-extent", "0,0,5"},
+			stderr: "expected minx,miny,maxx,maxy",
+			code:   2,
+		},
+		"makevalid": {
+			args:   []string{"makevalid"},
+			stdin:  "POLYGON ((0 0,10 0,10 10,0 10,0 0))",
+			stdout: "MULTIPOLYGON (((0 0,10 0,10 10,0 10)))\n",
+		},
+		"simplify": {
+			args:   []string{"simplify", "-tolerance", "0.1"},
+			stdin:  "LINESTRING (0 0,1 0.01,2 0)",
+			stdout: "LINESTRING (0 0,2 0)\n",
+		},
+		"triangulate": {
+			args:   []string{"triangulate"},
+			stdin:  "MULTIPOINT (0 0,10 0,0 10)",
+			stdout: "MULTIPOLYGON (((0 10,0 0,10 0,0 10)))\n",
+		},
+		"tile": {
+			args:   []string{"tile", "-zoom", "2"},
+			stdin:  "POLYGON ((-1 -1,1 -1,1 1,-1 1,-1 -1))",
+			stdout: "2/1/1\n2/2/1\n2/1/2\n2/2/2\n",
+		},
+		"tile web mercator": {
+			args:   []string{"tile", "-zoom", "1", "-crs", "3857"},
+			stdin:  "POINT (100 100)",
+			stdout: "1/1/0\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geom")
+	if err != nil {
+		t.Fatalf("temp dir error, expected nil got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var names []string
+	for i, text := range []string{"POINT (1 2)", `{"type":"Point","coordinates":[3,4]}`} {
+		name := filepath.Join(dir, string('a'+rune(i)))
+		if err := ioutil.WriteFile(name, []byte(text), 0644); err != nil {
+			t.Fatalf("write error, expected nil got %v", err)
+		}
+		names = append(names, name)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"convert"}, names...)
+	if code := run(args, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code, expected 0 got %v (%v)", code, stderr.String())
+	}
+	if expected := "POINT (1 2)\nPOINT (3 4)\n"; stdout.String() != expected {
+		t.Errorf("stdout, expected %q got %q", expected, stdout.String())
+	}
+}
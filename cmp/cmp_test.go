@@ -140,6 +140,9 @@ func TestPoint(t *testing.T) {
 	fn := func(t *testing.T, tc tc) {
 		gp1, gp2 := geom.Point(tc.p1), geom.Point(tc.p2)
 		e := (tc.p1[0] == tc.p2[0]) && (tc.p1[1] == tc.p2[1])
+		if d := Diff(gp1, gp2); e != (d == "") {
+			t.Errorf("Diff, expected equal %v got %q", e, d)
+		}
 		if e != PointEqual(tc.p1, tc.p2) {
 			t.Errorf("p1 == p2, expected %v got %v", e, !e)
 		}
@@ -200,6 +203,9 @@ func TestMultiPoint(t *testing.T) {
 	fn := func(t *testing.T, tc tc) {
 
 		gmp1, gmp2 := geom.MultiPoint(tc.l1), geom.MultiPoint(tc.l2)
+		if d := Diff(gmp1, gmp2); tc.e != (d == "") {
+			t.Errorf("Diff, expected equal %v got %q", tc.e, d)
+		}
 		if tc.e != MultiPointerEqual(gmp1, gmp2) {
 			t.Errorf("MultiPointer are equal, expected %v got %v", tc.e, !tc.e)
 		}
@@ -277,6 +283,9 @@ func TestLineString(t *testing.T) {
 
 	fn := func(t *testing.T, tc tc) {
 		g1, g2 := geom.LineString(tc.l1), geom.LineString(tc.l2)
+		if d := Diff(g1, g2); tc.e != (d == "") {
+			t.Errorf("Diff, expected equal %v got %q", tc.e, d)
+		}
 		if tc.e != LineStringEqual(tc.l1, tc.l2) {
 			t.Errorf("LineString equal, expected %v got %v", tc.e, !tc.e)
 		}
@@ -351,6 +360,9 @@ func TestMultiLineString(t *testing.T) {
 	}
 
 	fn := func(t *testing.T, tc tc) {
+		if d := Diff(geom.MultiLineString(tc.ml1), geom.MultiLineString(tc.ml2)); tc.e != (d == "") {
+			t.Errorf("Diff, expected equal %v got %q", tc.e, d)
+		}
 		if tc.e != MultiLineEqual(tc.ml1, tc.ml2) {
 			t.Errorf("MultiLineString equal, expected %v got %v", tc.e, !tc.e)
 		}
@@ -458,6 +470,10 @@ func TestPolygon(t *testing.T) {
 
 	fn := func(t *testing.T, tc tc) {
 		g1, g2 := geom.Polygon(tc.ply1), geom.Polygon(tc.ply2)
+		// before PolygonEqual, which sorts the rings
+		if d := Diff(g1, g2); tc.e != (d == "") {
+			t.Errorf("Diff, expected equal %v got %q", tc.e, d)
+		}
 		if tc.e != PolygonEqual(tc.ply1, tc.ply2) {
 			t.Errorf("polygons equal, expected %v got %v", tc.e, !tc.e)
 		}
@@ -574,6 +590,9 @@ func TestMultiPolygon(t *testing.T) {
 
 	fn := func(t *testing.T, tc tc) {
 		g1, g2 := geom.MultiPolygon(tc.mp1), geom.MultiPolygon(tc.mp2)
+		if d := Diff(g1, g2); tc.e != (d == "") {
+			t.Errorf("Diff, expected equal %v got %q", tc.e, d)
+		}
 		if tc.e != MultiPolygonerEqual(g1, g2) {
 			t.Errorf("polygoner equal, expected %v got %v", tc.e, !tc.e)
 		}
@@ -746,6 +765,9 @@ func TestCollection(t *testing.T) {
 	}
 
 	fn := func(t *testing.T, tc tcase) {
+		if d := Diff(tc.cl1, tc.cl2); tc.e != (d == "") {
+			t.Errorf("Diff, expected equal %v got %q", tc.e, d)
+		}
 		if tc.e != CollectionerEqual(tc.cl1, tc.cl2) {
 			t.Errorf("polygoner equal, expected %v got %v", tc.e, !tc.e)
 		}
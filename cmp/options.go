@@ -0,0 +1,475 @@
+package cmp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// Options configures how Options.GeometryEqual and Options.Diff compare
+// geometries.
+//
+// By default the geometries are compared by their structure the same way
+// as GeometryEqual: they have to be the same kind of geometry, with the same
+// number of parts and the same points. The points of MultiPoints, the line
+// strings of MultiLineStrings, the holes of Polygons and the polygons of
+// MultiPolygons may be in any order, and line strings and rings may start
+// at any of their points. Rings may or may not repeat the first point at
+// the end. The geometries of Collections are compared in order.
+type Options struct {
+	// Tolerance is the difference allowed between two coordinates that are
+	// the same. If it is zero TOLERANCE is used.
+	Tolerance float64
+
+	// Ordered compares the parts of multi-geometries and the holes of
+	// Polygons in order, and the points of line strings from the first
+	// one.
+	Ordered bool
+
+	// Topological compares the sets of points covered by the geometries
+	// instead of their structure, so that extra vertices along a line,
+	// the direction of lines and rings, or how the geometry is split into
+	// parts do not matter. A Polygon and a MultiPolygon, or a LineString
+	// and a MultiLineString, can be equal. Polygons are compared by their
+	// boundaries, which is only correct for valid polygons. The geometries
+	// of Collections are still compared one to one.
+	Topological bool
+}
+
+// Diff returns a description of the first difference between the two
+// geometries using the default Options, or an empty string if they are
+// equal.
+func Diff(g1, g2 geom.Geometry) string { return Options{}.Diff(g1, g2) }
+
+// GeometryEqual returns weather the two geometries are equal.
+func (o Options) GeometryEqual(g1, g2 geom.Geometry) bool { return o.Diff(g1, g2) == "" }
+
+// Diff returns a description of the first difference between the two
+// geometries, or an empty string if they are equal.
+func (o Options) Diff(g1, g2 geom.Geometry) string {
+	if o.Topological {
+		return o.topologicalDiff("geometry", g1, g2)
+	}
+	return o.diff("geometry", g1, g2)
+}
+
+func (o Options) tolerance() float64 {
+	if o.Tolerance == 0 {
+		return TOLERANCE
+	}
+	return o.Tolerance
+}
+
+func (o Options) pointEqual(p1, p2 [2]float64) bool {
+	tol := o.tolerance()
+	return Float64(p1[0], p2[0], tol) && Float64(p1[1], p2[1], tol)
+}
+
+// kindOf returns the name of the kind of the geometry, checking the
+// interfaces in the same order as GeometryEqual.
+func kindOf(g geom.Geometry) string {
+	switch g.(type) {
+	case geom.Pointer:
+		return "point"
+	case geom.MultiPointer:
+		return "multipoint"
+	case geom.LineStringer:
+		return "linestring"
+	case geom.MultiLineStringer:
+		return "multilinestring"
+	case geom.Polygoner:
+		return "polygon"
+	case geom.MultiPolygoner:
+		return "multipolygon"
+	case geom.Collectioner:
+		return "collection"
+	default:
+		return fmt.Sprintf("unknown geometry %T", g)
+	}
+}
+
+// partsDiff compares the parts from start to n1 and n2 using diff, in order
+// if ordered is true. Otherwise each part of the first has to be matched to
+// a different equal part of the second. With a tolerance a part can be
+// equal to more than one other part, so the matching is found with
+// augmenting paths instead of taking the first equal part.
+func (o Options) partsDiff(path, what string, start, n1, n2 int, ordered bool, diff func(path string, i, j int) string) string {
+	if n1 != n2 {
+		return fmt.Sprintf("%s: %d %s != %d %s", path, n1-start, what, n2-start, what)
+	}
+	if ordered {
+		for i := start; i < n1; i++ {
+			if d := diff(fmt.Sprintf("%s[%d]", path, i), i, i); d != "" {
+				return d
+			}
+		}
+		return ""
+	}
+
+	n := n1 - start
+	equal := make([][]bool, n)
+	for i := range equal {
+		equal[i] = make([]bool, n)
+		ipath := fmt.Sprintf("%s[%d]", path, i+start)
+		for j := range equal[i] {
+			equal[i][j] = diff(ipath, i+start, j+start) == ""
+		}
+	}
+	// match[j] is the part of the first matched to part j of the second.
+	match := make([]int, n)
+	for j := range match {
+		match[j] = -1
+	}
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for j := range equal[i] {
+			if !equal[i][j] || seen[j] {
+				continue
+			}
+			seen[j] = true
+			if match[j] == -1 || augment(match[j], seen) {
+				match[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if !augment(i, make([]bool, n)) {
+			return fmt.Sprintf("%s[%d]: no match in the second geometry", path, i+start)
+		}
+	}
+	return ""
+}
+
+// pointsDiff compares the points in order.
+func (o Options) pointsDiff(path string, pts1, pts2 [][2]float64) string {
+	if len(pts1) != len(pts2) {
+		return fmt.Sprintf("%s: %d points != %d points", path, len(pts1), len(pts2))
+	}
+	for i := range pts1 {
+		if !o.pointEqual(pts1[i], pts2[i]) {
+			return fmt.Sprintf("%s[%d]: %v != %v", path, i, pts1[i], pts2[i])
+		}
+	}
+	return ""
+}
+
+// openRing returns the ring without the closing point, if there is one.
+func openRing(ring [][2]float64) [][2]float64 {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		return ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// ringDiff compares the rings, which may start at different points.
+func (o Options) ringDiff(path string, r1, r2 [][2]float64) string {
+	return o.rotatedDiff(path, openRing(r1), openRing(r2))
+}
+
+// lineDiff compares the line strings, which may start at different points
+// unless the comparison is ordered.
+func (o Options) lineDiff(path string, l1, l2 [][2]float64) string {
+	if o.Ordered {
+		return o.pointsDiff(path, l1, l2)
+	}
+	return o.rotatedDiff(path, l1, l2)
+}
+
+// rotatedDiff compares the points of r1 to those of r2 starting at any of
+// its points.
+func (o Options) rotatedDiff(path string, r1, r2 [][2]float64) string {
+	if len(r1) != len(r2) {
+		return fmt.Sprintf("%s: %d points != %d points", path, len(r1), len(r2))
+	}
+	if len(r1) == 0 {
+		return ""
+	}
+	first := -1
+	for k := range r2 {
+		if !o.pointEqual(r1[0], r2[k]) {
+			continue
+		}
+		rotated := append(append([][2]float64{}, r2[k:]...), r2[:k]...)
+		d := o.pointsDiff(path, r1, rotated)
+		if d == "" {
+			return ""
+		}
+		if first == -1 {
+			first = k
+		}
+	}
+	if first == -1 {
+		return fmt.Sprintf("%s[0]: %v is not in the second ring", path, r1[0])
+	}
+	// explain the difference from the first possible start.
+	return o.pointsDiff(path, r1, append(append([][2]float64{}, r2[first:]...), r2[:first]...))
+}
+
+func (o Options) polygonDiff(path string, p1, p2 [][][2]float64) string {
+	if len(p1) != len(p2) {
+		return fmt.Sprintf("%s: %d rings != %d rings", path, len(p1), len(p2))
+	}
+	if len(p1) == 0 {
+		return ""
+	}
+	if d := o.ringDiff(path+".rings[0]", p1[0], p2[0]); d != "" {
+		return d
+	}
+	return o.partsDiff(path+".rings", "holes", 1, len(p1), len(p2), o.Ordered, func(path string, i, j int) string {
+		return o.ringDiff(path, p1[i], p2[j])
+	})
+}
+
+// diff compares the structure of the geometries.
+func (o Options) diff(path string, g1, g2 geom.Geometry) string {
+	if k1, k2 := kindOf(g1), kindOf(g2); k1 != k2 {
+		return fmt.Sprintf("%s: %s != %s", path, k1, k2)
+	}
+	switch gg1 := g1.(type) {
+
+	case geom.Pointer:
+		if p1, p2 := gg1.XY(), g2.(geom.Pointer).XY(); !o.pointEqual(p1, p2) {
+			return fmt.Sprintf("%s: %v != %v", path, p1, p2)
+		}
+		return ""
+
+	case geom.MultiPointer:
+		pts1, pts2 := gg1.Points(), g2.(geom.MultiPointer).Points()
+		return o.partsDiff(path+".points", "points", 0, len(pts1), len(pts2), o.Ordered, func(path string, i, j int) string {
+			if !o.pointEqual(pts1[i], pts2[j]) {
+				return fmt.Sprintf("%s: %v != %v", path, pts1[i], pts2[j])
+			}
+			return ""
+		})
+
+	case geom.LineStringer:
+		return o.lineDiff(path+".points", gg1.Verticies(), g2.(geom.LineStringer).Verticies())
+
+	case geom.MultiLineStringer:
+		lss1, lss2 := gg1.LineStrings(), g2.(geom.MultiLineStringer).LineStrings()
+		return o.partsDiff(path+".linestrings", "line strings", 0, len(lss1), len(lss2), o.Ordered, func(path string, i, j int) string {
+			return o.lineDiff(path+".points", lss1[i], lss2[j])
+		})
+
+	case geom.Polygoner:
+		return o.polygonDiff(path, gg1.LinearRings(), g2.(geom.Polygoner).LinearRings())
+
+	case geom.MultiPolygoner:
+		p1, p2 := gg1.Polygons(), g2.(geom.MultiPolygoner).Polygons()
+		return o.partsDiff(path+".polygons", "polygons", 0, len(p1), len(p2), o.Ordered, func(path string, i, j int) string {
+			return o.polygonDiff(path, p1[i], p2[j])
+		})
+
+	case geom.Collectioner:
+		geos1, geos2 := gg1.Geometries(), g2.(geom.Collectioner).Geometries()
+		return o.partsDiff(path+".geometries", "geometries", 0, len(geos1), len(geos2), true, func(path string, i, j int) string {
+			return o.diff(path, geos1[i], geos2[j])
+		})
+
+	default:
+		return fmt.Sprintf("%s: %s", path, kindOf(g1))
+	}
+}
+
+// pointSet is a geometry broken down to the points, or segments, that
+// make it up.
+type pointSet struct {
+	// 0 for points, 1 for lines and 2 for areas; -1 if empty.
+	dim  int
+	pts  [][2]float64
+	segs [][2][2]float64
+}
+
+func (ps *pointSet) addLine(pts [][2]float64, isClosed bool) {
+	if len(pts) == 0 {
+		return
+	}
+	if len(pts) == 1 {
+		ps.segs = append(ps.segs, [2][2]float64{pts[0], pts[0]})
+		return
+	}
+	for i := 1; i < len(pts); i++ {
+		ps.segs = append(ps.segs, [2][2]float64{pts[i-1], pts[i]})
+	}
+	if isClosed && pts[0] != pts[len(pts)-1] {
+		ps.segs = append(ps.segs, [2][2]float64{pts[len(pts)-1], pts[0]})
+	}
+}
+
+// newPointSet returns the point set of the geometry, or false if the
+// geometry is not known.
+func newPointSet(g geom.Geometry) (*pointSet, bool) {
+	ps := &pointSet{dim: -1}
+	switch gg := g.(type) {
+	case geom.Pointer:
+		ps.pts = [][2]float64{gg.XY()}
+	case geom.MultiPointer:
+		ps.pts = gg.Points()
+	case geom.LineStringer:
+		ps.addLine(gg.Verticies(), false)
+	case geom.MultiLineStringer:
+		for _, ls := range gg.LineStrings() {
+			ps.addLine(ls, false)
+		}
+	case geom.Polygoner:
+		for _, ring := range gg.LinearRings() {
+			ps.addLine(ring, true)
+		}
+		if len(ps.segs) > 0 {
+			ps.dim = 2
+		}
+	case geom.MultiPolygoner:
+		for _, plyg := range gg.Polygons() {
+			for _, ring := range plyg {
+				ps.addLine(ring, true)
+			}
+		}
+		if len(ps.segs) > 0 {
+			ps.dim = 2
+		}
+	default:
+		return nil, false
+	}
+	switch {
+	case ps.dim == 2:
+	case len(ps.pts) > 0:
+		ps.dim = 0
+	case len(ps.segs) > 0:
+		ps.dim = 1
+	}
+	return ps, true
+}
+
+func cross(a, b [2]float64) float64 { return a[0]*b[1] - a[1]*b[0] }
+
+func sub(a, b [2]float64) [2]float64 { return [2]float64{a[0] - b[0], a[1] - b[1]} }
+
+// distanceToSegment returns the distance from the point to the segment, and
+// the parameter of the closest point along the segment.
+func distanceToSegment(pt [2]float64, seg [2][2]float64) (d, t float64) {
+	r := sub(seg[1], seg[0])
+	if l2 := r[0]*r[0] + r[1]*r[1]; l2 > 0 {
+		v := sub(pt, seg[0])
+		t = math.Max(0, math.Min(1, (v[0]*r[0]+v[1]*r[1])/l2))
+	}
+	c := [2]float64{seg[0][0] + t*r[0], seg[0][1] + t*r[1]}
+	return math.Hypot(pt[0]-c[0], pt[1]-c[1]), t
+}
+
+func (o Options) onSegments(pt [2]float64, segs [][2][2]float64) bool {
+	for _, seg := range segs {
+		if d, _ := distanceToSegment(pt, seg); d < o.tolerance() {
+			return true
+		}
+	}
+	return false
+}
+
+// uncoveredPoint returns a point of the segments of a that is not on the
+// segments of b. Each segment of a is split where it meets b; every piece
+// is then either on b, or only touches b at its ends, so checking the ends
+// and middle of the pieces is enough.
+func (o Options) uncoveredPoint(a, b [][2][2]float64) ([2]float64, bool) {
+	for _, sa := range a {
+		ts := []float64{0, 1}
+		r := sub(sa[1], sa[0])
+		for _, sb := range b {
+			for _, pt := range sb {
+				if d, t := distanceToSegment(pt, sa); d < o.tolerance() {
+					ts = append(ts, t)
+				}
+			}
+			s := sub(sb[1], sb[0])
+			den := cross(r, s)
+			if den == 0 {
+				// parallel; overlaps are found by the end points.
+				continue
+			}
+			qp := sub(sb[0], sa[0])
+			t, u := cross(qp, s)/den, cross(qp, r)/den
+			if t >= 0 && t <= 1 && u >= 0 && u <= 1 {
+				ts = append(ts, t)
+			}
+		}
+		sort.Float64s(ts)
+		at := func(t float64) [2]float64 { return [2]float64{sa[0][0] + t*r[0], sa[0][1] + t*r[1]} }
+		for i := range ts {
+			if pt := at(ts[i]); !o.onSegments(pt, b) {
+				return pt, true
+			}
+			if i > 0 && ts[i] > ts[i-1] {
+				if pt := at((ts[i-1] + ts[i]) / 2); !o.onSegments(pt, b) {
+					return pt, true
+				}
+			}
+		}
+	}
+	return [2]float64{}, false
+}
+
+// uncoveredVertex returns a point of a that is not in b.
+func (o Options) uncoveredVertex(a, b [][2]float64) ([2]float64, bool) {
+LOOP:
+	for _, p1 := range a {
+		for _, p2 := range b {
+			if o.pointEqual(p1, p2) {
+				continue LOOP
+			}
+		}
+		return p1, true
+	}
+	return [2]float64{}, false
+}
+
+var dimNames = map[int]string{-1: "empty", 0: "points", 1: "lines", 2: "areas"}
+
+// topologicalDiff compares the point sets of the geometries.
+func (o Options) topologicalDiff(path string, g1, g2 geom.Geometry) string {
+	c1, ok1 := g1.(geom.Collectioner)
+	c2, ok2 := g2.(geom.Collectioner)
+	switch {
+	case ok1 && ok2:
+		geos1, geos2 := c1.Geometries(), c2.Geometries()
+		return o.partsDiff(path+".geometries", "geometries", 0, len(geos1), len(geos2), true, func(path string, i, j int) string {
+			return o.topologicalDiff(path, geos1[i], geos2[j])
+		})
+	case ok1 || ok2:
+		return fmt.Sprintf("%s: %s != %s", path, kindOf(g1), kindOf(g2))
+	}
+
+	for _, g := range []geom.Geometry{g1, g2} {
+		if _, ok := newPointSet(g); !ok {
+			return fmt.Sprintf("%s: %s", path, kindOf(g))
+		}
+	}
+	ps1, _ := newPointSet(g1)
+	ps2, _ := newPointSet(g2)
+	if ps1.dim != ps2.dim {
+		return fmt.Sprintf("%s: %s != %s", path, dimNames[ps1.dim], dimNames[ps2.dim])
+	}
+
+	var (
+		pt  [2]float64
+		ok  bool
+		msg = "%s: %v of the first geometry is not in the second"
+	)
+	if ps1.dim == 0 {
+		if pt, ok = o.uncoveredVertex(ps1.pts, ps2.pts); !ok {
+			pt, ok = o.uncoveredVertex(ps2.pts, ps1.pts)
+			msg = "%s: %v of the second geometry is not in the first"
+		}
+	} else {
+		if pt, ok = o.uncoveredPoint(ps1.segs, ps2.segs); !ok {
+			pt, ok = o.uncoveredPoint(ps2.segs, ps1.segs)
+			msg = "%s: %v of the second geometry is not in the first"
+		}
+	}
+	if ok {
+		return fmt.Sprintf(msg, path, pt)
+	}
+	return ""
+}
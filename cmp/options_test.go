@@ -0,0 +1,192 @@
+package cmp
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestOptionsDiff(t *testing.T) {
+	type tcase struct {
+		opts   Options
+		g1, g2 geom.Geometry
+		// the expected diff; empty if the geometries are equal
+		diff string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			diff := tc.opts.Diff(tc.g1, tc.g2)
+			if diff != tc.diff {
+				t.Errorf("diff, expected %q got %q", tc.diff, diff)
+			}
+			if eq := tc.opts.GeometryEqual(tc.g1, tc.g2); eq != (tc.diff == "") {
+				t.Errorf("equal, expected %v got %v", tc.diff == "", eq)
+			}
+		}
+	}
+
+	square := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	tests := map[string]tcase{
+		"point": {
+			g1: geom.Point{1, 2},
+			g2: &geom.Point{1, 2},
+		},
+		"point different": {
+			g1:   geom.Point{1, 2},
+			g2:   geom.Point{1, 2.1},
+			diff: "geometry: [1 2] != [1 2.1]",
+		},
+		"point tolerance": {
+			opts: Options{Tolerance: 0.5},
+			g1:   geom.Point{1, 2},
+			g2:   geom.Point{1, 2.1},
+		},
+		"kinds": {
+			g1:   geom.Point{1, 2},
+			g2:   geom.MultiPoint{{1, 2}},
+			diff: "geometry: point != multipoint",
+		},
+		"linestring": {
+			g1:   geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+			g2:   geom.LineString{{0, 0}, {1, 2}, {2, 0}},
+			diff: "geometry.points[1]: [1 1] != [1 2]",
+		},
+		"linestring start vertex": {
+			g1: geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+			g2: geom.LineString{{1, 1}, {2, 0}, {0, 0}},
+		},
+		"linestring start vertex ordered": {
+			opts: Options{Ordered: true},
+			g1:   geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+			g2:   geom.LineString{{1, 1}, {2, 0}, {0, 0}},
+			diff: "geometry.points[0]: [0 0] != [1 1]",
+		},
+		"multipoint order": {
+			g1: geom.MultiPoint{{0, 0}, {1, 1}},
+			g2: geom.MultiPoint{{1, 1}, {0, 0}},
+		},
+		"multipoint ordered": {
+			opts: Options{Ordered: true},
+			g1:   geom.MultiPoint{{0, 0}, {1, 1}},
+			g2:   geom.MultiPoint{{1, 1}, {0, 0}},
+			diff: "geometry.points[0]: [0 0] != [1 1]",
+		},
+		"multipoint tolerance matching": {
+			// (0,0) is also within the tolerance of (0.2,0), which
+			// (0.4,0) has to be matched to.
+			opts: Options{Tolerance: 0.3},
+			g1:   geom.MultiPoint{{0, 0}, {0.4, 0}},
+			g2:   geom.MultiPoint{{0.2, 0}, {-0.2, 0}},
+		},
+		"multipoint repeated": {
+			g1:   geom.MultiPoint{{0, 0}, {0, 0}},
+			g2:   geom.MultiPoint{{0, 0}, {1, 1}},
+			diff: "geometry.points[1]: no match in the second geometry",
+		},
+		"polygon start vertex": {
+			g1: geom.Polygon{square},
+			g2: geom.Polygon{{{10, 10}, {0, 10}, {0, 0}, {10, 0}, {10, 10}}},
+		},
+		"polygon collinear vertex": {
+			g1:   geom.Polygon{square},
+			g2:   geom.Polygon{{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			diff: "geometry.rings[0]: 4 points != 5 points",
+		},
+		"polygon collinear vertex topological": {
+			opts: Options{Topological: true},
+			g1:   geom.Polygon{square},
+			g2:   geom.Polygon{{{0, 10}, {10, 10}, {10, 0}, {5, 0}, {0, 0}}},
+		},
+		"polygon topological": {
+			opts: Options{Topological: true},
+			g1:   geom.Polygon{square},
+			g2:   geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 11}}},
+			diff: "geometry: [5 10] of the first geometry is not in the second",
+		},
+		"polygon holes": {
+			opts: Options{Ordered: true},
+			g1:   geom.Polygon{square, {{1, 1}, {1, 2}, {2, 2}}, {{5, 5}, {5, 6}, {6, 6}}},
+			g2:   geom.Polygon{square, {{5, 5}, {5, 6}, {6, 6}}, {{1, 1}, {1, 2}, {2, 2}}},
+			diff: "geometry.rings[1][0]: [1 1] is not in the second ring",
+		},
+		"polygon holes any order": {
+			g1: geom.Polygon{square, {{1, 1}, {1, 2}, {2, 2}}, {{5, 5}, {5, 6}, {6, 6}}},
+			g2: geom.Polygon{square, {{5, 5}, {5, 6}, {6, 6}}, {{1, 1}, {1, 2}, {2, 2}}},
+		},
+		"multipolygon ordered": {
+			opts: Options{Ordered: true},
+			g1:   geom.MultiPolygon{{square}, {{{20, 20}, {21, 20}, {21, 21}}}},
+			g2:   geom.MultiPolygon{{{{20, 20}, {21, 20}, {21, 21}}}, {square}},
+			diff: "geometry.polygons[0].rings[0]: 4 points != 3 points",
+		},
+		"multipolygon any order": {
+			g1: geom.MultiPolygon{{square}, {{{20, 20}, {21, 20}, {21, 21}}}},
+			g2: geom.MultiPolygon{{{{20, 20}, {21, 20}, {21, 21}}}, {square}},
+		},
+		"multipolygon any order no match": {
+			g1:   geom.MultiPolygon{{square}, {{{20, 20}, {21, 20}, {21, 21}}}},
+			g2:   geom.MultiPolygon{{{{20, 20}, {21, 20}, {21, 22}}}, {square}},
+			diff: "geometry.polygons[1]: no match in the second geometry",
+		},
+		"multipolygon and polygon topological": {
+			opts: Options{Topological: true},
+			g1:   geom.MultiPolygon{{square}},
+			g2:   geom.Polygon{square},
+		},
+		"multipolygon split topological": {
+			opts: Options{Topological: true},
+			g1:   geom.Polygon{square},
+			g2: geom.MultiPolygon{
+				{{{0, 0}, {5, 0}, {5, 10}, {0, 10}}},
+				{{{5, 0}, {10, 0}, {10, 10}, {5, 10}}},
+			},
+			diff: "geometry: [5 5] of the second geometry is not in the first",
+		},
+		"lines topological": {
+			opts: Options{Topological: true},
+			g1:   geom.LineString{{0, 0}, {10, 0}, {10, 10}},
+			g2:   geom.MultiLineString{{{10, 10}, {10, 0}}, {{0, 0}, {3, 0}, {10, 0}}},
+		},
+		"lines crossing topological": {
+			opts: Options{Topological: true},
+			g1:   geom.MultiLineString{{{0, 0}, {10, 10}}, {{0, 10}, {10, 0}}},
+			g2:   geom.MultiLineString{{{0, 0}, {5, 5}, {0, 10}}, {{10, 0}, {5, 5}, {10, 10}}},
+		},
+		"lines and area topological": {
+			opts: Options{Topological: true},
+			g1:   geom.LineString{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			g2:   geom.Polygon{square},
+			diff: "geometry: lines != areas",
+		},
+		"points topological": {
+			opts: Options{Topological: true},
+			g1:   geom.MultiPoint{{1, 1}, {2, 2}, {1, 1}},
+			g2:   geom.MultiPoint{{2, 2}, {1, 1}},
+		},
+		"collection": {
+			g1:   geom.Collection{geom.Point{1, 2}, geom.LineString{{0, 0}, {1, 1}}},
+			g2:   geom.Collection{geom.Point{1, 2}, geom.LineString{{0, 0}, {1, 2}}},
+			diff: "geometry.geometries[1].points[1]: [1 1] != [1 2]",
+		},
+		"collection order": {
+			g1:   geom.Collection{geom.Point{1, 2}, geom.LineString{{0, 0}, {1, 1}}},
+			g2:   geom.Collection{geom.LineString{{0, 0}, {1, 1}}, geom.Point{1, 2}},
+			diff: "geometry.geometries[0]: point != linestring",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if d := Diff(geom.Point{1, 2}, geom.Point{1, 2}); d != "" {
+		t.Errorf("diff, expected %q got %q", "", d)
+	}
+	expected := "geometry.points: 2 points != 1 points"
+	if d := Diff(geom.LineString{{1, 2}, {3, 4}}, geom.LineString{{1, 2}}); d != expected {
+		t.Errorf("diff, expected %q got %q", expected, d)
+	}
+}
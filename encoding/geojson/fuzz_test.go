@@ -0,0 +1,72 @@
+package geojson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+	"github.com/go-spatial/geom/internal/tcase"
+)
+
+// marshal marshals what Unmarshal gives; features are not geometries, so
+// they are marshaled themselves.
+func marshal(g geojson.Geometry) ([]byte, error) {
+	switch f := g.Geometry.(type) {
+	case geojson.Feature:
+		return json.Marshal(f)
+	case geojson.FeatureCollection:
+		return json.Marshal(f)
+	}
+	return json.Marshal(g)
+}
+
+/*
+FuzzUnmarshal checks that JSON that unmarshals, marshals to JSON that
+unmarshals and marshals to the same JSON. The corpus is seeded from the wkb
+test cases.
+*/
+func FuzzUnmarshal(f *testing.F) {
+	geos, err := tcase.Geometries(tcase.WKBTestdata())
+	if err != nil {
+		f.Fatalf("error getting test cases: %v", err)
+	}
+	for _, g := range geos {
+		data, err := json.Marshal(geojson.Geometry{Geometry: g})
+		if err != nil {
+			f.Fatalf("marshal error, expected nil got %v", err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`{"type":"Feature","geometry":null,"properties":{"name":"a"}}`))
+	f.Add([]byte(`{"type":"FeatureCollection","features":[{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[1,2]},"properties":null}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var geo geojson.Geometry
+		if err := json.Unmarshal(data, &geo); err != nil {
+			return
+		}
+		first, err := marshal(geo)
+		// a null geometry, as features can have, can not be marshaled
+		var unknown geom.ErrUnknownGeometry
+		if errors.As(err, &unknown) && unknown.Geom == nil {
+			return
+		}
+		if err != nil {
+			t.Fatalf("marshal error, expected nil got %v for %#v", err, geo)
+		}
+		var again geojson.Geometry
+		if err := json.Unmarshal(first, &again); err != nil {
+			t.Fatalf("unmarshal of %s, expected nil got %v", first, err)
+		}
+		second, err := marshal(again)
+		if err != nil {
+			t.Fatalf("marshal error, expected nil got %v for %#v", err, again)
+		}
+		if !bytes.Equal(first, second) {
+			t.Errorf("round trip, expected %s got %s", first, second)
+		}
+	})
+}
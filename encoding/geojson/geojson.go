@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/encoding"
+	"github.com/go-spatial/geom/windingorder"
 )
 
 type GeoJSONType string
@@ -25,6 +26,19 @@ type Geometry struct {
 	geom.Geometry
 }
 
+// MarshalRFC7946 returns the GeoJSON of the geometry with the rings of its
+// polygons oriented as RFC 7946 requires: exterior rings counter clockwise
+// and interior rings clockwise, in longitude/latitude. Geometry.MarshalJSON
+// writes the rings as they are. Feature and FeatureCollection have a
+// MarshalRFC7946 method as well.
+func MarshalRFC7946(g geom.Geometry) ([]byte, error) {
+	g, err := windingorder.Enforce(g, windingorder.RFC7946)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Geometry{Geometry: g})
+}
+
 func (geo Geometry) MarshalJSON() ([]byte, error) {
 	type coordinates struct {
 		Type   GeoJSONType `json:"type"`
@@ -32,7 +46,7 @@ func (geo Geometry) MarshalJSON() ([]byte, error) {
 	}
 	type collection struct {
 		Type       GeoJSONType `json:"type"`
-		Geometries []Geometry  `json:"geometries,omitempty"`
+		Geometries []Geometry  `json:"geometries"`
 	}
 
 	switch g := geo.Geometry.(type) {
@@ -119,6 +133,32 @@ type Feature struct {
 	Properties map[string]interface{} `json:"properties"`
 }
 
+// MarshalRFC7946 returns the GeoJSON of the feature with the rings of the
+// polygons of its geometry oriented as RFC 7946 requires, like the
+// MarshalRFC7946 function.
+func (f Feature) MarshalRFC7946() ([]byte, error) {
+	f, err := f.rfc7946()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(f)
+}
+
+// rfc7946 returns a copy of the feature with its geometry oriented as RFC
+// 7946 requires.
+func (f Feature) rfc7946() (Feature, error) {
+	if f.Geometry.Geometry == nil {
+		// null
+		return f, nil
+	}
+	g, err := windingorder.Enforce(f.Geometry.Geometry, windingorder.RFC7946)
+	if err != nil {
+		return f, err
+	}
+	f.Geometry = Geometry{g}
+	return f, nil
+}
+
 // featureCollectionType allows the GeoJSON type for Feature to be automatically set during json Marshalling
 // which avoids the user from accidentally setting the incorrect GeoJSON type.
 type featureCollectionType struct{}
@@ -133,6 +173,21 @@ type FeatureCollection struct {
 	Features []Feature             `json:"features"`
 }
 
+// MarshalRFC7946 returns the GeoJSON of the feature collection with the
+// rings of the polygons of its features oriented as RFC 7946 requires, like
+// the MarshalRFC7946 function.
+func (fc FeatureCollection) MarshalRFC7946() ([]byte, error) {
+	features := make([]Feature, len(fc.Features))
+	for i := range fc.Features {
+		var err error
+		if features[i], err = fc.Features[i].rfc7946(); err != nil {
+			return nil, err
+		}
+	}
+	fc.Features = features
+	return json.Marshal(fc)
+}
+
 func closePolygon(p geom.Polygon) {
 	for i := range p {
 		if len(p[i]) == 0 {
@@ -147,69 +202,83 @@ func closePolygon(p geom.Polygon) {
 	}
 }
 
+// member unmarshals the key of the object m into v; the key is required.
+func member(m map[string]json.RawMessage, key string, v interface{}, b []byte) error {
+	raw, ok := m[key]
+	if !ok {
+		return encoding.ErrInvalidGeoJSON{GJSON: b}
+	}
+	return json.Unmarshal(raw, v)
+}
+
 func (geo *Geometry) UnmarshalJSON(b []byte) error {
-	var geojsonMap map[string]*json.RawMessage
+	var geojsonMap map[string]json.RawMessage
 	if err := json.Unmarshal(b, &geojsonMap); err != nil {
 		return err
 	}
+	if geojsonMap == nil {
+		// null, as the geometry of a feature can be
+		geo.Geometry = nil
+		return nil
+	}
 
 	var geomType GeoJSONType
-	if err := json.Unmarshal(*geojsonMap["type"], &geomType); err != nil {
+	if err := member(geojsonMap, "type", &geomType, b); err != nil {
 		return err
 	}
 	switch geomType {
 	case PointType:
 		var pt geom.Point
-		if err := json.Unmarshal(*geojsonMap["coordinates"], &pt); err != nil {
+		if err := member(geojsonMap, "coordinates", &pt, b); err != nil {
 			return err
 		}
 		geo.Geometry = pt
 		return nil
 	case PolygonType:
 		var poly geom.Polygon
-		if err := json.Unmarshal(*geojsonMap["coordinates"], &poly); err != nil {
+		if err := member(geojsonMap, "coordinates", &poly, b); err != nil {
 			return err
 		}
 		geo.Geometry = poly
 		return nil
 	case LineStringType:
 		var ls geom.LineString
-		if err := json.Unmarshal(*geojsonMap["coordinates"], &ls); err != nil {
+		if err := member(geojsonMap, "coordinates", &ls, b); err != nil {
 			return err
 		}
 		geo.Geometry = ls
 		return nil
 	case MultiPointType:
 		var mp geom.MultiPoint
-		if err := json.Unmarshal(*geojsonMap["coordinates"], &mp); err != nil {
+		if err := member(geojsonMap, "coordinates", &mp, b); err != nil {
 			return err
 		}
 		geo.Geometry = mp
 		return nil
 	case MultiLineStringType:
 		var ml geom.MultiLineString
-		if err := json.Unmarshal(*geojsonMap["coordinates"], &ml); err != nil {
+		if err := member(geojsonMap, "coordinates", &ml, b); err != nil {
 			return err
 		}
 		geo.Geometry = ml
 		return nil
 	case MultiPolygonType:
 		var mp geom.MultiPolygon
-		if err := json.Unmarshal(*geojsonMap["coordinates"], &mp); err != nil {
+		if err := member(geojsonMap, "coordinates", &mp, b); err != nil {
 			return err
 		}
 		geo.Geometry = mp
 		return nil
 	case GeometryCollectionType:
 		gc := geom.Collection{}
-		var rawMessageForGeometries []*json.RawMessage
-		if err := json.Unmarshal(*geojsonMap["geometries"], &rawMessageForGeometries); err != nil {
+		var rawMessageForGeometries []json.RawMessage
+		if err := member(geojsonMap, "geometries", &rawMessageForGeometries, b); err != nil {
 			return err
 		}
 		geoms := make([]geom.Geometry, len(rawMessageForGeometries))
 		for i, v := range rawMessageForGeometries {
 			var g Geometry
-			if err := json.Unmarshal(*v, &g); err != nil {
+			if err := json.Unmarshal(v, &g); err != nil {
 				return err
 			}
 			geoms[i] = g.Geometry
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding"
 	"github.com/go-spatial/geom/encoding/geojson"
 )
 
@@ -104,6 +105,10 @@ func TestFeatureMarshalJSON(t *testing.T) {
 			},
 			expected: []byte(`{"type":"Feature","geometry":{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[12.2,17.7]},{"type":"MultiPoint","coordinates":[[12.2,17.7],[13.3,18.8]]},{"type":"LineString","coordinates":[[3.2,4.3],[5.4,6.5],[7.6,8.7],[9.8,10.9]]}]},"properties":null}`),
 		},
+		"empty geometry collection": {
+			geom:     geom.Collection{},
+			expected: []byte(`{"type":"Feature","geometry":{"type":"GeometryCollection","geometries":[]},"properties":null}`),
+		},
 		"nil geom": {
 			geom: nil,
 			expectedErr: json.MarshalerError{
@@ -119,6 +124,100 @@ func TestFeatureMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestMarshalRFC7946(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		expected string
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		output, err := geojson.MarshalRFC7946(tc.geom)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if string(output) != tc.expected {
+			t.Errorf("expected %v got %v", tc.expected, string(output))
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom:     geom.Point{12.2, 17.7},
+			expected: `{"type":"Point","coordinates":[12.2,17.7]}`,
+		},
+		"polygon": {
+			// clockwise exterior and counter clockwise hole, in lon/lat
+			geom: geom.Polygon{
+				{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+				{{2, 2}, {4, 2}, {4, 4}, {2, 4}},
+			},
+			expected: `{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[2,4],[4,4],[4,2],[2,2]]]}`,
+		},
+		"multi polygon": {
+			geom: geom.MultiPolygon{
+				{{{0, 0}, {10, 0}, {10, 10}}},
+				{{{20, 20}, {20, 30}, {30, 30}, {20, 20}}},
+			},
+			expected: `{"type":"MultiPolygon","coordinates":[[[[0,0],[10,0],[10,10],[0,0]]],[[[20,20],[30,30],[20,30],[20,20]]]]}`,
+		},
+		"geometry collection": {
+			geom: geom.Collection{
+				geom.Polygon{{{0, 0}, {0, 10}, {10, 10}}},
+			},
+			expected: `{"type":"GeometryCollection","geometries":[{"type":"Polygon","coordinates":[[[0,0],[10,10],[0,10],[0,0]]]}]}`,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+
+	// the caller's polygon is not changed
+	plyg := geom.Polygon{{{0, 0}, {0, 10}, {10, 10}}}
+	if _, err := geojson.MarshalRFC7946(plyg); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if expected := (geom.Polygon{{{0, 0}, {0, 10}, {10, 10}}}); !reflect.DeepEqual(expected, plyg) {
+		t.Errorf("polygon changed, expected %v got %v", expected, plyg)
+	}
+}
+
+func TestFeatureMarshalRFC7946(t *testing.T) {
+	// clockwise in lon/lat
+	plyg := geom.Polygon{{{0, 0}, {0, 10}, {10, 10}}}
+	id := uint64(1)
+	feature := geojson.Feature{
+		ID:         &id,
+		Geometry:   geojson.Geometry{Geometry: plyg},
+		Properties: map[string]interface{}{"a": 1},
+	}
+	expectedFeature := `{"type":"Feature","id":1,"geometry":{"type":"Polygon","coordinates":[[[0,0],[10,10],[0,10],[0,0]]]},"properties":{"a":1}}`
+
+	output, err := feature.MarshalRFC7946()
+	if err != nil {
+		t.Fatalf("feature error, expected nil got %v", err)
+	}
+	if string(output) != expectedFeature {
+		t.Errorf("feature, expected %v got %v", expectedFeature, string(output))
+	}
+
+	fc := geojson.FeatureCollection{Features: []geojson.Feature{feature, feature}}
+	expected := `{"type":"FeatureCollection","features":[` + expectedFeature + "," + expectedFeature + `]}`
+	output, err = fc.MarshalRFC7946()
+	if err != nil {
+		t.Fatalf("feature collection error, expected nil got %v", err)
+	}
+	if string(output) != expected {
+		t.Errorf("feature collection, expected %v got %v", expected, string(output))
+	}
+
+	// the caller's features are not changed
+	if expected := (geom.Polygon{{{0, 0}, {0, 10}, {10, 10}}}); !reflect.DeepEqual(expected, fc.Features[0].Geometry.Geometry) {
+		t.Errorf("polygon changed, expected %v got %v", expected, fc.Features[0].Geometry.Geometry)
+	}
+}
+
 func TestUnmarshalJSON(t *testing.T) {
 	type tcase struct {
 		gjson       []byte
@@ -201,6 +300,14 @@ func TestUnmarshalJSON(t *testing.T) {
 				Geometry: geojson.Geometry{geom.Point{12.2, 17.7}},
 			},
 		},
+		"feature null geometry": {
+			gjson:    []byte(`{"type":"Feature","geometry":null,"properties":null}`),
+			expected: geojson.Feature{},
+		},
+		"empty geometry collection": {
+			gjson:    []byte(`{"type":"GeometryCollection","geometries":[]}`),
+			expected: geom.Collection{},
+		},
 		"feature collection": {
 			gjson: []byte(`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[12.2,17.7]},"properties":null}]}`),
 			expected: geojson.FeatureCollection{
@@ -229,3 +336,34 @@ func TestUnmarshalJSON(t *testing.T) {
 		t.Run(name, func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	type tcase struct {
+		gjson []byte
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		var output geojson.Geometry
+		err := json.Unmarshal(tc.gjson, &output)
+		if _, ok := err.(encoding.ErrInvalidGeoJSON); !ok {
+			t.Errorf("error, expected %T got %v", encoding.ErrInvalidGeoJSON{}, err)
+		}
+	}
+
+	tests := map[string]tcase{
+		"no type": {
+			gjson: []byte(`{"coordinates":[12.2,17.7]}`),
+		},
+		"no coordinates": {
+			gjson: []byte(`{"type":"Point"}`),
+		},
+		"no geometries": {
+			gjson: []byte(`{"type":"GeometryCollection"}`),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
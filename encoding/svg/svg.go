@@ -0,0 +1,555 @@
+/*
+Package svg renders geometries as SVG documents, to see what is going on when
+debugging and for reports.
+
+A Document is made of layers, each an SVG group with its own style, which are
+drawn in the order they were added. The view box is fitted to the extent of
+everything that has been added, unless an extent is set. The y axis points
+up, as it does for the geometries.
+*/
+package svg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+const (
+	// DefaultWidth is the width, in pixels, of a document without one.
+	DefaultWidth = 512
+	// DefaultMargin is the margin around the fitted extent, as a fraction of
+	// the larger side of the extent.
+	DefaultMargin = 0.05
+)
+
+/*
+Style is the presentation of an element. Zero fields are not set, so they
+are inherited from the layer, and from the defaults for the layer; no fill,
+a black stroke of a pixel, points with a radius of 3 pixels and 12 pixel
+text.
+
+Sizes are in pixels, and do not change with the scale of the document.
+*/
+type Style struct {
+	Fill        string
+	FillOpacity float64
+	Stroke      string
+	StrokeWidth float64
+	PointRadius float64
+	FontSize    float64
+}
+
+// DefaultStyle is the style of a layer with no style.
+var DefaultStyle = Style{
+	Fill:        "none",
+	Stroke:      "black",
+	StrokeWidth: 1,
+	PointRadius: 3,
+	FontSize:    12,
+}
+
+// merge returns s with the zero fields set from base.
+func (s Style) merge(base Style) Style {
+	if s.Fill == "" {
+		s.Fill = base.Fill
+	}
+	if s.FillOpacity == 0 {
+		s.FillOpacity = base.FillOpacity
+	}
+	if s.Stroke == "" {
+		s.Stroke = base.Stroke
+	}
+	if s.StrokeWidth == 0 {
+		s.StrokeWidth = base.StrokeWidth
+	}
+	if s.PointRadius == 0 {
+		s.PointRadius = base.PointRadius
+	}
+	if s.FontSize == 0 {
+		s.FontSize = base.FontSize
+	}
+	return s
+}
+
+// attrs returns the SVG presentation attributes of the set fields of s,
+// with the sizes in pixels of scale units.
+func (s Style) attrs(scale float64) string {
+	var b strings.Builder
+	if s.Fill != "" {
+		fmt.Fprintf(&b, ` fill="%v"`, escape(s.Fill))
+	}
+	if s.FillOpacity != 0 {
+		fmt.Fprintf(&b, ` fill-opacity="%v"`, formatFloat(s.FillOpacity))
+	}
+	if s.Stroke != "" {
+		fmt.Fprintf(&b, ` stroke="%v"`, escape(s.Stroke))
+	}
+	if s.StrokeWidth != 0 {
+		fmt.Fprintf(&b, ` stroke-width="%v"`, formatFloat(s.StrokeWidth*scale))
+	}
+	return b.String()
+}
+
+/*
+LabelStyle returns the style of a triangle with the label. It is the
+default for Layer.AddLabels; inside triangles are green, outside triangles
+are red and others are grey.
+*/
+func LabelStyle(l planar.Label) Style {
+	switch l {
+	case planar.Inside:
+		return Style{Fill: "green", FillOpacity: 0.4}
+	case planar.Outside:
+		return Style{Fill: "red", FillOpacity: 0.4}
+	default:
+		return Style{Fill: "grey", FillOpacity: 0.4}
+	}
+}
+
+// item is an element of a layer; a geometry or, if text is set, a label.
+type item struct {
+	geo   geom.Geometry
+	text  string
+	style Style
+}
+
+/*
+Layer is a group of elements of a document, drawn in the order they were
+added.
+*/
+type Layer struct {
+	Name  string
+	Style Style
+	// StyleFor, if set, is called for each geometry added to the layer. The
+	// returned style takes precedence over the style of the layer.
+	StyleFor func(g geom.Geometry) Style
+	// LabelStyle, if set, replaces the package LabelStyle in AddLabels.
+	LabelStyle func(l planar.Label) Style
+
+	items  []item
+	extent *geom.Extent
+}
+
+/*
+add adds an item to the layer, and grows the extent of the layer.
+
+If l is nil a panic will occur.
+*/
+func (l *Layer) add(it item) error {
+	e, err := extentOf(it.geo)
+	if err != nil {
+		return err
+	}
+	if e != nil {
+		if l.extent == nil {
+			l.extent = e
+		} else {
+			l.extent.Add(e)
+		}
+	}
+	l.items = append(l.items, it)
+	return nil
+}
+
+/*
+AddGeometry adds geometries to the layer. The geom.Geometry interfaces are
+supported, as are geom.Line, geom.Triangle and geom.Extent. An unknown
+geometry returns a geom.ErrUnknownGeometry, and nothing after it is added.
+
+If l is nil a panic will occur.
+*/
+func (l *Layer) AddGeometry(geos ...geom.Geometry) error {
+	for _, g := range geos {
+		it := item{geo: g}
+		if l.StyleFor != nil {
+			it.style = l.StyleFor(g)
+		}
+		if err := l.add(it); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+AddTriangles adds the triangles to the layer.
+
+If l is nil a panic will occur.
+*/
+func (l *Layer) AddTriangles(triangles []geom.Triangle) error {
+	for _, t := range triangles {
+		if err := l.AddGeometry(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+AddText adds a label centred on pt.
+
+If l is nil a panic will occur.
+*/
+func (l *Layer) AddText(pt [2]float64, text string, style Style) error {
+	return l.add(item{geo: geom.Point(pt), text: text, style: style})
+}
+
+/*
+AddLabels adds the triangles to the layer, filled by the label hm gives the
+centre of each, as makevalid labels them. If withText is true the label is
+also written in the triangle.
+
+If l is nil a panic will occur.
+*/
+func (l *Layer) AddLabels(hm planar.HitMapper, triangles []geom.Triangle, withText bool) error {
+	labelStyle := LabelStyle
+	if l.LabelStyle != nil {
+		labelStyle = l.LabelStyle
+	}
+	for _, t := range triangles {
+		label := hm.LabelFor(t.Center())
+		if err := l.add(item{geo: t, style: labelStyle(label)}); err != nil {
+			return err
+		}
+		if withText {
+			if err := l.AddText(t.Center(), label.String(), Style{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+AddSubdivision adds the edges of the subdivision to the layer, and the
+frame edges if includeFrame is true.
+
+If l or subdiv is nil a panic will occur.
+*/
+func (l *Layer) AddSubdivision(subdiv *quadedge.QuadEdgeSubdivision, includeFrame bool) error {
+	edges := subdiv.GetPrimaryEdges(includeFrame)
+	ml := make(geom.MultiLineString, len(edges))
+	for i, e := range edges {
+		ml[i] = [][2]float64{e.Orig(), e.Dest()}
+	}
+	return l.AddGeometry(ml)
+}
+
+/*
+Document is an SVG document of layers.
+*/
+type Document struct {
+	// Width and Height are the size of the document in pixels. If Width is
+	// zero, DefaultWidth is used, and if Height is zero it is set from the
+	// width and the shape of the extent.
+	Width, Height float64
+	// Extent, if set, is the part of the plane that is shown, grown like
+	// the fitted extent if it has no span. Otherwise the extent of the
+	// layers is used, with Margin around it.
+	Extent *geom.Extent
+	// Margin is a fraction of the larger side of the fitted extent.
+	// DefaultMargin is used if it is zero, and there is no margin if it is
+	// negative.
+	Margin float64
+
+	layers []*Layer
+}
+
+/*
+Layer returns the layer with the name, which is added to the top of the
+document with the style if there is no such layer.
+
+If doc is nil a panic will occur.
+*/
+func (doc *Document) Layer(name string, style Style) *Layer {
+	for _, l := range doc.layers {
+		if l.Name == name {
+			return l
+		}
+	}
+	l := &Layer{Name: name, Style: style}
+	doc.layers = append(doc.layers, l)
+	return l
+}
+
+/*
+Layers returns the layers of the document from the bottom up.
+
+If doc is nil a panic will occur.
+*/
+func (doc *Document) Layers() []*Layer {
+	return doc.layers
+}
+
+/*
+viewExtent returns the extent shown by the document, which is never empty.
+
+If doc is nil a panic will occur.
+*/
+func (doc *Document) viewExtent() geom.Extent {
+	if doc.Extent != nil {
+		return withRoom(*doc.Extent, math.Max(doc.Extent.XSpan(), doc.Extent.YSpan()))
+	}
+	var e *geom.Extent
+	for _, l := range doc.layers {
+		if l.extent == nil {
+			continue
+		}
+		if e == nil {
+			e = l.extent.Clone()
+		} else {
+			e.Add(l.extent)
+		}
+	}
+	if e == nil {
+		return geom.Extent{0, 0, 1, 1}
+	}
+	size := math.Max(e.XSpan(), e.YSpan())
+	if size == 0 {
+		size = 1
+	}
+	margin := doc.Margin
+	if margin == 0 {
+		margin = DefaultMargin
+	}
+	if margin > 0 {
+		e = e.ExpandBy(size * margin)
+	}
+	return withRoom(*e, size)
+}
+
+// withRoom returns the extent grown by size along the sides that have no
+// span, so a point or a straight line has some room; one if size is zero.
+func withRoom(e geom.Extent, size float64) geom.Extent {
+	if size == 0 {
+		size = 1
+	}
+	if e.XSpan() == 0 {
+		e[0], e[2] = e[0]-size/2, e[2]+size/2
+	}
+	if e.YSpan() == 0 {
+		e[1], e[3] = e[1]-size/2, e[3]+size/2
+	}
+	return e
+}
+
+/*
+WriteTo writes the document to w.
+
+If doc is nil a panic will occur.
+*/
+func (doc *Document) WriteTo(w io.Writer) (int64, error) {
+	e := doc.viewExtent()
+	width := doc.Width
+	if width == 0 {
+		width = DefaultWidth
+	}
+	height := doc.Height
+	if height == 0 {
+		height = math.Max(math.Round(width*e.YSpan()/e.XSpan()), 1)
+	}
+	// the size of a pixel in the units of the geometries
+	scale := math.Max(e.XSpan()/width, e.YSpan()/height)
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	fmt.Fprintf(cw, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="%v %v %v %v">`+"\n",
+		formatFloat(width), formatFloat(height),
+		formatFloat(e.MinX()), formatFloat(-e.MaxY()), formatFloat(e.XSpan()), formatFloat(e.YSpan()),
+	)
+	for _, l := range doc.layers {
+		style := l.Style.merge(DefaultStyle)
+		fmt.Fprintf(cw, `<g id="%v"%v>`+"\n", escape(l.Name), style.attrs(scale))
+		for _, it := range l.items {
+			writeItem(cw, it, style, scale)
+		}
+		fmt.Fprint(cw, "</g>\n")
+	}
+	fmt.Fprint(cw, "</svg>\n")
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.(*bufio.Writer).Flush()
+}
+
+/*
+Encode returns an SVG document of the geometry, with the default style.
+*/
+func Encode(g geom.Geometry) (string, error) {
+	var doc Document
+	if err := doc.Layer("geometry", Style{}).AddGeometry(g); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeItem writes the element of an item of a layer with the style.
+func writeItem(w io.Writer, it item, layer Style, scale float64) {
+	style := it.style.merge(layer)
+	attrs := it.style.attrs(scale)
+	if it.text != "" {
+		pt := it.geo.(geom.Point)
+		fmt.Fprintf(w, `<text x="%v" y="%v" font-size="%v" text-anchor="middle" dominant-baseline="middle" stroke="none" fill="%v">%v</text>`+"\n",
+			formatFloat(pt[0]), formatFloat(-pt[1]), formatFloat(style.FontSize*scale), escape(style.Stroke), escape(it.text))
+		return
+	}
+	writeGeometry(w, it.geo, attrs, style.PointRadius*scale)
+}
+
+// writeGeometry writes the elements of a geometry; points as circles of
+// radius r and everything else as paths.
+func writeGeometry(w io.Writer, g geom.Geometry, attrs string, r float64) {
+	switch g := g.(type) {
+	case geom.Line:
+		writePath(w, attrs, false, g[:])
+	case geom.Triangle:
+		writePath(w, attrs, true, g[:])
+	case geom.Extent:
+		writePath(w, attrs, true, g.Vertices())
+	case *geom.Extent:
+		if g != nil {
+			writePath(w, attrs, true, g.Vertices())
+		}
+	case geom.Pointer:
+		xy := g.XY()
+		fmt.Fprintf(w, `<circle cx="%v" cy="%v" r="%v"%v/>`+"\n", formatFloat(xy[0]), formatFloat(-xy[1]), formatFloat(r), attrs)
+	case geom.MultiPointer:
+		for _, pt := range g.Points() {
+			writeGeometry(w, geom.Point(pt), attrs, r)
+		}
+	case geom.LineStringer:
+		writePath(w, attrs, false, g.Verticies())
+	case geom.MultiLineStringer:
+		writePath(w, attrs, false, g.LineStrings()...)
+	case geom.Polygoner:
+		writePath(w, attrs, true, g.LinearRings()...)
+	case geom.MultiPolygoner:
+		var rings [][][2]float64
+		for _, p := range g.Polygons() {
+			rings = append(rings, p...)
+		}
+		writePath(w, attrs, true, rings...)
+	case geom.Collectioner:
+		for _, g := range g.Geometries() {
+			writeGeometry(w, g, attrs, r)
+		}
+	}
+}
+
+// writePath writes the lines as a path, closing each if closed is true.
+func writePath(w io.Writer, attrs string, closed bool, lines ...[][2]float64) {
+	var d []string
+	for _, l := range lines {
+		for i, pt := range l {
+			cmd := "L"
+			if i == 0 {
+				cmd = "M"
+			}
+			d = append(d, cmd+formatFloat(pt[0])+" "+formatFloat(-pt[1]))
+		}
+		if closed && len(l) > 0 {
+			d = append(d, "Z")
+		}
+	}
+	if len(d) == 0 {
+		return
+	}
+	fillRule := ""
+	if closed {
+		fillRule = ` fill-rule="evenodd"`
+	}
+	fmt.Fprintf(w, `<path d="%v"%v%v/>`+"\n", strings.Join(d, " "), fillRule, attrs)
+}
+
+/*
+extentOf returns the extent of the geometry, which is nil for an empty
+geometry, or geom.ErrUnknownGeometry if the geometry can not be drawn.
+*/
+func extentOf(g geom.Geometry) (*geom.Extent, error) {
+	switch g := g.(type) {
+	case geom.Line:
+		return geom.NewExtent(g[:]...), nil
+	case geom.Triangle:
+		return geom.NewExtent(g[:]...), nil
+	case geom.Extent:
+		return g.Clone(), nil
+	case *geom.Extent:
+		if g == nil {
+			return nil, nil
+		}
+		return g.Clone(), nil
+	case geom.Pointer, geom.MultiPointer, geom.LineStringer, geom.MultiLineStringer,
+		geom.Polygoner, geom.MultiPolygoner:
+		pts, err := geom.GetCoordinates(g)
+		if err != nil || len(pts) == 0 {
+			return nil, err
+		}
+		points := make([][2]float64, len(pts))
+		for i := range pts {
+			points[i] = pts[i]
+		}
+		return geom.NewExtent(points...), nil
+	case geom.Collectioner:
+		var e *geom.Extent
+		for _, g := range g.Geometries() {
+			ge, err := extentOf(g)
+			if err != nil {
+				return nil, err
+			}
+			if ge == nil {
+				continue
+			}
+			if e == nil {
+				e = ge
+			} else {
+				e.Add(ge)
+			}
+		}
+		return e, nil
+	}
+	return nil, geom.ErrUnknownGeometry{Geom: g}
+}
+
+func formatFloat(f float64) string {
+	if f == 0 {
+		// no negative zero
+		return "0"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// countWriter counts the bytes written, and keeps the first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,219 @@
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/makevalid/hitmap"
+	"github.com/go-spatial/geom/planar/triangulate"
+)
+
+func TestEncode(t *testing.T) {
+	type tcase struct {
+		geom geom.Geometry
+		// the elements of the geometry layer
+		expected string
+		err      error
+	}
+
+	const (
+		header = `<svg xmlns="http://www.w3.org/2000/svg" width="512" height="512" viewBox="-5 -105 110 110">` + "\n" +
+			`<g id="geometry" fill="none" stroke="black" stroke-width="0.21484375">` + "\n"
+		footer = "</g>\n</svg>\n"
+	)
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Encode(tc.geom)
+			if tc.err != nil {
+				if err == nil || err.Error() != tc.err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if expected := header + tc.expected + footer; got != expected {
+				t.Errorf("svg, expected\n%v\ngot\n%v", expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"multipoint": {
+			geom:     geom.MultiPoint{{0, 0}, {100, 100}},
+			expected: "<circle cx=\"0\" cy=\"0\" r=\"0.64453125\"/>\n<circle cx=\"100\" cy=\"-100\" r=\"0.64453125\"/>\n",
+		},
+		"linestring": {
+			geom:     geom.LineString{{0, 0}, {50, 100}, {100, 0}},
+			expected: "<path d=\"M0 0 L50 -100 L100 0\"/>\n",
+		},
+		"polygon": {
+			geom: geom.Polygon{
+				{{0, 0}, {100, 0}, {100, 100}, {0, 100}},
+				{{25, 25}, {25, 75}, {75, 75}, {75, 25}},
+			},
+			expected: "<path d=\"M0 0 L100 0 L100 -100 L0 -100 Z M25 -25 L25 -75 L75 -75 L75 -25 Z\" fill-rule=\"evenodd\"/>\n",
+		},
+		"collection": {
+			geom:     geom.Collection{geom.Line{{0, 0}, {100, 100}}, geom.Triangle{{0, 100}, {50, 50}, {100, 100}}},
+			expected: "<path d=\"M0 0 L100 -100\"/>\n<path d=\"M0 -100 L50 -50 L100 -100 Z\" fill-rule=\"evenodd\"/>\n",
+		},
+		"unknown": {
+			geom: 1,
+			err:  geom.ErrUnknownGeometry{Geom: 1},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc := Document{Width: 100, Margin: -1}
+
+	triangles := []geom.Triangle{
+		{{0, 0}, {10, 0}, {0, 10}},
+		{{10, 0}, {10, 10}, {0, 10}},
+	}
+	hm := hitmap.OrderedHM{hitmap.MustNew(nil, geom.Polygon{{{0, 0}, {10, 0}, {0, 10}}})}
+	if err := doc.Layer("labels", Style{}).AddLabels(hm, triangles, true); err != nil {
+		t.Fatalf("labels error, expected nil got %v", err)
+	}
+
+	builder := triangulate.NewDelaunayTriangulationBuilder(0)
+	builder.SetSites(geom.MultiPoint{{0, 0}, {10, 0}, {0, 10}, {10, 10}})
+	edges := doc.Layer("edges", Style{Stroke: "blue", StrokeWidth: 2})
+	edges.StyleFor = func(g geom.Geometry) Style {
+		return Style{Stroke: "red"}
+	}
+	if err := edges.AddSubdivision(builder.GetSubdivision(), false); err != nil {
+		t.Fatalf("subdivision error, expected nil got %v", err)
+	}
+	if err := doc.Layer("labels", Style{}).AddText([2]float64{5, 5}, "a < b", Style{FontSize: 10}); err != nil {
+		t.Fatalf("text error, expected nil got %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("write error, expected nil got %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("written, expected %v got %v", buf.Len(), n)
+	}
+	got := buf.String()
+
+	for _, expected := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 -10 10 10">`,
+		`<g id="labels" fill="none" stroke="black" stroke-width="0.1">`,
+		`<path d="M0 0 L10 0 L0 -10 Z" fill-rule="evenodd" fill="green" fill-opacity="0.4"/>`,
+		`<path d="M10 0 L10 -10 L0 -10 Z" fill-rule="evenodd" fill="red" fill-opacity="0.4"/>`,
+		`>` + planar.Inside.String() + `</text>`,
+		`>a &lt; b</text>`,
+		`font-size="1"`,
+		`<g id="edges" fill="none" stroke="blue" stroke-width="0.2">`,
+		`stroke="red"/>`,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("svg, expected to contain %v got\n%v", expected, got)
+		}
+	}
+	// the layers are in the order they were added
+	if strings.Index(got, `id="labels"`) > strings.Index(got, `id="edges"`) {
+		t.Errorf("layers, expected labels before edges")
+	}
+}
+
+func TestDocumentHeight(t *testing.T) {
+	type tcase struct {
+		geo    geom.Geometry
+		height string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			doc := Document{Width: 100, Margin: -1}
+			if err := doc.Layer("lines", Style{}).AddGeometry(tc.geo); err != nil {
+				t.Fatalf("add error, expected nil got %v", err)
+			}
+			var buf bytes.Buffer
+			if _, err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("write error, expected nil got %v", err)
+			}
+			expected := `width="100" height="` + tc.height + `"`
+			if !strings.Contains(buf.String(), expected) {
+				t.Errorf("svg, expected to contain %v got\n%v", expected, buf.String())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"rounded": {
+			geo:    geom.LineString{{0, 0}, {3, 1}},
+			height: "33",
+		},
+		"at least a pixel": {
+			geo:    geom.LineString{{0, 0}, {300, 1}},
+			height: "1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDocumentExtent(t *testing.T) {
+	type tcase struct {
+		extent  *geom.Extent
+		geo     geom.Geometry
+		viewBox string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			doc := Document{Width: 100, Extent: tc.extent}
+			if err := doc.Layer("geometry", Style{}).AddGeometry(tc.geo); err != nil {
+				t.Fatalf("add error, expected nil got %v", err)
+			}
+			var buf bytes.Buffer
+			if _, err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("write error, expected nil got %v", err)
+			}
+			got := buf.String()
+			if expected := `viewBox="` + tc.viewBox + `"`; !strings.Contains(got, expected) {
+				t.Errorf("svg, expected to contain %v got\n%v", expected, got)
+			}
+			if strings.Contains(got, "NaN") || strings.Contains(got, "<path") {
+				t.Errorf("svg, expected no NaN or path got\n%v", got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nil extent": {
+			geo:     (*geom.Extent)(nil),
+			viewBox: "0 -1 1 1",
+		},
+		"line extent": {
+			extent:  &geom.Extent{0, 5, 10, 5},
+			geo:     geom.Point{5, 5},
+			viewBox: "0 -10 10 10",
+		},
+		"point extent": {
+			extent:  &geom.Extent{5, 5, 5, 5},
+			geo:     geom.Point{5, 5},
+			viewBox: "4.5 -5.5 1 1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,69 @@
+package wkb_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/geom/internal/tcase"
+)
+
+/*
+FuzzDecode checks that a decode either fails without a geometry, or gives a
+geometry that survives an encode and decode round trip. The corpus is seeded
+from the test cases and the go-fuzz corpus.
+*/
+func FuzzDecode(f *testing.F) {
+	fnames, err := tcase.GetFiles("testdata")
+	if err != nil {
+		f.Fatalf("error getting files: %v", err)
+	}
+	for _, fname := range fnames {
+		cases, err := tcase.ParseFile(fname)
+		if err != nil {
+			f.Fatalf("error parsing file: %v : %v ", fname, err)
+		}
+		for _, tc := range cases {
+			f.Add(tc.Bytes)
+		}
+	}
+	corpus, err := filepath.Glob(filepath.Join("internal", "fuzz", "corpus", "*"))
+	if err != nil {
+		f.Fatalf("error getting corpus: %v", err)
+	}
+	for _, fname := range corpus {
+		data, err := ioutil.ReadFile(fname)
+		if err != nil {
+			f.Fatalf("error reading corpus: %v", err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		geo, err := wkb.DecodeBytes(data)
+		if err != nil {
+			if geo != nil {
+				t.Fatalf("geometry, expected nil on error (%v) got %#v", err, geo)
+			}
+			return
+		}
+		bs, err := wkb.EncodeBytes(geo)
+		if err != nil {
+			t.Fatalf("encode error, expected nil got %v for %#v", err, geo)
+		}
+		again, err := wkb.DecodeBytes(bs)
+		if err != nil {
+			t.Fatalf("decode of encoding, expected nil got %v for %#v", err, geo)
+		}
+		// the byte order of data may differ, so the encodings are compared
+		abs, err := wkb.EncodeBytes(again)
+		if err != nil {
+			t.Fatalf("encode error, expected nil got %v for %#v", err, again)
+		}
+		if !bytes.Equal(abs, bs) {
+			t.Errorf("round trip, expected %v got %v", tcase.SprintBinary(bs, "\t"), tcase.SprintBinary(abs, "\t"))
+		}
+	})
+}
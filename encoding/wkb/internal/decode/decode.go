@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/encoding/wkb/internal/consts"
@@ -45,6 +46,18 @@ func ByteOrderType(r io.Reader) (byteOrder binary.ByteOrder, typ uint32, err err
 	return byteOrder, typ, err
 }
 
+// maxPrealloc is the most items allocated up front for a count read from
+// the data; a corrupt count then fails at the end of the data, rather than
+// allocating all the memory.
+const maxPrealloc = 1024
+
+func prealloc(num uint32) int {
+	if num > maxPrealloc {
+		return maxPrealloc
+	}
+	return int(num)
+}
+
 func Point(r io.Reader, bom binary.ByteOrder) (pt geom.Point, err error) {
 	err = binary.Read(r, bom, &pt)
 	return pt, err
@@ -56,8 +69,8 @@ func MultiPoint(r io.Reader, bom binary.ByteOrder) (pts geom.MultiPoint, err err
 		return pts, err
 	}
 
-	pts = make([][2]float64, num)
-	for i := range pts {
+	pts = make([][2]float64, 0, prealloc(num))
+	for i := uint32(0); i < num; i++ {
 
 		bom, typ, err = ByteOrderType(r)
 		if err != nil {
@@ -66,10 +79,12 @@ func MultiPoint(r io.Reader, bom binary.ByteOrder) (pts geom.MultiPoint, err err
 		if typ != consts.Point {
 			return pts, ErrInvalidType{"multipoint", typ}
 		}
-		err = binary.Read(r, bom, &pts[i])
+		var pt [2]float64
+		err = binary.Read(r, bom, &pt)
 		if err != nil {
 			return pts, err
 		}
+		pts = append(pts, pt)
 	}
 	return pts, err
 }
@@ -79,11 +94,13 @@ func LineString(r io.Reader, bom binary.ByteOrder) (ln geom.LineString, err erro
 	if err = binary.Read(r, bom, &num); err != nil {
 		return ln, err
 	}
-	ln = make([][2]float64, num)
-	for i := range ln {
-		if err = binary.Read(r, bom, &ln[i]); err != nil {
+	ln = make([][2]float64, 0, prealloc(num))
+	for i := uint32(0); i < num; i++ {
+		var pt [2]float64
+		if err = binary.Read(r, bom, &pt); err != nil {
 			return ln, err
 		}
+		ln = append(ln, pt)
 	}
 	return ln, err
 }
@@ -93,8 +110,8 @@ func MultiLineString(r io.Reader, bom binary.ByteOrder) (lns geom.MultiLineStrin
 	if err = binary.Read(r, bom, &num); err != nil {
 		return lns, err
 	}
-	lns = make([][][2]float64, num)
-	for i := range lns {
+	lns = make([][][2]float64, 0, prealloc(num))
+	for i := uint32(0); i < num; i++ {
 		bom, typ, err := ByteOrderType(r)
 		if err != nil {
 			return lns, err
@@ -102,27 +119,40 @@ func MultiLineString(r io.Reader, bom binary.ByteOrder) (lns geom.MultiLineStrin
 		if typ != consts.LineString {
 			return lns, ErrInvalidType{"multilinestring", typ}
 		}
-		if lns[i], err = LineString(r, bom); err != nil {
+		ln, err := LineString(r, bom)
+		if err != nil {
 			return lns, err
 		}
+		lns = append(lns, ln)
 	}
 	return lns, err
 }
 
+// samePoint reports if a and b are the same bits; the encoder compares
+// the ends of rings the same way.
+func samePoint(a, b [2]float64) bool {
+	return math.Float64bits(a[0]) == math.Float64bits(b[0]) && math.Float64bits(a[1]) == math.Float64bits(b[1])
+}
+
 func LinerRing(r io.Reader, bom binary.ByteOrder) (rn [][2]float64, err error) {
 	var num uint32 // Number of points
 	if err = binary.Read(r, bom, &num); err != nil {
 		return rn, err
 	}
-	rn = make([][2]float64, num)
-	for i := range rn {
-		if err = binary.Read(r, bom, &rn[i]); err != nil {
+	rn = make([][2]float64, 0, prealloc(num))
+	for i := uint32(0); i < num; i++ {
+		var pt [2]float64
+		if err = binary.Read(r, bom, &pt); err != nil {
 			return rn, err
 		}
+		rn = append(rn, pt)
 	}
 	if num > 1 {
-		// Remove the last point if it is the same.
-		if rn[0][0] == rn[num-1][0] && rn[0][1] == rn[num-1][1] {
+		// Remove the last point if it is the same. It is kept if the point
+		// before it is the same as well, as the encoder would take the ring
+		// without it to be closed, and the ring would lose a point for
+		// every encode and decode.
+		if samePoint(rn[0], rn[num-1]) && !samePoint(rn[0], rn[num-2]) {
 			rn = rn[:num-1]
 		}
 	}
@@ -135,11 +165,13 @@ func Polygon(r io.Reader, bom binary.ByteOrder) (ply geom.Polygon, err error) {
 	if err = binary.Read(r, bom, &num); err != nil {
 		return ply, err
 	}
-	ply = make([][][2]float64, num)
-	for i := range ply {
-		if ply[i], err = LinerRing(r, bom); err != nil {
+	ply = make([][][2]float64, 0, prealloc(num))
+	for i := uint32(0); i < num; i++ {
+		rn, err := LinerRing(r, bom)
+		if err != nil {
 			return ply, err
 		}
+		ply = append(ply, rn)
 	}
 	return ply, err
 }
@@ -149,8 +181,8 @@ func MultiPolygon(r io.Reader, bom binary.ByteOrder) (plys geom.MultiPolygon, er
 	if err = binary.Read(r, bom, &num); err != nil {
 		return plys, err
 	}
-	plys = make([][][][2]float64, num)
-	for i := range plys {
+	plys = make([][][][2]float64, 0, prealloc(num))
+	for i := uint32(0); i < num; i++ {
 		bom, typ, err := ByteOrderType(r)
 		if err != nil {
 			return plys, err
@@ -158,9 +190,11 @@ func MultiPolygon(r io.Reader, bom binary.ByteOrder) (plys geom.MultiPolygon, er
 		if typ != consts.Polygon {
 			return plys, ErrInvalidType{"multipolygon", typ}
 		}
-		if plys[i], err = Polygon(r, bom); err != nil {
+		ply, err := Polygon(r, bom)
+		if err != nil {
 			return plys, err
 		}
+		plys = append(plys, ply)
 	}
 	return plys, err
 }
@@ -170,33 +204,35 @@ func Collection(r io.Reader, bom binary.ByteOrder) (col geom.Collection, err err
 	if err = binary.Read(r, bom, &num); err != nil {
 		return col, err
 	}
-	col = make(geom.Collection, num)
-	for i := range col {
+	col = make(geom.Collection, 0, prealloc(num))
+	for i := uint32(0); i < num; i++ {
 		bom, typ, err := ByteOrderType(r)
 		if err != nil {
 			return col, err
 		}
+		var g geom.Geometry
 		switch typ {
 		case consts.Point:
-			col[i], err = Point(r, bom)
+			g, err = Point(r, bom)
 		case consts.LineString:
-			col[i], err = LineString(r, bom)
+			g, err = LineString(r, bom)
 		case consts.Polygon:
-			col[i], err = Polygon(r, bom)
+			g, err = Polygon(r, bom)
 		case consts.MultiPoint:
-			col[i], err = MultiPoint(r, bom)
+			g, err = MultiPoint(r, bom)
 		case consts.MultiLineString:
-			col[i], err = MultiLineString(r, bom)
+			g, err = MultiLineString(r, bom)
 		case consts.MultiPolygon:
-			col[i], err = MultiPolygon(r, bom)
+			g, err = MultiPolygon(r, bom)
 		case consts.Collection:
-			col[i], err = Collection(r, bom)
+			g, err = Collection(r, bom)
 		default:
 			err = ErrInvalidType{"collection", typ}
 		}
 		if err != nil {
 			return col, err
 		}
+		col = append(col, g)
 	}
 	return col, err
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"math"
 
 	"github.com/go-spatial/geom/encoding/wkb/internal/consts"
 )
@@ -80,6 +81,12 @@ func (en *Encoder) MultiLineString(lns [][][2]float64) {
 	}
 }
 
+// samePoint reports if a and b are the same bits, so a ring with NaN
+// coordinates is not closed again on every encode.
+func samePoint(a, b [2]float64) bool {
+	return math.Float64bits(a[0]) == math.Float64bits(b[0]) && math.Float64bits(a[1]) == math.Float64bits(b[1])
+}
+
 func (en *Encoder) Polygon(ply [][][2]float64) {
 	en.BOM().Write(consts.Polygon, uint32(len(ply)))
 	for _, r := range ply {
@@ -89,7 +96,7 @@ func (en *Encoder) Polygon(ply [][][2]float64) {
 		var needToClose bool
 		length := uint32(len(r))
 
-		if length > 0 && (r[0][0] != r[length-1][0] || r[0][1] != r[length-1][1]) {
+		if length > 0 && !samePoint(r[0], r[length-1]) {
 			// Let's close the ring.
 			length += 1
 			needToClose = true
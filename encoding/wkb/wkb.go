@@ -44,6 +44,7 @@ func DecodeBytes(b []byte) (geo geom.Geometry, err error) {
 }
 
 // Decode will attempt to decode a geometry encoded as WKB into a geom.Geometry.
+// On an error the geometry is nil, rather than the part decoded before it.
 func Decode(r io.Reader) (geo geom.Geometry, err error) {
 
 	bom, typ, err := decode.ByteOrderType(r)
@@ -53,25 +54,46 @@ func Decode(r io.Reader) (geo geom.Geometry, err error) {
 	switch typ {
 	case Point:
 		pt, err := decode.Point(r, bom)
-		return geom.Point(pt), err
+		if err != nil {
+			return nil, err
+		}
+		return geom.Point(pt), nil
 	case MultiPoint:
 		mpt, err := decode.MultiPoint(r, bom)
-		return geom.MultiPoint(mpt), err
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiPoint(mpt), nil
 	case LineString:
 		ln, err := decode.LineString(r, bom)
-		return geom.LineString(ln), err
+		if err != nil {
+			return nil, err
+		}
+		return geom.LineString(ln), nil
 	case MultiLineString:
 		mln, err := decode.MultiLineString(r, bom)
-		return geom.MultiLineString(mln), err
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiLineString(mln), nil
 	case Polygon:
 		pl, err := decode.Polygon(r, bom)
-		return geom.Polygon(pl), err
+		if err != nil {
+			return nil, err
+		}
+		return geom.Polygon(pl), nil
 	case MultiPolygon:
 		mpl, err := decode.MultiPolygon(r, bom)
-		return geom.MultiPolygon(mpl), err
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiPolygon(mpl), nil
 	case Collection:
 		col, err := decode.Collection(r, bom)
-		return col, err
+		if err != nil {
+			return nil, err
+		}
+		return col, nil
 	default:
 		return nil, ErrUnknownGeometryType{typ}
 	}
@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/go-spatial/geom/encoding/wkb"
-	"github.com/go-spatial/geom/encoding/wkb/internal/tcase"
+	"github.com/go-spatial/geom/internal/tcase"
 )
 
 func TestWKBDecode(t *testing.T) {
@@ -29,6 +29,9 @@ func TestWKBDecode(t *testing.T) {
 			return
 		}
 		if tc.HasErrorFor(tcase.TypeDecode) {
+			if geom != nil {
+				t.Errorf("decode, expected nil on an error got %v", geom)
+			}
 			return
 		}
 		if !reflect.DeepEqual(geom, tc.Expected) {
@@ -2,11 +2,13 @@ package wkb_test
 
 import (
 	"log"
+	"math"
 	"reflect"
 	"testing"
 
+	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/encoding/wkb"
-	"github.com/go-spatial/geom/encoding/wkb/internal/tcase"
+	"github.com/go-spatial/geom/internal/tcase"
 )
 
 func TestWKBEncode(t *testing.T) {
@@ -47,3 +49,36 @@ func TestWKBEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestRingRoundTrip(t *testing.T) {
+	fn := func(ply geom.Polygon) func(*testing.T) {
+		return func(t *testing.T) {
+			bs, err := wkb.EncodeBytes(ply)
+			if err != nil {
+				t.Fatalf("encode error, expected nil got %v", err)
+			}
+			g, err := wkb.DecodeBytes(bs)
+			if err != nil {
+				t.Fatalf("decode error, expected nil got %v", err)
+			}
+			again, err := wkb.EncodeBytes(g)
+			if err != nil {
+				t.Fatalf("encode error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(again, bs) {
+				t.Errorf("round trip, expected %v got %v", tcase.SprintBinary(bs, "\t"), tcase.SprintBinary(again, "\t"))
+			}
+		}
+	}
+
+	nan := math.NaN()
+	tests := map[string]geom.Polygon{
+		"repeated point": {{{1, 1}, {1, 1}}},
+		"nan":            {{{nan, 0}, {1, 1}, {0, 1}}},
+		"nan closed":     {{{nan, 0}, {1, 1}, {nan, 0}}},
+	}
+
+	for name, ply := range tests {
+		t.Run(name, fn(ply))
+	}
+}
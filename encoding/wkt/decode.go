@@ -0,0 +1,277 @@
+package wkt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrSyntax is returned by Decode for text that is not well-known text.
+type ErrSyntax struct {
+	// Offset is the byte offset of the error in the text.
+	Offset int
+	Msg    string
+}
+
+func (e ErrSyntax) Error() string {
+	return fmt.Sprintf("wkt: syntax error at offset %v: %v", e.Offset, e.Msg)
+}
+
+/*
+Decode returns the geometry of the well-known text. The Z and M values of
+coordinates are dropped.
+
+Empty geometries decode as they are encoded by Encode; POINT EMPTY is a nil
+*geom.Point and the others are empty values of their types.
+*/
+func Decode(text string) (geo geom.Geometry, err error) {
+	d := decoder{text: text}
+	geo, err = d.geometry()
+	if err != nil {
+		return nil, err
+	}
+	if tok := d.next(); tok != "" {
+		return nil, d.errorf("unexpected %q after the geometry", tok)
+	}
+	return geo, nil
+}
+
+// decoder is a recursive descent parser of well-known text.
+type decoder struct {
+	text string
+	pos  int
+	// the offset of the last token returned by next
+	tokPos int
+	// the number of coordinates of each point of the current geometry
+	dims int
+}
+
+func (d *decoder) errorf(format string, args ...interface{}) error {
+	return ErrSyntax{Offset: d.tokPos, Msg: fmt.Sprintf(format, args...)}
+}
+
+/*
+next returns the next token; a word, a number or one of "(", ")" and ",".
+An empty string is returned at the end of the text.
+*/
+func (d *decoder) next() string {
+	for d.pos < len(d.text) && unicode.IsSpace(rune(d.text[d.pos])) {
+		d.pos++
+	}
+	d.tokPos = d.pos
+	if d.pos == len(d.text) {
+		return ""
+	}
+	switch c := d.text[d.pos]; {
+	case c == '(' || c == ')' || c == ',':
+		d.pos++
+	case unicode.IsLetter(rune(c)):
+		for d.pos < len(d.text) && unicode.IsLetter(rune(d.text[d.pos])) {
+			d.pos++
+		}
+	default:
+		// letters are read as well, for the +Inf and -Inf Encode writes
+		for d.pos < len(d.text) && (strings.IndexByte("0123456789+-.", d.text[d.pos]) >= 0 || unicode.IsLetter(rune(d.text[d.pos]))) {
+			d.pos++
+		}
+		if d.pos == d.tokPos {
+			d.pos++
+		}
+	}
+	return d.text[d.tokPos:d.pos]
+}
+
+// peek returns the next token without consuming it.
+func (d *decoder) peek() string {
+	pos, tokPos := d.pos, d.tokPos
+	tok := d.next()
+	d.pos, d.tokPos = pos, tokPos
+	return tok
+}
+
+func (d *decoder) expect(tok string) error {
+	if got := d.next(); got != tok {
+		return d.errorf("expected %q got %q", tok, got)
+	}
+	return nil
+}
+
+/*
+open reads the dimension of a tagged geometry and the "(" that starts its
+text, and returns false if the geometry is EMPTY.
+*/
+func (d *decoder) open() (bool, error) {
+	d.dims = 2
+	switch strings.ToUpper(d.peek()) {
+	case "Z", "M":
+		d.next()
+		d.dims = 3
+	case "ZM":
+		d.next()
+		d.dims = 4
+	}
+	tok := d.next()
+	if strings.ToUpper(tok) == "EMPTY" {
+		return false, nil
+	}
+	if tok != "(" {
+		return false, d.errorf("expected \"(\" or EMPTY got %q", tok)
+	}
+	return true, nil
+}
+
+// list reads items separated by commas up to the closing ")".
+func (d *decoder) list(item func() error) error {
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		switch tok := d.next(); tok {
+		case ",":
+		case ")":
+			return nil
+		default:
+			return d.errorf("expected \",\" or \")\" got %q", tok)
+		}
+	}
+}
+
+func (d *decoder) point() ([2]float64, error) {
+	var pt [2]float64
+	for i := 0; i < d.dims; i++ {
+		tok := d.next()
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return pt, d.errorf("expected a number got %q", tok)
+		}
+		if i < 2 {
+			pt[i] = f
+		}
+	}
+	return pt, nil
+}
+
+// points reads a parenthesised list of points.
+func (d *decoder) points() ([][2]float64, error) {
+	if err := d.expect("("); err != nil {
+		return nil, err
+	}
+	pts := [][2]float64{}
+	err := d.list(func() error {
+		pt, err := d.point()
+		pts = append(pts, pt)
+		return err
+	})
+	return pts, err
+}
+
+// rings reads a parenthesised list of lists of points.
+func (d *decoder) rings() ([][][2]float64, error) {
+	if err := d.expect("("); err != nil {
+		return nil, err
+	}
+	rings := [][][2]float64{}
+	err := d.list(func() error {
+		pts, err := d.points()
+		rings = append(rings, pts)
+		return err
+	})
+	return rings, err
+}
+
+func (d *decoder) geometry() (geom.Geometry, error) {
+	tag := d.next()
+	switch strings.ToUpper(tag) {
+	case "POINT":
+		ok, err := d.open()
+		if err != nil || !ok {
+			return (*geom.Point)(nil), err
+		}
+		pt, err := d.point()
+		if err != nil {
+			return nil, err
+		}
+		return geom.Point(pt), d.expect(")")
+
+	case "MULTIPOINT":
+		mp := geom.MultiPoint{}
+		ok, err := d.open()
+		if err != nil || !ok {
+			return mp, err
+		}
+		err = d.list(func() error {
+			// the points may or may not be in parentheses
+			paren := d.peek() == "("
+			if paren {
+				d.next()
+			}
+			pt, err := d.point()
+			if err != nil {
+				return err
+			}
+			mp = append(mp, pt)
+			if paren {
+				return d.expect(")")
+			}
+			return nil
+		})
+		return mp, err
+
+	case "LINESTRING":
+		ok, err := d.open()
+		if err != nil || !ok {
+			return geom.LineString{}, err
+		}
+		d.pos = d.tokPos
+		pts, err := d.points()
+		return geom.LineString(pts), err
+
+	case "MULTILINESTRING", "POLYGON":
+		ok, err := d.open()
+		if err != nil || !ok {
+			if strings.ToUpper(tag) == "POLYGON" {
+				return geom.Polygon{}, err
+			}
+			return geom.MultiLineString{}, err
+		}
+		d.pos = d.tokPos
+		rings, err := d.rings()
+		if strings.ToUpper(tag) == "POLYGON" {
+			return geom.Polygon(rings), err
+		}
+		return geom.MultiLineString(rings), err
+
+	case "MULTIPOLYGON":
+		mp := geom.MultiPolygon{}
+		ok, err := d.open()
+		if err != nil || !ok {
+			return mp, err
+		}
+		err = d.list(func() error {
+			rings, err := d.rings()
+			mp = append(mp, rings)
+			return err
+		})
+		return mp, err
+
+	case "GEOMETRYCOLLECTION":
+		col := geom.Collection{}
+		ok, err := d.open()
+		if err != nil || !ok {
+			return col, err
+		}
+		err = d.list(func() error {
+			g, err := d.geometry()
+			col = append(col, g)
+			return err
+		})
+		return col, err
+	}
+	if tag == "" {
+		return nil, d.errorf("expected a geometry got the end of the text")
+	}
+	return nil, d.errorf("unknown geometry %q", tag)
+}
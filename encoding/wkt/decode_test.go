@@ -0,0 +1,123 @@
+package wkt
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestDecode(t *testing.T) {
+	type tcase struct {
+		Rep  string
+		Geom geom.Geometry
+		Err  error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			geo, err := Decode(tc.Rep)
+			if tc.Err != nil {
+				if err == nil || err.Error() != tc.Err.Error() {
+					t.Errorf("error, expected %v got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(geo, tc.Geom) {
+				t.Errorf("geometry, expected %#v got %#v", tc.Geom, geo)
+			}
+			// the decoded geometry encodes back to the text, when that is
+			// in the form Encode writes
+			rep, err := Encode(geo)
+			if err != nil {
+				t.Fatalf("encode error, expected nil got %v", err)
+			}
+			if again, err := Decode(rep); err != nil || !reflect.DeepEqual(again, geo) {
+				t.Errorf("round trip of %v, expected %#v got %#v (%v)", rep, geo, again, err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			Rep:  "POINT (10 -2.5)",
+			Geom: geom.Point{10, -2.5},
+		},
+		"point empty": {
+			Rep:  "POINT EMPTY",
+			Geom: (*geom.Point)(nil),
+		},
+		"point zm": {
+			Rep:  "point zm (1 2 3 4)",
+			Geom: geom.Point{1, 2},
+		},
+		"point infinite": {
+			Rep:  "POINT (+Inf -Inf)",
+			Geom: geom.Point{math.Inf(1), math.Inf(-1)},
+		},
+		"multipoint": {
+			Rep:  "MULTIPOINT (1 2,3 4)",
+			Geom: geom.MultiPoint{{1, 2}, {3, 4}},
+		},
+		"multipoint parentheses": {
+			Rep:  "MULTIPOINT ((1 2), (3 4))",
+			Geom: geom.MultiPoint{{1, 2}, {3, 4}},
+		},
+		"linestring": {
+			Rep:  "LINESTRING (0 0,1e3 1,2 2)",
+			Geom: geom.LineString{{0, 0}, {1000, 1}, {2, 2}},
+		},
+		"linestring empty": {
+			Rep:  "LINESTRING EMPTY",
+			Geom: geom.LineString{},
+		},
+		"multilinestring": {
+			Rep:  "MULTILINESTRING ((0 0,1 1),(2 2,3 3))",
+			Geom: geom.MultiLineString{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}},
+		},
+		"polygon": {
+			Rep:  "POLYGON Z ((0 0 1,10 0 1,10 10 1,0 0 1))",
+			Geom: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+		},
+		"multipolygon empty member": {
+			Rep: "MULTIPOLYGON (((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)),EMPTY)",
+			Err: ErrSyntax{Offset: 54, Msg: `expected "(" got "EMPTY"`},
+		},
+		"multipolygon two": {
+			Rep:  "MULTIPOLYGON (((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))",
+			Geom: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{5, 5}, {6, 5}, {6, 6}, {5, 5}}}},
+		},
+		"collection": {
+			Rep:  "GEOMETRYCOLLECTION (POINT (1 2),LINESTRING (0 0,1 1))",
+			Geom: geom.Collection{geom.Point{1, 2}, geom.LineString{{0, 0}, {1, 1}}},
+		},
+		"collection empty": {
+			Rep:  "GEOMETRYCOLLECTION EMPTY",
+			Geom: geom.Collection{},
+		},
+		"unknown": {
+			Rep: "CIRCLE (1 2)",
+			Err: ErrSyntax{Offset: 0, Msg: `unknown geometry "CIRCLE"`},
+		},
+		"bad number": {
+			Rep: "POINT (1 x)",
+			Err: ErrSyntax{Offset: 9, Msg: `expected a number got "x"`},
+		},
+		"trailing": {
+			Rep: "POINT (1 2) POINT",
+			Err: ErrSyntax{Offset: 12, Msg: `unexpected "POINT" after the geometry`},
+		},
+		"unclosed": {
+			Rep: "LINESTRING (0 0,1 1",
+			Err: ErrSyntax{Offset: 19, Msg: `expected "," or ")" got ""`},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,53 @@
+package wkt
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom/internal/tcase"
+)
+
+/*
+FuzzDecode checks that text that decodes, encodes to text that decodes and
+encodes to the same text. The corpus is seeded from the wkb test cases.
+*/
+func FuzzDecode(f *testing.F) {
+	geos, err := tcase.Geometries(tcase.WKBTestdata())
+	if err != nil {
+		f.Fatalf("error getting test cases: %v", err)
+	}
+	for _, g := range geos {
+		rep, err := Encode(g)
+		if err != nil {
+			f.Fatalf("encode error, expected nil got %v", err)
+		}
+		f.Add(rep)
+	}
+	f.Add("POINT ZM (1 2 3 4)")
+	f.Add("MULTIPOINT ((1 2),(3 4))")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		geo, err := Decode(text)
+		if err != nil {
+			if geo != nil {
+				t.Fatalf("geometry, expected nil on error (%v) got %#v", err, geo)
+			}
+			return
+		}
+		rep, err := Encode(geo)
+		if err != nil {
+			t.Fatalf("encode error, expected nil got %v for %#v", err, geo)
+		}
+		again, err := Decode(rep)
+		if err != nil {
+			t.Fatalf("decode of %v, expected nil got %v", rep, err)
+		}
+		// NaN coordinates are not equal, so the encodings are compared
+		arep, err := Encode(again)
+		if err != nil {
+			t.Fatalf("encode error, expected nil got %v for %#v", err, again)
+		}
+		if arep != rep {
+			t.Errorf("round trip, expected %v got %v", rep, arep)
+		}
+	})
+}
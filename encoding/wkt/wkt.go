@@ -218,7 +218,3 @@ func Encode(geo geom.Geometry) (string, error) {
 		return "GEOMETRYCOLLECTION (" + strings.Join(geometries, ",") + ")", nil
 	}
 }
-
-func Decode(text string) (geo geom.Geometry, err error) {
-	return nil, nil
-}
@@ -0,0 +1,228 @@
+package geom
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// The tags written before each geometry, so that different kinds of
+// geometries with the same coordinates have different hashes.
+const (
+	hashTagUnknown byte = iota
+	hashTagPoint
+	hashTagMultiPoint
+	hashTagLineString
+	hashTagMultiLineString
+	hashTagPolygon
+	hashTagMultiPolygon
+	hashTagCollection
+)
+
+// Hasher computes hashes of geometries that do not depend on the concrete
+// type of the geometry, the point a line string or ring starts at, whether
+// rings are closed, or the order of the parts of Multi geometries and the
+// holes of polygons. Geometries that are equal under cmp.GeometryEqual
+// hash the same (except for coordinates that are within cmp.TOLERANCE of
+// each other but not the same; see Tolerance). Geometries of unknown types
+// all hash the same.
+//
+// The zero value hashes the exact coordinates.
+type Hasher struct {
+	// Tolerance, if larger then zero, is the size of the grid the
+	// coordinates are snapped to before hashing. Two coordinates that are
+	// closer than the Tolerance can still snap to different grid points.
+	Tolerance float64
+}
+
+// Hash returns a 64-bit FNV-1a hash of the geometry.
+func (h Hasher) Hash(g Geometry) uint64 {
+	w := fnv.New64a()
+	h.write(w, g)
+	return w.Sum64()
+}
+
+// Fingerprint returns a SHA-256 hash of the geometry, suitable for content
+// addressing.
+func (h Hasher) Fingerprint(g Geometry) (sum [sha256.Size]byte) {
+	w := sha256.New()
+	h.write(w, g)
+	copy(sum[:], w.Sum(nil))
+	return sum
+}
+
+// Hash returns a 64-bit hash of the geometry using the zero Hasher.
+func Hash(g Geometry) uint64 { return Hasher{}.Hash(g) }
+
+// Fingerprint returns a SHA-256 hash of the geometry using the zero Hasher.
+func Fingerprint(g Geometry) [sha256.Size]byte { return Hasher{}.Fingerprint(g) }
+
+func (h Hasher) snap(f float64) float64 {
+	if h.Tolerance > 0 {
+		f = math.Round(f/h.Tolerance) * h.Tolerance
+	}
+	// -0 and 0 are the same value, but not the same bits.
+	if f == 0 {
+		return 0
+	}
+	return f
+}
+
+func (h Hasher) snapPoints(pts [][2]float64) [][2]float64 {
+	ret := make([][2]float64, len(pts))
+	for i := range pts {
+		ret[i] = [2]float64{h.snap(pts[i][0]), h.snap(pts[i][1])}
+	}
+	return ret
+}
+
+// rotateToLeast returns the rotation of the points that is the least,
+// comparing them point by point.
+func rotateToLeast(pts [][2]float64) [][2]float64 {
+	if len(pts) < 2 {
+		return pts
+	}
+	best := pts
+	for i := range pts {
+		if xyCompare(pts[i], best[0]) > 0 {
+			continue
+		}
+		rot := append(append(make([][2]float64, 0, len(pts)), pts[i:]...), pts[:i]...)
+		if pointsCompare(rot, best) < 0 {
+			best = rot
+		}
+	}
+	return best
+}
+
+func writeUint64(buf *bytes.Buffer, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	buf.Write(b[:])
+}
+
+func writePoints(buf *bytes.Buffer, pts [][2]float64) {
+	writeUint64(buf, uint64(len(pts)))
+	for _, pt := range pts {
+		writeUint64(buf, math.Float64bits(pt[0]))
+		writeUint64(buf, math.Float64bits(pt[1]))
+	}
+}
+
+func (h Hasher) lineString(ls [][2]float64) []byte {
+	var buf bytes.Buffer
+	writePoints(&buf, rotateToLeast(h.snapPoints(ls)))
+	return buf.Bytes()
+}
+
+func (h Hasher) ring(ring [][2]float64) []byte {
+	pts := h.snapPoints(ring)
+	if len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+	var buf bytes.Buffer
+	writePoints(&buf, rotateToLeast(pts))
+	return buf.Bytes()
+}
+
+// polygon encodes the exterior ring followed by the sorted holes.
+func (h Hasher) polygon(plyg [][][2]float64) []byte {
+	var buf bytes.Buffer
+	writeUint64(&buf, uint64(len(plyg)))
+	if len(plyg) == 0 {
+		return buf.Bytes()
+	}
+	buf.Write(h.ring(plyg[0]))
+	holes := make([][]byte, len(plyg)-1)
+	for i := range holes {
+		holes[i] = h.ring(plyg[i+1])
+	}
+	writeSorted(&buf, holes, false)
+	return buf.Bytes()
+}
+
+// writeSorted writes the encoded parts in sorted order, optionally dropping
+// duplicates.
+func writeSorted(buf *bytes.Buffer, parts [][]byte, dedupe bool) {
+	sort.Slice(parts, func(i, j int) bool { return bytes.Compare(parts[i], parts[j]) < 0 })
+	if dedupe {
+		uniq := parts[:0]
+		for i := range parts {
+			if i > 0 && bytes.Equal(parts[i], uniq[len(uniq)-1]) {
+				continue
+			}
+			uniq = append(uniq, parts[i])
+		}
+		parts = uniq
+	}
+	writeUint64(buf, uint64(len(parts)))
+	for _, p := range parts {
+		buf.Write(p)
+	}
+}
+
+// write writes the canonical encoding of the geometry to w. The geometry
+// interfaces are checked in the same order cmp.GeometryEqual uses.
+func (h Hasher) write(w hash.Hash, g Geometry) {
+	var buf bytes.Buffer
+	switch gg := g.(type) {
+
+	case Pointer:
+		buf.WriteByte(hashTagPoint)
+		pt := gg.XY()
+		writeUint64(&buf, math.Float64bits(h.snap(pt[0])))
+		writeUint64(&buf, math.Float64bits(h.snap(pt[1])))
+
+	case MultiPointer:
+		buf.WriteByte(hashTagMultiPoint)
+		pts := h.snapPoints(gg.Points())
+		sort.Slice(pts, func(i, j int) bool { return xyCompare(pts[i], pts[j]) < 0 })
+		writePoints(&buf, pts)
+
+	case LineStringer:
+		buf.WriteByte(hashTagLineString)
+		buf.Write(h.lineString(gg.Verticies()))
+
+	case MultiLineStringer:
+		buf.WriteByte(hashTagMultiLineString)
+		lss := gg.LineStrings()
+		parts := make([][]byte, len(lss))
+		for i := range lss {
+			parts[i] = h.lineString(lss[i])
+		}
+		// cmp.MultiLineEqual only checks that every line string of one
+		// is in the other.
+		writeSorted(&buf, parts, true)
+
+	case Polygoner:
+		buf.WriteByte(hashTagPolygon)
+		buf.Write(h.polygon(gg.LinearRings()))
+
+	case MultiPolygoner:
+		buf.WriteByte(hashTagMultiPolygon)
+		plygs := gg.Polygons()
+		parts := make([][]byte, len(plygs))
+		for i := range plygs {
+			parts[i] = h.polygon(plygs[i])
+		}
+		writeSorted(&buf, parts, false)
+
+	case Collectioner:
+		buf.WriteByte(hashTagCollection)
+		geos := gg.Geometries()
+		writeUint64(&buf, uint64(len(geos)))
+		w.Write(buf.Bytes())
+		for i := range geos {
+			h.write(w, geos[i])
+		}
+		return
+
+	default:
+		buf.WriteByte(hashTagUnknown)
+	}
+	w.Write(buf.Bytes())
+}
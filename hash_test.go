@@ -0,0 +1,140 @@
+package geom_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+// ring is a user type that only implements the Polygoner interface.
+type ring [][2]float64
+
+func (r ring) LinearRings() [][][2]float64 { return [][][2]float64{r} }
+
+func TestHash(t *testing.T) {
+	type tcase struct {
+		hasher geom.Hasher
+		g1, g2 geom.Geometry
+		// if the geometries should hash the same
+		same bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			h1, h2 := tc.hasher.Hash(tc.g1), tc.hasher.Hash(tc.g2)
+			if (h1 == h2) != tc.same {
+				t.Errorf("hash, expected same %v got %v, %v", tc.same, h1, h2)
+			}
+			f1, f2 := tc.hasher.Fingerprint(tc.g1), tc.hasher.Fingerprint(tc.g2)
+			if (f1 == f2) != tc.same {
+				t.Errorf("fingerprint, expected same %v got %x, %x", tc.same, f1, f2)
+			}
+			// the hashes should agree with cmp for exact coordinates
+			if tc.hasher.Tolerance == 0 && cmp.GeometryEqual(tc.g1, tc.g2) && !tc.same {
+				t.Errorf("cmp, geometries are equal but hash differently")
+			}
+		}
+	}
+
+	square := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	tests := map[string]tcase{
+		"point pointer": {
+			g1:   geom.Point{1, 2},
+			g2:   &geom.Point{1, 2},
+			same: true,
+		},
+		"point negative zero": {
+			g1:   geom.Point{0, 2},
+			g2:   geom.Point{math.Copysign(0, -1), 2},
+			same: true,
+		},
+		"point different": {
+			g1: geom.Point{1, 2},
+			g2: geom.Point{2, 1},
+		},
+		"point tolerance": {
+			hasher: geom.Hasher{Tolerance: 0.01},
+			g1:     geom.Point{1, 2},
+			g2:     geom.Point{1.001, 1.999},
+			same:   true,
+		},
+		"point no tolerance": {
+			g1: geom.Point{1, 2},
+			g2: geom.Point{1.001, 1.999},
+		},
+		"multipoint order": {
+			g1:   geom.MultiPoint{{1, 2}, {3, 4}},
+			g2:   geom.MultiPoint{{3, 4}, {1, 2}},
+			same: true,
+		},
+		"point and multipoint": {
+			g1: geom.Point{1, 2},
+			g2: geom.MultiPoint{{1, 2}},
+		},
+		"linestring rotated": {
+			g1:   geom.LineString{{1, 1}, {0, 0}, {2, 0}},
+			g2:   geom.LineString{{0, 0}, {2, 0}, {1, 1}},
+			same: true,
+		},
+		"linestring different": {
+			g1: geom.LineString{{1, 1}, {0, 0}, {2, 0}},
+			g2: geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+		},
+		"multilinestring order": {
+			g1:   geom.MultiLineString{{{0, 0}, {1, 1}}, {{5, 5}, {6, 6}}},
+			g2:   geom.MultiLineString{{{5, 5}, {6, 6}}, {{0, 0}, {1, 1}}},
+			same: true,
+		},
+		"polygon rotated and closed": {
+			g1:   geom.Polygon{square},
+			g2:   geom.Polygon{{{10, 10}, {0, 10}, {0, 0}, {10, 0}, {10, 10}}},
+			same: true,
+		},
+		"polygon holes order": {
+			g1: geom.Polygon{
+				square,
+				{{1, 1}, {1, 2}, {2, 2}},
+				{{5, 5}, {5, 6}, {6, 6}},
+			},
+			g2: geom.Polygon{
+				square,
+				{{5, 6}, {6, 6}, {5, 5}},
+				{{1, 1}, {1, 2}, {2, 2}},
+			},
+			same: true,
+		},
+		"polygon hole is not the exterior": {
+			g1: geom.Polygon{square, {{1, 1}, {1, 2}, {2, 2}}},
+			g2: geom.Polygon{{{1, 1}, {1, 2}, {2, 2}}, square},
+		},
+		"polygon user type": {
+			g1:   geom.Polygon{square},
+			g2:   ring{{10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			same: true,
+		},
+		"multipolygon order": {
+			g1:   geom.MultiPolygon{{square}, {{{20, 20}, {21, 20}, {21, 21}}}},
+			g2:   geom.MultiPolygon{{{{21, 21}, {20, 20}, {21, 20}}}, {square}},
+			same: true,
+		},
+		"collection": {
+			g1:   geom.Collection{geom.Point{1, 2}, geom.LineString{{1, 1}, {0, 0}}},
+			g2:   geom.Collection{&geom.Point{1, 2}, geom.LineString{{0, 0}, {1, 1}}},
+			same: true,
+		},
+		"collection order": {
+			g1: geom.Collection{geom.Point{1, 2}, geom.Point{3, 4}},
+			g2: geom.Collection{geom.Point{3, 4}, geom.Point{1, 2}},
+		},
+		"collection nesting": {
+			g1: geom.Collection{geom.Point{1, 2}, geom.Point{3, 4}},
+			g2: geom.Collection{geom.Collection{geom.Point{1, 2}}, geom.Point{3, 4}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
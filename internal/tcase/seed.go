@@ -0,0 +1,43 @@
+package tcase
+
+import (
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-spatial/geom"
+)
+
+/*
+WKBTestdata returns the directory of the wkb test cases; these seed the
+fuzz tests of the encodings and the planar algorithms.
+*/
+func WKBTestdata() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "encoding", "wkb", "testdata")
+}
+
+/*
+Geometries returns the expected geometries of the test cases in the .tcase
+files of dir. Cases that are expected to fail to decode are skipped.
+*/
+func Geometries(dir string) ([]geom.Geometry, error) {
+	fnames, err := GetFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var geos []geom.Geometry
+	for _, fname := range fnames {
+		cases, err := ParseFile(fname)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cases {
+			g, ok := c.Expected.(geom.Geometry)
+			if !ok || g == nil || c.DecodeError != "" {
+				continue
+			}
+			geos = append(geos, g)
+		}
+	}
+	return geos, nil
+}
@@ -58,3 +58,22 @@ func TestParse(t *testing.T) {
 		t.Run(name, func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestGeometries(t *testing.T) {
+	geos, err := Geometries("testdata")
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := []geom.Geometry{geom.Point{2, 4}, geom.Point{2, 4}}
+	if !reflect.DeepEqual(geos, expected) {
+		t.Errorf("geometries, expected %v got %v", expected, geos)
+	}
+
+	geos, err = Geometries(WKBTestdata())
+	if err != nil {
+		t.Fatalf("wkb testdata error, expected nil got %v", err)
+	}
+	if len(geos) == 0 {
+		t.Errorf("wkb testdata, expected geometries got none")
+	}
+}
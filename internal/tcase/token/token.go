@@ -7,8 +7,8 @@ import (
 	"strings"
 
 	"github.com/go-spatial/geom"
-	"github.com/go-spatial/geom/encoding/wkb/internal/tcase/symbol"
 	"github.com/go-spatial/geom/internal/parsing"
+	"github.com/go-spatial/geom/internal/tcase/symbol"
 )
 
 type T struct {
@@ -0,0 +1,182 @@
+package geom
+
+import "sort"
+
+// xyCompare compares two points by their x and then y values, returning
+// -1, 0 or 1.
+func xyCompare(p1, p2 [2]float64) int {
+	switch {
+	case p1[0] < p2[0]:
+		return -1
+	case p1[0] > p2[0]:
+		return 1
+	case p1[1] < p2[1]:
+		return -1
+	case p1[1] > p2[1]:
+		return 1
+	}
+	return 0
+}
+
+// pointsCompare compares two sequences of points, point by point; a shorter
+// sequence that matches the start of a longer one is less.
+func pointsCompare(l1, l2 [][2]float64) int {
+	for i := 0; i < len(l1) && i < len(l2); i++ {
+		if c := xyCompare(l1[i], l2[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(l1) < len(l2):
+		return -1
+	case len(l1) > len(l2):
+		return 1
+	}
+	return 0
+}
+
+// dedupePoints returns a copy of the points without consecutive duplicates.
+func dedupePoints(pts [][2]float64) [][2]float64 {
+	ret := make([][2]float64, 0, len(pts))
+	for i := range pts {
+		if i > 0 && pts[i] == ret[len(ret)-1] {
+			continue
+		}
+		ret = append(ret, pts[i])
+	}
+	return ret
+}
+
+func normalizeLineString(ls [][2]float64) [][2]float64 {
+	ret := dedupePoints(ls)
+	if len(ret) > 1 && xyCompare(ret[len(ret)-1], ret[0]) < 0 {
+		for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+			ret[i], ret[j] = ret[j], ret[i]
+		}
+	}
+	return ret
+}
+
+// ringArea returns twice the signed area of the ring, which is positive for
+// rings that are clockwise as defined by the windingorder package.
+func ringArea(ring [][2]float64) float64 {
+	a := 0.0
+	lp := len(ring) - 1
+	for i := range ring {
+		a += ring[lp][0]*ring[i][1] - ring[i][0]*ring[lp][1]
+		lp = i
+	}
+	return a
+}
+
+// normalizeRing returns the ring, not closed, with a positive area if
+// isExterior and a negative one if not, starting at its least point.
+func normalizeRing(ring [][2]float64, isExterior bool) [][2]float64 {
+	ret := dedupePoints(ring)
+	// rings are not closed
+	for len(ret) > 1 && ret[0] == ret[len(ret)-1] {
+		ret = ret[:len(ret)-1]
+	}
+	if len(ret) == 0 {
+		return ret
+	}
+	// the windingorder package can not be used here as it imports geom.
+	if a := ringArea(ret); a != 0 && (a > 0) != isExterior {
+		for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+			ret[i], ret[j] = ret[j], ret[i]
+		}
+	}
+	min := 0
+	for i := range ret {
+		if xyCompare(ret[i], ret[min]) < 0 {
+			min = i
+		}
+	}
+	return append(ret[min:], ret[:min]...)
+}
+
+func normalizePolygon(plyg [][][2]float64) [][][2]float64 {
+	if len(plyg) == 0 {
+		return [][][2]float64{}
+	}
+	ret := make([][][2]float64, len(plyg))
+	ret[0] = normalizeRing(plyg[0], true)
+	for i := 1; i < len(plyg); i++ {
+		ret[i] = normalizeRing(plyg[i], false)
+	}
+	holes := ret[1:]
+	sort.SliceStable(holes, func(i, j int) bool { return pointsCompare(holes[i], holes[j]) < 0 })
+	return ret
+}
+
+// Normalize returns a copy of the geometry in a canonical form, so that
+// geometries that are the same shape are also the same values:
+//
+//   - consecutive duplicate vertices are removed;
+//   - line strings start with the lesser of their two end points;
+//   - exterior rings are clockwise and interior rings counter clockwise (as
+//     defined by the windingorder package), are not closed, and start with
+//     their least point;
+//   - the points of MultiPoints, the line strings of MultiLineStrings, the
+//     holes of Polygons and the polygons of MultiPolygons are sorted.
+//
+// Points are ordered by their x and then y values. The geometries of a
+// Collection are normalized, but stay in their order.
+func Normalize(g Geometry) (Geometry, error) {
+	switch gg := g.(type) {
+
+	case Pointer:
+		return Point(gg.XY()), nil
+
+	case MultiPointer:
+		pts := append(MultiPoint{}, gg.Points()...)
+		sort.SliceStable(pts, func(i, j int) bool { return xyCompare(pts[i], pts[j]) < 0 })
+		return pts, nil
+
+	case LineStringer:
+		return LineString(normalizeLineString(gg.Verticies())), nil
+
+	case MultiLineStringer:
+		lss := gg.LineStrings()
+		mls := make(MultiLineString, len(lss))
+		for i := range lss {
+			mls[i] = normalizeLineString(lss[i])
+		}
+		sort.SliceStable(mls, func(i, j int) bool { return pointsCompare(mls[i], mls[j]) < 0 })
+		return mls, nil
+
+	case Polygoner:
+		return Polygon(normalizePolygon(gg.LinearRings())), nil
+
+	case MultiPolygoner:
+		plygs := gg.Polygons()
+		mplyg := make(MultiPolygon, len(plygs))
+		for i := range plygs {
+			mplyg[i] = normalizePolygon(plygs[i])
+		}
+		sort.SliceStable(mplyg, func(i, j int) bool {
+			for k := 0; k < len(mplyg[i]) && k < len(mplyg[j]); k++ {
+				if c := pointsCompare(mplyg[i][k], mplyg[j][k]); c != 0 {
+					return c < 0
+				}
+			}
+			return len(mplyg[i]) < len(mplyg[j])
+		})
+		return mplyg, nil
+
+	case Collectioner:
+		geos := gg.Geometries()
+		coll := make(Collection, len(geos))
+		for i := range geos {
+			ng, err := Normalize(geos[i])
+			if err != nil {
+				return nil, err
+			}
+			coll[i] = ng
+		}
+		return coll, nil
+
+	default:
+		return nil, ErrUnknownGeometry{Geom: g}
+	}
+}
@@ -0,0 +1,94 @@
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	type tcase struct {
+		geom     Geometry
+		expected Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Normalize(tc.geom)
+			if tc.err != nil {
+				if err != tc.err {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("normalize, expected %v got %v", tc.expected, got)
+			}
+			// normalizing is idempotent
+			again, err := Normalize(got)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, again) {
+				t.Errorf("normalize again, expected %v got %v", got, again)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom:     &Point{1, 2},
+			expected: Point{1, 2},
+		},
+		"multipoint": {
+			geom:     MultiPoint{{2, 0}, {1, 5}, {1, 2}},
+			expected: MultiPoint{{1, 2}, {1, 5}, {2, 0}},
+		},
+		"linestring reversed": {
+			geom:     LineString{{5, 5}, {3, 3}, {3, 3}, {0, 0}},
+			expected: LineString{{0, 0}, {3, 3}, {5, 5}},
+		},
+		"multilinestring": {
+			geom:     MultiLineString{{{5, 5}, {6, 6}}, {{1, 1}, {0, 0}}},
+			expected: MultiLineString{{{0, 0}, {1, 1}}, {{5, 5}, {6, 6}}},
+		},
+		"polygon": {
+			geom: Polygon{
+				// closed, rotated, and the wrong winding order.
+				{{10, 10}, {10, 0}, {0, 0}, {0, 10}, {10, 10}},
+				{{7, 7}, {7, 8}, {8, 8}},
+				{{2, 2}, {3, 2}, {3, 3}, {2, 3}},
+			},
+			expected: Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {2, 3}, {3, 3}, {3, 2}},
+				{{7, 7}, {7, 8}, {8, 8}},
+			},
+		},
+		"multipolygon": {
+			geom: MultiPolygon{
+				{{{5, 5}, {6, 5}, {6, 6}}},
+				{{{0, 0}, {1, 0}, {1, 1}}},
+			},
+			expected: MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}}},
+				{{{5, 5}, {6, 5}, {6, 6}}},
+			},
+		},
+		"collection": {
+			geom:     Collection{LineString{{1, 1}, {0, 0}}, Point{3, 3}},
+			expected: Collection{LineString{{0, 0}, {1, 1}}, Point{3, 3}},
+		},
+		"unknown": {
+			geom: Extent{},
+			err:  ErrUnknownGeometry{Extent{}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
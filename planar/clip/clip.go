@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/snapround"
 )
 
 type dclipper struct{}
@@ -14,6 +15,21 @@ func (_ dclipper) Clip(ctx context.Context, geo geom.Geometry, clipbox *geom.Ext
 
 var Default dclipper
 
+// Precise is a planar.Clipper that snap-rounds the clipped geometry to the
+// grid of Model, so that the intersection points with the clipbox are on
+// the grid as well.
+type Precise struct {
+	Model *geom.PrecisionModel
+}
+
+func (p Precise) Clip(ctx context.Context, geo geom.Geometry, clipbox *geom.Extent) (geom.Geometry, error) {
+	g, err := Geometry(ctx, geo, clipbox)
+	if err != nil {
+		return g, err
+	}
+	return snapround.Geometry(ctx, p.Model, g)
+}
+
 // Geometry will return the clipped version of the given geometry.
 func Geometry(ctx context.Context, geo geom.Geometry, clipbox *geom.Extent) (geom.Geometry, error) {
 	if clipbox.IsUniverse() {
@@ -0,0 +1,102 @@
+package clip
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/geom/internal/tcase"
+)
+
+/*
+FuzzGeometry checks that the clipped geometry stays inside the clipbox, and
+keeps the points of the geometry that are inside it. The geometry is WKB;
+the corpus is seeded from the wkb test cases, with a clipbox of the middle
+of their extents.
+*/
+func FuzzGeometry(f *testing.F) {
+	geos, err := tcase.Geometries(tcase.WKBTestdata())
+	if err != nil {
+		f.Fatalf("error getting test cases: %v", err)
+	}
+	for _, g := range geos {
+		data, err := wkb.EncodeBytes(g)
+		if err != nil {
+			f.Fatalf("encode error, expected nil got %v", err)
+		}
+		e, err := geom.NewExtentFromGeometry(g)
+		if err != nil {
+			continue
+		}
+		dx, dy := e.XSpan()/4, e.YSpan()/4
+		f.Add(data, e.MinX()+dx, e.MinY()+dy, e.MaxX()-dx, e.MaxY()-dy)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, minx, miny, maxx, maxy float64) {
+		g, err := wkb.DecodeBytes(data)
+		if err != nil {
+			return
+		}
+		pts, err := geom.GetCoordinates(g)
+		if err != nil || len(pts) > 1000 {
+			return
+		}
+		for _, v := range append(pts, geom.Point{minx, miny}, geom.Point{maxx, maxy}) {
+			if math.IsNaN(v[0]) || math.IsInf(v[0], 0) || math.IsNaN(v[1]) || math.IsInf(v[1], 0) {
+				return
+			}
+		}
+		clipbox := geom.NewExtent([2]float64{minx, miny}, [2]float64{maxx, maxy})
+
+		clipped, err := Geometry(context.Background(), g, clipbox)
+		if err == ErrUnsupportedGeometry {
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if clipped == nil {
+			clipped = geom.Collection{}
+		}
+		cpts, err := geom.GetCoordinates(clipped)
+		if err != nil {
+			t.Fatalf("coordinates error, expected nil got %v", err)
+		}
+
+		// the intersections with the clipbox are calculated, so can be
+		// off a little
+		scale := math.Max(math.Max(math.Abs(minx), math.Abs(maxx)), math.Max(math.Abs(miny), math.Abs(maxy)))
+		for _, pt := range pts {
+			scale = math.Max(scale, math.Max(math.Abs(pt[0]), math.Abs(pt[1])))
+		}
+		tol := 1e-9 * math.Max(scale, 1)
+		grown := geom.NewExtent(
+			[2]float64{clipbox.MinX() - tol, clipbox.MinY() - tol},
+			[2]float64{clipbox.MaxX() + tol, clipbox.MaxY() + tol},
+		)
+		kept := make(map[geom.Point]bool, len(cpts))
+		for _, pt := range cpts {
+			if !grown.ContainsPoint(pt) {
+				t.Errorf("point %v is outside the clipbox %v", pt, clipbox)
+			}
+			kept[pt] = true
+		}
+		for _, pt := range pts {
+			inside := clipbox.MinX() < pt[0] && pt[0] < clipbox.MaxX() &&
+				clipbox.MinY() < pt[1] && pt[1] < clipbox.MaxY()
+			if inside && !kept[pt] {
+				t.Errorf("point %v inside the clipbox %v, expected kept", pt, clipbox)
+			}
+		}
+
+		if mls, ok := clipped.(geom.MultiLineString); ok {
+			for i, ls := range mls {
+				if len(ls) < 2 {
+					t.Errorf("line %v, expected at least 2 points got %v", i, ls)
+				}
+			}
+		}
+	})
+}
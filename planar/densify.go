@@ -0,0 +1,169 @@
+package planar
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// EarthRadius is the mean radius of the earth in meters, used by
+// DensifyGreatCircle.
+const EarthRadius = 6371008.8
+
+// ErrInvalidMaxSegmentLength is returned when the max segment length is not
+// larger then zero.
+type ErrInvalidMaxSegmentLength float64
+
+func (e ErrInvalidMaxSegmentLength) Error() string {
+	return fmt.Sprintf("planar: max segment length must be larger then zero, got %v", float64(e))
+}
+
+// segmentDensifier returns the points to add between a and b, not including a or b.
+type segmentDensifier func(a, b [2]float64) [][2]float64
+
+func (fn segmentDensifier) points(pts [][2]float64, isClosed bool) [][2]float64 {
+	if len(pts) < 2 {
+		return pts
+	}
+	ret := make([][2]float64, 0, len(pts))
+	for i := 1; i < len(pts); i++ {
+		ret = append(ret, pts[i-1])
+		ret = append(ret, fn(pts[i-1], pts[i])...)
+	}
+	ret = append(ret, pts[len(pts)-1])
+	if isClosed && pts[0] != pts[len(pts)-1] {
+		ret = append(ret, fn(pts[len(pts)-1], pts[0])...)
+	}
+	return ret
+}
+
+func (fn segmentDensifier) rings(rings [][][2]float64, isClosed bool) [][][2]float64 {
+	ret := make([][][2]float64, len(rings))
+	for i := range rings {
+		ret[i] = fn.points(rings[i], isClosed)
+	}
+	return ret
+}
+
+func (fn segmentDensifier) geometry(geometry geom.Geometry) geom.Geometry {
+	switch gg := geometry.(type) {
+
+	case geom.Collectioner:
+
+		geos := gg.Geometries()
+		coll := make([]geom.Geometry, len(geos))
+		for i := range geos {
+			coll[i] = fn.geometry(geos[i])
+		}
+		return geom.Collection(coll)
+
+	case geom.MultiPolygoner:
+
+		plys := gg.Polygons()
+		mply := make([][][][2]float64, len(plys))
+		for i := range plys {
+			mply[i] = fn.rings(plys[i], true)
+		}
+		return geom.MultiPolygon(mply)
+
+	case geom.Polygoner:
+
+		return geom.Polygon(fn.rings(gg.LinearRings(), true))
+
+	case geom.MultiLineStringer:
+
+		return geom.MultiLineString(fn.rings(gg.LineStrings(), false))
+
+	case geom.LineStringer:
+
+		return geom.LineString(fn.points(gg.Verticies(), false))
+
+	default: // Points, MutliPoints or anything else.
+		return geometry
+
+	}
+}
+
+// Densify adds vertices to the geometry so that no segment is longer than
+// maxSegLen. The added vertices are evenly spaced along the original segment.
+// The closing segment of polygon rings is densified as well; the rings are
+// left open or closed as they were.
+func Densify(geometry geom.Geometry, maxSegLen float64) (geom.Geometry, error) {
+	if !(maxSegLen > 0) {
+		return nil, ErrInvalidMaxSegmentLength(maxSegLen)
+	}
+	fn := func(a, b [2]float64) [][2]float64 {
+		n := int(math.Ceil(PointDistance(geom.Point(a), geom.Point(b)) / maxSegLen))
+		pts := make([][2]float64, 0, n)
+		for i := 1; i < n; i++ {
+			t := float64(i) / float64(n)
+			pts = append(pts, [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])})
+		}
+		return pts
+	}
+	return segmentDensifier(fn).geometry(geometry), nil
+}
+
+// DensifyGreatCircle is like Densify, but for geometries in longitude and
+// latitude degrees. The vertices are added along the great circle between
+// the two end points of each segment, and maxSegLen is in meters. Segments
+// between antipodal points have no single great circle and are left alone.
+func DensifyGreatCircle(geometry geom.Geometry, maxSegLen float64) (geom.Geometry, error) {
+	if !(maxSegLen > 0) {
+		return nil, ErrInvalidMaxSegmentLength(maxSegLen)
+	}
+	fn := func(a, b [2]float64) [][2]float64 {
+		va, vb := toVector(a), toVector(b)
+		// the angle between the two points
+		d := math.Atan2(norm(cross(va, vb)), dot(va, vb))
+		n := int(math.Ceil(d * EarthRadius / maxSegLen))
+		sind := math.Sin(d)
+		if n < 2 || sind == 0 {
+			return nil
+		}
+		pts := make([][2]float64, 0, n)
+		for i := 1; i < n; i++ {
+			t := float64(i) / float64(n)
+			// spherical linear interpolation
+			fa, fb := math.Sin((1-t)*d)/sind, math.Sin(t*d)/sind
+			pts = append(pts, fromVector([3]float64{
+				fa*va[0] + fb*vb[0],
+				fa*va[1] + fb*vb[1],
+				fa*va[2] + fb*vb[2],
+			}))
+		}
+		return pts
+	}
+	return segmentDensifier(fn).geometry(geometry), nil
+}
+
+// toVector returns the unit vector of the longitude, latitude point.
+func toVector(pt [2]float64) [3]float64 {
+	lon, lat := pt[0]*Rad, pt[1]*Rad
+	return [3]float64{
+		math.Cos(lat) * math.Cos(lon),
+		math.Cos(lat) * math.Sin(lon),
+		math.Sin(lat),
+	}
+}
+
+// fromVector returns the longitude, latitude point of the vector.
+func fromVector(v [3]float64) [2]float64 {
+	return [2]float64{
+		math.Atan2(v[1], v[0]) / Rad,
+		math.Atan2(v[2], math.Hypot(v[0], v[1])) / Rad,
+	}
+}
+
+func dot(a, b [3]float64) float64 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func norm(v [3]float64) float64 { return math.Sqrt(dot(v, v)) }
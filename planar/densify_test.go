@@ -0,0 +1,126 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestDensify(t *testing.T) {
+	type tcase struct {
+		geom      geom.Geometry
+		maxSegLen float64
+		expected  geom.Geometry
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Densify(tc.geom, tc.maxSegLen)
+			if tc.err != nil {
+				if err != tc.err {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.GeometryEqual(tc.expected, got) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom:      geom.Point{1, 2},
+			maxSegLen: 1,
+			expected:  geom.Point{1, 2},
+		},
+		"linestring": {
+			geom:      geom.LineString{{0, 0}, {10, 0}, {10, 2}},
+			maxSegLen: 4,
+			expected:  geom.LineString{{0, 0}, {10.0 / 3, 0}, {20.0 / 3, 0}, {10, 0}, {10, 2}},
+		},
+		"exact length": {
+			geom:      geom.LineString{{0, 0}, {0, 4}},
+			maxSegLen: 2,
+			expected:  geom.LineString{{0, 0}, {0, 2}, {0, 4}},
+		},
+		"polygon closing segment": {
+			geom:      geom.Polygon{{{0, 0}, {2, 0}, {0, 2}}},
+			maxSegLen: 1.5,
+			expected: geom.Polygon{{
+				{0, 0}, {1, 0}, {2, 0}, {1, 1}, {0, 2}, {0, 1},
+			}},
+		},
+		"closed polygon ring": {
+			geom:      geom.Polygon{{{0, 0}, {2, 0}, {0, 2}, {0, 0}}},
+			maxSegLen: 1.5,
+			expected: geom.Polygon{{
+				{0, 0}, {1, 0}, {2, 0}, {1, 1}, {0, 2}, {0, 1}, {0, 0},
+			}},
+		},
+		"multilinestring": {
+			geom:      geom.MultiLineString{{{0, 0}, {2, 0}}, {{0, 1}, {0, 2}}},
+			maxSegLen: 1,
+			expected:  geom.MultiLineString{{{0, 0}, {1, 0}, {2, 0}}, {{0, 1}, {0, 2}}},
+		},
+		"collection": {
+			geom:      geom.Collection{geom.Point{0, 0}, geom.LineString{{0, 0}, {2, 0}}},
+			maxSegLen: 1,
+			expected:  geom.Collection{geom.Point{0, 0}, geom.LineString{{0, 0}, {1, 0}, {2, 0}}},
+		},
+		"zero length": {
+			geom:      geom.LineString{{0, 0}, {2, 0}},
+			maxSegLen: 0,
+			err:       ErrInvalidMaxSegmentLength(0),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDensifyGreatCircle(t *testing.T) {
+	// along the equator the great circle is the straight line.
+	got, err := DensifyGreatCircle(geom.LineString{{0, 0}, {90, 0}}, EarthRadius*math.Pi/4)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := geom.LineString{{0, 0}, {45, 0}, {90, 0}}
+	if !cmp.GeometryEqual(expected, got) {
+		t.Errorf("equator, expected %v got %v", expected, got)
+	}
+
+	// going from (0, 45) to (180, 45) crosses the pole.
+	got, err = DensifyGreatCircle(geom.LineString{{0, 45}, {180, 45}}, EarthRadius*math.Pi/4)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	ls := got.(geom.LineString)
+	if len(ls) != 3 || !cmp.Float(ls[1][1], 90) {
+		t.Errorf("pole, expected a mid point at the pole got %v", ls)
+	}
+
+	// the great circle route goes further north than the chord.
+	got, err = DensifyGreatCircle(geom.LineString{{-100, 50}, {20, 50}}, 100000)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	ls = got.(geom.LineString)
+	mid := ls[len(ls)/2]
+	if mid[1] <= 50 {
+		t.Errorf("great circle, expected the mid point north of 50 got %v", mid)
+	}
+	for i := 1; i < len(ls); i++ {
+		a, b := toVector(ls[i-1]), toVector(ls[i])
+		if d := math.Atan2(norm(cross(a, b)), dot(a, b)) * EarthRadius; d > 100000+1e-6 {
+			t.Errorf("segment %v, expected at most 100000m got %v", i, d)
+		}
+	}
+}
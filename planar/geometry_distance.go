@@ -0,0 +1,342 @@
+package planar
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/rtreego"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+// ErrEmptyGeometry is returned when a distance is asked for a geometry with
+// no points.
+var ErrEmptyGeometry = errors.New("planar: empty geometry")
+
+// parts is a geometry broken into its pieces.
+type parts struct {
+	// segments of all the lines and rings; points are zero length segments.
+	segs []geom.Line
+	// polygons, used to check if something is inside.
+	polygons [][][][2]float64
+	// the first point of each component, used to check if a component
+	// is inside of a polygon.
+	starts [][2]float64
+	// all the vertices in order.
+	vertices [][2]float64
+}
+
+func (p *parts) addLine(pts [][2]float64, isClosed bool) {
+	if len(pts) == 0 {
+		return
+	}
+	p.starts = append(p.starts, pts[0])
+	p.vertices = append(p.vertices, pts...)
+	if len(pts) == 1 {
+		p.segs = append(p.segs, geom.Line{pts[0], pts[0]})
+		return
+	}
+	for i := 1; i < len(pts); i++ {
+		p.segs = append(p.segs, geom.Line{pts[i-1], pts[i]})
+	}
+	if isClosed && pts[0] != pts[len(pts)-1] {
+		p.segs = append(p.segs, geom.Line{pts[len(pts)-1], pts[0]})
+	}
+}
+
+func (p *parts) addPolygon(plyg [][][2]float64) {
+	if len(plyg) == 0 || len(plyg[0]) == 0 {
+		return
+	}
+	p.polygons = append(p.polygons, plyg)
+	for i := range plyg {
+		p.addLine(plyg[i], true)
+	}
+}
+
+func (p *parts) add(g geom.Geometry) error {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		p.addLine([][2]float64{gg.XY()}, false)
+	case geom.MultiPointer:
+		for _, pt := range gg.Points() {
+			p.addLine([][2]float64{pt}, false)
+		}
+	case geom.LineStringer:
+		p.addLine(gg.Verticies(), false)
+	case geom.MultiLineStringer:
+		for _, ls := range gg.LineStrings() {
+			p.addLine(ls, false)
+		}
+	case geom.Polygoner:
+		p.addPolygon(gg.LinearRings())
+	case geom.MultiPolygoner:
+		for _, plyg := range gg.Polygons() {
+			p.addPolygon(plyg)
+		}
+	case geom.Collectioner:
+		for _, cg := range gg.Geometries() {
+			if err := p.add(cg); err != nil {
+				return err
+			}
+		}
+	default:
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+func newParts(g geom.Geometry) (*parts, error) {
+	p := new(parts)
+	if err := p.add(g); err != nil {
+		return nil, err
+	}
+	if len(p.vertices) == 0 {
+		return nil, ErrEmptyGeometry
+	}
+	return p, nil
+}
+
+// covers returns weather the point is inside or on the boundary of one of
+// the polygons.
+func (p *parts) covers(pt [2]float64) bool {
+	for _, plyg := range p.polygons {
+		if polygonCovers(plyg, pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonCovers returns weather the point is inside or on the boundary of
+// the polygon, using the even-odd rule.
+func polygonCovers(plyg [][][2]float64, pt [2]float64) bool {
+	inside := false
+	for _, ring := range plyg {
+		lp := len(ring) - 1
+		for i := range ring {
+			a, b := ring[lp], ring[i]
+			lp = i
+			o := robust.Orient2D(a, b, pt)
+			if o == 0 && geom.NewExtent(a, b).ContainsPoint(pt) {
+				return true
+			}
+			switch {
+			case a[1] <= pt[1] && b[1] > pt[1]:
+				if o < 0 {
+					inside = !inside
+				}
+			case b[1] <= pt[1] && a[1] > pt[1]:
+				if o > 0 {
+					inside = !inside
+				}
+			}
+		}
+	}
+	return inside
+}
+
+type segItem struct {
+	seg  geom.Line
+	rect *rtreego.Rect
+}
+
+func (s *segItem) Bounds() *rtreego.Rect { return s.rect }
+
+// segRect returns the bounding rect of the segment, expanded by d.
+func segRect(seg geom.Line, d float64) *rtreego.Rect {
+	// rtreego does not allow zero lengths
+	const smallep = 0.00001
+	minx, maxx := math.Min(seg[0][0], seg[1][0]), math.Max(seg[0][0], seg[1][0])
+	miny, maxy := math.Min(seg[0][1], seg[1][1]), math.Max(seg[0][1], seg[1][1])
+	rect, err := rtreego.NewRect(
+		rtreego.Point{minx - d, miny - d},
+		[]float64{maxx - minx + 2*d + smallep, maxy - miny + 2*d + smallep},
+	)
+	if err != nil {
+		panic("Assumption broken:" + err.Error())
+	}
+	return rect
+}
+
+func segmentTree(segs []geom.Line) *rtreego.Rtree {
+	items := make([]rtreego.Spatial, len(segs))
+	for i := range segs {
+		items[i] = &segItem{seg: segs[i], rect: segRect(segs[i], 0)}
+	}
+	return rtreego.NewTree(2, 25, 50, items...)
+}
+
+// closestOnSegment returns the point on the segment v, w closest to p.
+func closestOnSegment(p, v, w [2]float64) [2]float64 {
+	l2 := PointDistance2(geom.Point(v), geom.Point(w))
+	if l2 == 0 {
+		return v
+	}
+	t := ((p[0]-v[0])*(w[0]-v[0]) + (p[1]-v[1])*(w[1]-v[1])) / l2
+	t = math.Max(0, math.Min(1, t))
+	return [2]float64{v[0] + t*(w[0]-v[0]), v[1] + t*(w[1]-v[1])}
+}
+
+// segmentsNearest returns the closest points on the two segments and their
+// distance.
+func segmentsNearest(s1, s2 geom.Line) (p1, p2 [2]float64, d float64) {
+	if s1[0] != s1[1] && s2[0] != s2[1] {
+		if pt, ok := SegmentIntersect(s1, s2); ok {
+			return pt, pt, 0
+		}
+	}
+	d = math.Inf(1)
+	try := func(a, b [2]float64) {
+		if dd := PointDistance(geom.Point(a), geom.Point(b)); dd < d {
+			p1, p2, d = a, b, dd
+		}
+	}
+	try(s1[0], closestOnSegment(s1[0], s2[0], s2[1]))
+	try(s1[1], closestOnSegment(s1[1], s2[0], s2[1]))
+	try(closestOnSegment(s2[0], s1[0], s1[1]), s2[0])
+	try(closestOnSegment(s2[1], s1[0], s1[1]), s2[1])
+	return p1, p2, d
+}
+
+// NearestPoints returns the point on a and the point on b that are closest
+// to each other. If the geometries intersect the two points are the same.
+// Points inside of a polygon are considered to intersect the polygon.
+func NearestPoints(a, b geom.Geometry) (pa, pb geom.Point, err error) {
+	ap, err := newParts(a)
+	if err != nil {
+		return pa, pb, err
+	}
+	bp, err := newParts(b)
+	if err != nil {
+		return pa, pb, err
+	}
+
+	// If a component is inside of a polygon of the other geometry, they
+	// intersect.
+	for _, pt := range ap.starts {
+		if bp.covers(pt) {
+			return geom.Point(pt), geom.Point(pt), nil
+		}
+	}
+	for _, pt := range bp.starts {
+		if ap.covers(pt) {
+			return geom.Point(pt), geom.Point(pt), nil
+		}
+	}
+
+	// Search the smaller set of segments against a tree of the larger.
+	swapped := len(ap.segs) > len(bp.segs)
+	if swapped {
+		ap, bp = bp, ap
+	}
+	tree := segmentTree(bp.segs)
+
+	p1, p2, best := segmentsNearest(ap.segs[0], bp.segs[0])
+	for _, seg := range ap.segs {
+		if best == 0 {
+			break
+		}
+		for _, item := range tree.SearchIntersect(segRect(seg, best)) {
+			n1, n2, d := segmentsNearest(seg, item.(*segItem).seg)
+			if d < best {
+				p1, p2, best = n1, n2, d
+				if best == 0 {
+					break
+				}
+			}
+		}
+	}
+	if swapped {
+		p1, p2 = p2, p1
+	}
+	return geom.Point(p1), geom.Point(p2), nil
+}
+
+// Distance returns the minimum euclidean distance between the two
+// geometries, which is 0 if they intersect.
+func Distance(a, b geom.Geometry) (float64, error) {
+	pa, pb, err := NearestPoints(a, b)
+	if err != nil {
+		return 0, err
+	}
+	return PointDistance(pa, pb), nil
+}
+
+// pointToSegments returns the distance from the point to the closest segment
+// in the tree.
+func pointToSegments(tree *rtreego.Rtree, segs []geom.Line, pt [2]float64) float64 {
+	// start with a known distance to limit the search.
+	best := DistanceToLineSegment(geom.Point(pt), geom.Point(segs[0][0]), geom.Point(segs[0][1]))
+	for _, item := range tree.SearchIntersect(segRect(geom.Line{pt, pt}, best)) {
+		seg := item.(*segItem).seg
+		if d := DistanceToLineSegment(geom.Point(pt), geom.Point(seg[0]), geom.Point(seg[1])); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// HausdorffDistance returns the discrete Hausdorff distance between the two
+// geometries: the largest distance from a vertex of one geometry to the
+// lines of the other. Polygons are compared by their rings. As only the
+// vertices are measured the result can be smaller than the true Hausdorff
+// distance; Densify the geometries first for a closer approximation.
+func HausdorffDistance(a, b geom.Geometry) (float64, error) {
+	ap, err := newParts(a)
+	if err != nil {
+		return 0, err
+	}
+	bp, err := newParts(b)
+	if err != nil {
+		return 0, err
+	}
+
+	dist := 0.0
+	directed := func(from, to *parts) {
+		tree := segmentTree(to.segs)
+		for _, pt := range from.vertices {
+			if d := pointToSegments(tree, to.segs, pt); d > dist {
+				dist = d
+			}
+		}
+	}
+	directed(ap, bp)
+	directed(bp, ap)
+	return dist, nil
+}
+
+// FrechetDistance returns the discrete Fréchet distance between the vertices
+// of the two geometries, taken in order. Unlike the Hausdorff distance it
+// takes the direction of the lines into account.
+func FrechetDistance(a, b geom.Geometry) (float64, error) {
+	ap, err := newParts(a)
+	if err != nil {
+		return 0, err
+	}
+	bp, err := newParts(b)
+	if err != nil {
+		return 0, err
+	}
+	p, q := ap.vertices, bp.vertices
+
+	// Only the previous row of the dynamic programming table is needed.
+	prev, cur := make([]float64, len(q)), make([]float64, len(q))
+	for i := range p {
+		for j := range q {
+			d := PointDistance(geom.Point(p[i]), geom.Point(q[j]))
+			switch {
+			case i == 0 && j == 0:
+				cur[j] = d
+			case i == 0:
+				cur[j] = math.Max(cur[j-1], d)
+			case j == 0:
+				cur[j] = math.Max(prev[j], d)
+			default:
+				cur[j] = math.Max(math.Min(prev[j], math.Min(prev[j-1], cur[j-1])), d)
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(q)-1], nil
+}
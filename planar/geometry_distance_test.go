@@ -0,0 +1,194 @@
+package planar
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestNearestPoints(t *testing.T) {
+	type tcase struct {
+		a, b     geom.Geometry
+		pa, pb   geom.Point
+		distance float64
+		err      error
+	}
+
+	square := geom.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			pa, pb, err := NearestPoints(tc.a, tc.b)
+			if tc.err != nil {
+				if err != tc.err {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.PointerEqual(tc.pa, pa) || !cmp.PointerEqual(tc.pb, pb) {
+				t.Errorf("points, expected %v %v got %v %v", tc.pa, tc.pb, pa, pb)
+			}
+			d, err := Distance(tc.a, tc.b)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.Float(tc.distance, d) {
+				t.Errorf("distance, expected %v got %v", tc.distance, d)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"points": {
+			a:        geom.Point{0, 0},
+			b:        geom.MultiPoint{{3, 4}, {10, 10}},
+			pa:       geom.Point{0, 0},
+			pb:       geom.Point{3, 4},
+			distance: 5,
+		},
+		"point to line": {
+			a:        geom.Point{5, 5},
+			b:        geom.LineString{{0, 0}, {10, 0}},
+			pa:       geom.Point{5, 5},
+			pb:       geom.Point{5, 0},
+			distance: 5,
+		},
+		"crossing lines": {
+			a:  geom.LineString{{0, 0}, {10, 10}},
+			b:  geom.LineString{{0, 10}, {10, 0}},
+			pa: geom.Point{5, 5},
+			pb: geom.Point{5, 5},
+		},
+		"parallel lines": {
+			a:        geom.LineString{{0, 0}, {10, 0}},
+			b:        geom.LineString{{12, 1}, {20, 1}},
+			pa:       geom.Point{10, 0},
+			pb:       geom.Point{12, 1},
+			distance: math.Sqrt(5),
+		},
+		"point in polygon": {
+			a:  square,
+			b:  geom.Point{2, 2},
+			pa: geom.Point{2, 2},
+			pb: geom.Point{2, 2},
+		},
+		"point in hole": {
+			a:        square,
+			b:        geom.Point{5, 5.5},
+			pa:       geom.Point{5, 6},
+			pb:       geom.Point{5, 5.5},
+			distance: 0.5,
+		},
+		"polygon to polygon": {
+			a:        square,
+			b:        geom.Polygon{{{13, 0}, {20, 0}, {20, 4}}},
+			pa:       geom.Point{10, 0},
+			pb:       geom.Point{13, 0},
+			distance: 3,
+		},
+		"collection": {
+			a:        geom.Collection{geom.Point{100, 100}, geom.LineString{{0, -2}, {10, -2}}},
+			b:        square,
+			pa:       geom.Point{0, -2},
+			pb:       geom.Point{0, 0},
+			distance: 2,
+		},
+		"empty": {
+			a:   geom.MultiPoint{},
+			b:   square,
+			err: ErrEmptyGeometry,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestNearestPointsBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	line := func(n int, offset float64) geom.LineString {
+		ls := make(geom.LineString, n)
+		for i := range ls {
+			ls[i] = [2]float64{rnd.Float64()*100 + offset, rnd.Float64() * 100}
+		}
+		return ls
+	}
+	for i := 0; i < 20; i++ {
+		a, b := line(50, 0), line(200, 150)
+		d, err := Distance(a, b)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		expected := math.Inf(1)
+		for j := 1; j < len(a); j++ {
+			for k := 1; k < len(b); k++ {
+				_, _, dd := segmentsNearest(geom.Line{a[j-1], a[j]}, geom.Line{b[k-1], b[k]})
+				expected = math.Min(expected, dd)
+			}
+		}
+		if !cmp.Float(expected, d) {
+			t.Errorf("distance %v, expected %v got %v", i, expected, d)
+		}
+	}
+}
+
+func TestHausdorffDistance(t *testing.T) {
+	a := geom.LineString{{0, 0}, {100, 0}, {200, 20}}
+	b := geom.LineString{{0, 0}, {200, 20}}
+	// the vertex {100,0} is farthest from b
+	expected := DistanceToLineSegment(geom.Point{100, 0}, geom.Point{0, 0}, geom.Point{200, 20})
+	d, err := HausdorffDistance(a, b)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !cmp.Float(expected, d) {
+		t.Errorf("distance, expected %v got %v", expected, d)
+	}
+	d, err = HausdorffDistance(b, a)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !cmp.Float(expected, d) {
+		t.Errorf("symmetric distance, expected %v got %v", expected, d)
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	a := geom.LineString{{0, 0}, {10, 0}, {20, 0}}
+	// same shape, but reversed: the Hausdorff distance is 0 while the
+	// Fréchet distance is not.
+	b := geom.LineString{{20, 0}, {10, 0}, {0, 0}}
+	d, err := FrechetDistance(a, b)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !cmp.Float(20, d) {
+		t.Errorf("reversed, expected %v got %v", 20, d)
+	}
+	h, err := HausdorffDistance(a, b)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if h != 0 {
+		t.Errorf("hausdorff, expected 0 got %v", h)
+	}
+
+	c := geom.LineString{{0, 1}, {5, 2}, {10, 1}, {20, 1}}
+	d, err = FrechetDistance(a, c)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if expected := math.Sqrt(29); !cmp.Float(expected, d) {
+		t.Errorf("offset, expected %v got %v", expected, d)
+	}
+}
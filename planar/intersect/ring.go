@@ -4,14 +4,17 @@ import (
 	"log"
 
 	"github.com/go-spatial/geom"
-	"github.com/go-spatial/geom/cmp"
 	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/robust"
 )
 
 type Ring struct {
 	segs          []geom.Line
 	index         *SearchSegmentIdxs
 	IncludeBorder bool
+	// Precision, if set, is used to decide if a point is on the border;
+	// points within the tolerance of the precision model are on the border.
+	Precision *geom.PrecisionModel
 
 	bbox geom.Extent
 }
@@ -67,6 +70,14 @@ func (r *Ring) Extent() *geom.Extent {
 // array to avoid allocation of a new []int slice for each ContainsPoint call.
 var staticIdxs = [...]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
 
+// onBorder returns weather pt is on the segment, o is the orientation of pt to the segment.
+func (r *Ring) onBorder(seg geom.Line, pt [2]float64, o float64) bool {
+	if r.Precision.IsFloating() {
+		return o == 0 && seg.ContainsPoint(pt)
+	}
+	return planar.DistanceToLineSegment(geom.Point(pt), geom.Point(seg[0]), geom.Point(seg[1])) <= r.Precision.Tolerance()
+}
+
 func (r *Ring) ContainsPoint(pt [2]float64) bool {
 	if r == nil {
 		return false
@@ -94,52 +105,31 @@ func (r *Ring) ContainsPoint(pt [2]float64) bool {
 		log.Printf("\t SearchIntersect got back (%v):  %+v", len(results), results)
 	}
 
-	count, ok := 0, false
-	var ipt [2]float64
+	count := 0
 	for _, idx := range results {
-		if planar.AreLinesColinear(l, r.segs[idx]) {
-			if debug {
-				log.Printf("\t The lines are colinear.")
-			}
-			if r.segs[idx].ContainsPoint(pt) {
-				// we are on the border, so return what include border tells us to return
-				return r.IncludeBorder
-			}
-			continue
-		}
-		if ipt, ok = planar.SegmentIntersect(l, r.segs[idx]); !ok {
+		a, b := r.segs[idx][0], r.segs[idx][1]
+		o := robust.Orient2D(a, b, pt)
+		if r.onBorder(r.segs[idx], pt, o) {
 			if debug {
-				log.Printf("\t The lines don't intersect %v: %v <=> %v", idx, r.segs[idx], l)
-			}
-			continue
-		}
-		if cmp.PointEqual(ipt, pt) {
-			if debug {
-				log.Printf("\t Intersect point is is the same as match point. %v returning %v", idx, r.IncludeBorder)
+				log.Printf("\t Point is on the border of segment %v, returning %v", idx, r.IncludeBorder)
 			}
 			// we are on the border, so return what include border tells us to return
 			return r.IncludeBorder
 		}
 
-		// check to see if ipt is on the end point of the segment, if so we will
-		// only increment the counter for any lines below the current ray.
-		if cmp.PointEqual(r.segs[idx][0], ipt) {
-			if debug {
-				log.Printf("\t Intersect point on the end point of the segment. %v", idx)
+		// The segment crosses the ray if one end point is above the ray and
+		// the other is not; and it crosses to the left of pt if pt is to the
+		// right of the segment.
+		switch {
+		case a[1] <= pt[1] && b[1] > pt[1]:
+			if o < 0 {
+				count++
 			}
-			if r.segs[idx][1][1] > pt[1] {
-				continue
-			}
-		} else if cmp.PointEqual(r.segs[idx][1], ipt) {
-			if debug {
-				log.Printf("\t Intersect point on the end point of the segment. %v", idx)
-			}
-			if r.segs[idx][0][1] > pt[1] {
-				continue
+		case b[1] <= pt[1] && a[1] > pt[1]:
+			if o > 0 {
+				count++
 			}
 		}
-
-		count++
 	}
 	if debug {
 		log.Printf("\t count is %v", count)
@@ -41,6 +41,10 @@ type event struct {
 type EventQueue struct {
 	events   []event
 	segments []geom.Line
+
+	// Precision, if set, is used to round the intersection points found
+	// and to decide if an intersection point is an end point.
+	Precision *geom.PrecisionModel
 }
 
 func (e *event) Point() geom.Point { return e.ev }
@@ -99,6 +103,13 @@ func NewEventQueue(segments []geom.Line) (eq EventQueue) {
 	return eq
 }
 
+func (eq *EventQueue) pointEqual(p1, p2 [2]float64) bool {
+	if eq.Precision.IsFloating() {
+		return cmp.PointEqual(p1, p2)
+	}
+	return eq.Precision.PointEqual(p1, p2)
+}
+
 func (eq *EventQueue) FindIntersects(ctx context.Context, connected bool, fn func(src, dest int, pt [2]float64) error) error {
 	segmap := make(map[int]struct{})
 	keys := make([]int, 0, 2)
@@ -152,11 +163,12 @@ func (eq *EventQueue) FindIntersects(ctx context.Context, connected bool, fn fun
 				// Check the next edge.
 				continue
 			}
+			ipt = eq.Precision.MakePrecisePoint(ipt)
 			// we need to see if , the ipt is the endpoint of both lines, (it's a connecting point) and
 			// the polygonCheck is true, then it should not count as an intersect.
 			if connected {
-				matchseg := cmp.PointEqual(ipt, seg.Point1().XY()) || cmp.PointEqual(ipt, seg.Point2().XY())
-				matchseg1 := cmp.PointEqual(ipt, seg1.Point1().XY()) || cmp.PointEqual(ipt, seg1.Point2().XY())
+				matchseg := eq.pointEqual(ipt, seg.Point1().XY()) || eq.pointEqual(ipt, seg.Point2().XY())
+				matchseg1 := eq.pointEqual(ipt, seg1.Point1().XY()) || eq.pointEqual(ipt, seg1.Point2().XY())
 				// Check the next edge.
 				if matchseg && matchseg1 {
 					continue
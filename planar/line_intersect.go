@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
 )
 
 const (
@@ -15,17 +16,16 @@ const (
 )
 
 func AreLinesColinear(l1, l2 geom.Line) bool {
+	// both end points of l2 need to be on the line through l1.
+	if robust.Orient2D(l1[0], l1[1], l2[0]) != 0 || robust.Orient2D(l1[0], l1[1], l2[1]) != 0 {
+		return false
+	}
+
 	x1, y1 := l1[0][0], l1[0][1]
 	x2, y2 := l1[1][0], l1[1][1]
 	x3, y3 := l2[0][0], l2[0][1]
 	x4, y4 := l2[1][0], l2[1][1]
 
-	denom := ((x1 - x2) * (y3 - y4)) - ((y1 - y2) * (x3 - x4))
-	// The lines are parallel or they overlap fi denom is 0.
-	if denom != 0 {
-		return false
-	}
-
 	// now we just need to see if one of the end points is on the other one.
 	xmin, xmax := x1, x2
 	if x1 > x2 {
@@ -50,11 +50,21 @@ func LineIntersect(l1, l2 geom.Line) (pt [2]float64, ok bool) {
 	x3, y3 := l2.Point1().X(), l2.Point1().Y()
 	x4, y4 := l2.Point2().X(), l2.Point2().Y()
 
-	denom := ((x1 - x2) * (y3 - y4)) - ((y1 - y2) * (x3 - x4))
 	// The lines are parallel or they overlap. No single point.
-	if denom == 0 {
+	if robust.Cross(l1[1], l1[0], l2[1], l2[0]) == 0 {
 		return pt, false
 	}
+	denom := ((x1 - x2) * (y3 - y4)) - ((y1 - y2) * (x3 - x4))
+	if denom == 0 {
+		// The lines are nearly parallel and denom was rounded to zero.
+		o1 := robust.Orient2D(l1[0], l1[1], l2[0])
+		o2 := robust.Orient2D(l1[0], l1[1], l2[1])
+		if o1 == o2 {
+			// too far away to represent
+			return pt, false
+		}
+		return interpolate(l2, o1, o2), true
+	}
 
 	xnom := (((x1 * y2) - (y1 * x2)) * (x3 - x4)) - ((x1 - x2) * ((x3 * y4) - (y3 * x4)))
 	ynom := (((x1 * y2) - (y1 * x2)) * (y3 - y4)) - ((y1 - y2) * ((x3 * y4) - (y3 * x4)))
@@ -133,6 +143,14 @@ func LineIntersectBigFloat(l1, l2 geom.Line) (pt [2]*big.Float, ok bool) {
 
 }
 
+// interpolate returns the point of l2 on the line with the orientations
+// o1 and o2 of its end points; they are in proportion to the distances of
+// the end points from the line.
+func interpolate(l2 geom.Line, o1, o2 float64) [2]float64 {
+	t := o1 / (o1 - o2)
+	return [2]float64{l2[0][0] + t*(l2[1][0]-l2[0][0]), l2[0][1] + t*(l2[1][1]-l2[0][1])}
+}
+
 // SegmentIntersect will find the intersection point (x,y) between two lines if
 // there is one. Ok will be true if it found an intersection point and if the
 // point is on both lines.
@@ -144,19 +162,45 @@ func SegmentIntersect(l1, l2 geom.Line) (pt [2]float64, ok bool) {
 	x3, y3 := l2.Point1().X(), l2.Point1().Y()
 	x4, y4 := l2.Point2().X(), l2.Point2().Y()
 
+	// Use the orientation of the end points to decide if the segments
+	// intersect, as it is robust.
+	o1 := robust.Orient2D(l1[0], l1[1], l2[0])
+	o2 := robust.Orient2D(l1[0], l1[1], l2[1])
+	o3 := robust.Orient2D(l2[0], l2[1], l1[0])
+	o4 := robust.Orient2D(l2[0], l2[1], l1[1])
+
+	// The segments are collinear. No single point.
+	if o1 == 0 && o2 == 0 {
+		return pt, false
+	}
+
+	intersects := !(o1 > 0 && o2 > 0) && !(o1 < 0 && o2 < 0) &&
+		!(o3 > 0 && o4 > 0) && !(o3 < 0 && o4 < 0)
+
+	// If an end point is on the other segment it is the intersection point.
+	switch {
+	case o1 == 0:
+		return l2[0], intersects
+	case o2 == 0:
+		return l2[1], intersects
+	case o3 == 0:
+		return l1[0], intersects
+	case o4 == 0:
+		return l1[1], intersects
+	}
+
 	deltaX12 := x1 - x2
-	deltaX13 := x1 - x3
 	deltaX34 := x3 - x4
 	deltaY12 := y1 - y2
-	deltaY13 := y1 - y3
 	deltaY34 := y3 - y4
 	denom := (deltaX12 * deltaY34) - (deltaY12 * deltaX34)
-
-	// The lines are parallel or they overlap. No single point.
 	if denom == 0 {
-		return pt, false
+		// The segments are nearly parallel and denom was rounded to zero.
+		if !intersects {
+			return pt, false
+		}
+		return interpolate(l2, o1, o2), true
 	}
-
 	xnom := (((x1 * y2) - (y1 * x2)) * deltaX34) - (deltaX12 * ((x3 * y4) - (y3 * x4)))
 	ynom := (((x1 * y2) - (y1 * x2)) * deltaY34) - (deltaY12 * ((x3 * y4) - (y3 * x4)))
 	bx := (xnom / denom)
@@ -167,11 +211,5 @@ func SegmentIntersect(l1, l2 geom.Line) (pt [2]float64, ok bool) {
 	if by == -0 {
 		by = 0
 	}
-
-	t := ((deltaX13 * deltaY34) - (deltaY13 * deltaX34)) / denom
-	u := -((deltaX12 * deltaY13) - (deltaY12 * deltaX13)) / denom
-
-	intersects := u >= 0.0 && u <= 1.0 && t >= 0.0 && t <= 1.0
 	return [2]float64{bx, by}, intersects
-
 }
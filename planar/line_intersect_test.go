@@ -28,6 +28,13 @@ func TestLineIntersect(t *testing.T) {
 	}
 
 	tests := map[string]tcase{
+		"nearly parallel": {
+			// the denominator rounds to zero
+			l1: geom.Line{{0, 0}, {1 + 1.0/(1<<30), 1}},
+			l2: geom.Line{{(2 + 3.0/(1<<30)) / 2, 1 + 1.0/(1<<31)}, {-1.0 / (1 << 31), -1.0 / (1 << 31)}},
+			ok: true,
+			pt: [2]float64{(1 + 1.0/(1<<30)) / 2, 0.5},
+		},
 		"simple": {
 			l1: geom.Line{{-10, 0}, {10, 0}},
 			l2: geom.Line{{0, 10}, {0, -10}},
@@ -78,6 +85,13 @@ func TestSegmentIntersect(t *testing.T) {
 	}
 
 	tests := map[string]tcase{
+		"nearly parallel": {
+			// the denominator rounds to zero
+			l1: geom.Line{{0, 0}, {1 + 1.0/(1<<30), 1}},
+			l2: geom.Line{{(2 + 3.0/(1<<30)) / 2, 1 + 1.0/(1<<31)}, {-1.0 / (1 << 31), -1.0 / (1 << 31)}},
+			ok: true,
+			pt: [2]float64{(1 + 1.0/(1<<30)) / 2, 0.5},
+		},
 		"simple": {
 			l1: geom.Line{{-10, 0}, {10, 0}},
 			l2: geom.Line{{0, 10}, {0, -10}},
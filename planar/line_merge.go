@@ -0,0 +1,106 @@
+package planar
+
+import (
+	"github.com/go-spatial/geom"
+)
+
+// lineEnd is one of the end points of a line string
+type lineEnd struct {
+	line    int
+	atStart bool
+}
+
+// LineMerge merges line strings that touch at their end points into
+// maximal chains. Line strings are only joined at points where exactly two
+// line strings end; points where three or more meet, or where a line string
+// ends on its own, end the chain. Closed loops are returned as closed line
+// strings. Where line strings have to be reversed to be joined the chain
+// follows the direction of the majority of its parts. Line strings with less
+// than two points are dropped.
+func LineMerge(mls geom.MultiLineString) geom.MultiLineString {
+	lines := make([][][2]float64, 0, len(mls))
+	for i := range mls {
+		if len(mls[i]) < 2 {
+			continue
+		}
+		lines = append(lines, mls[i])
+	}
+
+	nodes := make(map[[2]float64][]lineEnd)
+	for i, ls := range lines {
+		nodes[ls[0]] = append(nodes[ls[0]], lineEnd{line: i, atStart: true})
+		nodes[ls[len(ls)-1]] = append(nodes[ls[len(ls)-1]], lineEnd{line: i})
+	}
+
+	visited := make([]bool, len(lines))
+
+	// walk follows the chain starting with the given line.
+	walk := func(line int, forward bool) [][2]float64 {
+		var (
+			chain    [][2]float64
+			reversed int
+			count    int
+		)
+		for {
+			visited[line] = true
+			count++
+			ls := lines[line]
+			if !forward {
+				reversed++
+				ls = reverse(ls)
+			}
+			if len(chain) == 0 {
+				chain = append(chain, ls...)
+			} else {
+				chain = append(chain, ls[1:]...)
+			}
+
+			end := nodes[chain[len(chain)-1]]
+			if len(end) != 2 {
+				break
+			}
+			next := end[0]
+			if next.line == line && next.atStart != forward {
+				next = end[1]
+			}
+			if visited[next.line] {
+				break
+			}
+			line, forward = next.line, next.atStart
+		}
+		if reversed*2 > count {
+			chain = reverse(chain)
+		}
+		return chain
+	}
+
+	var merged geom.MultiLineString
+	// Chains start at the nodes that are not of degree two.
+	for i, ls := range lines {
+		if visited[i] {
+			continue
+		}
+		switch {
+		case len(nodes[ls[0]]) != 2:
+			merged = append(merged, walk(i, true))
+		case len(nodes[ls[len(ls)-1]]) != 2:
+			merged = append(merged, walk(i, false))
+		}
+	}
+	// What is left are loops.
+	for i := range lines {
+		if !visited[i] {
+			merged = append(merged, walk(i, true))
+		}
+	}
+	return merged
+}
+
+// reverse returns a reversed copy of the points.
+func reverse(pts [][2]float64) [][2]float64 {
+	ret := make([][2]float64, len(pts))
+	for i := range pts {
+		ret[len(pts)-1-i] = pts[i]
+	}
+	return ret
+}
@@ -0,0 +1,84 @@
+package planar
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestLineMerge(t *testing.T) {
+	type tcase struct {
+		mls      geom.MultiLineString
+		expected geom.MultiLineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := LineMerge(tc.mls)
+			if !cmp.MultiLineEqual(tc.expected, got) {
+				t.Errorf("merged, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"chain": {
+			mls: geom.MultiLineString{
+				{{2, 0}, {3, 0}},
+				{{0, 0}, {1, 0}},
+				{{1, 0}, {2, 0}},
+			},
+			expected: geom.MultiLineString{{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+		},
+		"chain with reversed part": {
+			mls: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{2, 0}, {1, 0}},
+				{{2, 0}, {3, 0}},
+			},
+			expected: geom.MultiLineString{{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+		},
+		"majority direction": {
+			mls: geom.MultiLineString{
+				{{1, 0}, {0, 0}},
+				{{2, 0}, {1, 0}},
+				{{2, 0}, {3, 0}},
+			},
+			expected: geom.MultiLineString{{{3, 0}, {2, 0}, {1, 0}, {0, 0}}},
+		},
+		"stops at junction": {
+			mls: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1, 0}, {2, 0}},
+				{{1, 0}, {1, 1}},
+				{{1, 1}, {1, 2}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1, 0}, {2, 0}},
+				{{1, 0}, {1, 1}, {1, 2}},
+			},
+		},
+		"loop": {
+			mls: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1, 0}, {1, 1}},
+				{{1, 1}, {0, 0}},
+			},
+			expected: geom.MultiLineString{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		},
+		"drops degenerate": {
+			mls: geom.MultiLineString{
+				{{0, 0}},
+				{{0, 0}, {1, 0}},
+			},
+			expected: geom.MultiLineString{{{0, 0}, {1, 0}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,159 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// lineLengths returns the length along the line string to each vertex.
+func lineLengths(pts [][2]float64) []float64 {
+	lengths := make([]float64, len(pts))
+	for i := 1; i < len(pts); i++ {
+		lengths[i] = lengths[i-1] + PointDistance(geom.Point(pts[i-1]), geom.Point(pts[i]))
+	}
+	return lengths
+}
+
+func clampFraction(f float64) float64 { return math.Max(0, math.Min(1, f)) }
+
+// pointAt returns the point at the given length along the line string, and
+// the index of the segment it is on.
+func pointAt(pts [][2]float64, lengths []float64, length float64) ([2]float64, int) {
+	for i := 1; i < len(pts); i++ {
+		if length > lengths[i] && i != len(pts)-1 {
+			continue
+		}
+		seg := lengths[i] - lengths[i-1]
+		if seg == 0 {
+			return pts[i-1], i - 1
+		}
+		t := clampFraction((length - lengths[i-1]) / seg)
+		return [2]float64{
+			pts[i-1][0] + t*(pts[i][0]-pts[i-1][0]),
+			pts[i-1][1] + t*(pts[i][1]-pts[i-1][1]),
+		}, i - 1
+	}
+	return pts[0], 0
+}
+
+// InterpolatePoint returns the point at the given fraction of the length of
+// the line string. The fraction is clamped to [0, 1].
+func InterpolatePoint(ls geom.LineStringer, fraction float64) (geom.Point, error) {
+	pts := ls.Verticies()
+	if len(pts) < 2 {
+		return geom.Point{}, geom.ErrInvalidLineString
+	}
+	lengths := lineLengths(pts)
+	pt, _ := pointAt(pts, lengths, clampFraction(fraction)*lengths[len(lengths)-1])
+	return geom.Point(pt), nil
+}
+
+// closest returns the segment of the line string closest to the point, and
+// how far along the segment, from 0 to 1, the closest point is.
+func closest(pts [][2]float64, pt geom.Pointer) (seg int, t float64) {
+	min := math.Inf(1)
+	for i := 1; i < len(pts); i++ {
+		d := DistanceToLineSegment(pt, geom.Point(pts[i-1]), geom.Point(pts[i]))
+		if d < min {
+			min, seg = d, i-1
+		}
+	}
+	v, w := pts[seg], pts[seg+1]
+	l2 := PointDistance2(geom.Point(v), geom.Point(w))
+	if l2 == 0 {
+		return seg, 0
+	}
+	xy := pt.XY()
+	return seg, clampFraction(((xy[0]-v[0])*(w[0]-v[0]) + (xy[1]-v[1])*(w[1]-v[1])) / l2)
+}
+
+// LocatePoint returns the fraction of the length of the line string at which
+// the point on the line string closest to pt is. If more than one point is
+// as close, the one first along the line string is used.
+func LocatePoint(ls geom.LineStringer, pt geom.Pointer) (float64, error) {
+	pts := ls.Verticies()
+	if len(pts) < 2 {
+		return 0, geom.ErrInvalidLineString
+	}
+	lengths := lineLengths(pts)
+	total := lengths[len(lengths)-1]
+	if total == 0 {
+		return 0, nil
+	}
+	seg, t := closest(pts, pt)
+	return (lengths[seg] + t*(lengths[seg+1]-lengths[seg])) / total, nil
+}
+
+// ProjectPoint returns the point on the line string that is closest to pt.
+func ProjectPoint(ls geom.LineStringer, pt geom.Pointer) (geom.Point, error) {
+	pts := ls.Verticies()
+	if len(pts) < 2 {
+		return geom.Point{}, geom.ErrInvalidLineString
+	}
+	seg, t := closest(pts, pt)
+	v, w := pts[seg], pts[seg+1]
+	return geom.Point{v[0] + t*(w[0]-v[0]), v[1] + t*(w[1]-v[1])}, nil
+}
+
+// Substring returns the part of the line string between the two fractions of
+// its length. The fractions are clamped to [0, 1]; if from is larger than to
+// the returned line string goes in the opposite direction.
+func Substring(ls geom.LineStringer, from, to float64) (geom.LineString, error) {
+	pts := ls.Verticies()
+	if len(pts) < 2 {
+		return nil, geom.ErrInvalidLineString
+	}
+	from, to = clampFraction(from), clampFraction(to)
+	if from > to {
+		sub, err := Substring(ls, to, from)
+		return geom.LineString(reverse(sub)), err
+	}
+
+	lengths := lineLengths(pts)
+	total := lengths[len(lengths)-1]
+	start, i := pointAt(pts, lengths, from*total)
+	end, j := pointAt(pts, lengths, to*total)
+
+	sub := geom.LineString{start}
+	for k := i + 1; k <= j; k++ {
+		if pts[k] != sub[len(sub)-1] {
+			sub = append(sub, pts[k])
+		}
+	}
+	if end != sub[len(sub)-1] || len(sub) == 1 {
+		sub = append(sub, end)
+	}
+	return sub, nil
+}
+
+// LineSplit splits the line string at the point on it closest to pt. If that
+// point is the start or the end of the line string, before or after
+// respectively will be nil.
+func LineSplit(ls geom.LineStringer, pt geom.Pointer) (before, after geom.LineString, err error) {
+	pts := ls.Verticies()
+	if len(pts) < 2 {
+		return nil, nil, geom.ErrInvalidLineString
+	}
+	seg, t := closest(pts, pt)
+	v, w := pts[seg], pts[seg+1]
+	split := [2]float64{v[0] + t*(w[0]-v[0]), v[1] + t*(w[1]-v[1])}
+
+	before = append(geom.LineString{}, pts[:seg+1]...)
+	if split != before[len(before)-1] {
+		before = append(before, split)
+	}
+	after = geom.LineString{split}
+	for _, p := range pts[seg+1:] {
+		if p != after[len(after)-1] {
+			after = append(after, p)
+		}
+	}
+	if len(before) < 2 {
+		before = nil
+	}
+	if len(after) < 2 {
+		after = nil
+	}
+	return before, after, nil
+}
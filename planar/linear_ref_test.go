@@ -0,0 +1,176 @@
+package planar
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+// an L shaped line string of length 20
+var lineL = geom.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+func TestInterpolatePoint(t *testing.T) {
+	type tcase struct {
+		fraction float64
+		expected geom.Point
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := InterpolatePoint(lineL, tc.fraction)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.PointerEqual(tc.expected, got) {
+				t.Errorf("point, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"start":       {fraction: 0, expected: geom.Point{0, 0}},
+		"first half":  {fraction: 0.25, expected: geom.Point{5, 0}},
+		"vertex":      {fraction: 0.5, expected: geom.Point{10, 0}},
+		"second half": {fraction: 0.75, expected: geom.Point{10, 5}},
+		"end":         {fraction: 1, expected: geom.Point{10, 10}},
+		"clamped":     {fraction: 2, expected: geom.Point{10, 10}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := InterpolatePoint(geom.LineString{{1, 1}}, 0.5); err != geom.ErrInvalidLineString {
+		t.Errorf("error, expected %v got %v", geom.ErrInvalidLineString, err)
+	}
+}
+
+func TestLocateAndProjectPoint(t *testing.T) {
+	type tcase struct {
+		pt        geom.Point
+		fraction  float64
+		projected geom.Point
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			f, err := LocatePoint(lineL, tc.pt)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.Float(tc.fraction, f) {
+				t.Errorf("fraction, expected %v got %v", tc.fraction, f)
+			}
+			pt, err := ProjectPoint(lineL, tc.pt)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.PointerEqual(tc.projected, pt) {
+				t.Errorf("projected, expected %v got %v", tc.projected, pt)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"on line":       {pt: geom.Point{5, 0}, fraction: 0.25, projected: geom.Point{5, 0}},
+		"below":         {pt: geom.Point{5, -3}, fraction: 0.25, projected: geom.Point{5, 0}},
+		"right":         {pt: geom.Point{12, 5}, fraction: 0.75, projected: geom.Point{10, 5}},
+		"before start":  {pt: geom.Point{-5, -5}, fraction: 0, projected: geom.Point{0, 0}},
+		"past end":      {pt: geom.Point{10, 15}, fraction: 1, projected: geom.Point{10, 10}},
+		"equidistant":   {pt: geom.Point{8, 2}, fraction: 0.4, projected: geom.Point{8, 0}},
+		"outer corner":  {pt: geom.Point{12, -2}, fraction: 0.5, projected: geom.Point{10, 0}},
+		"inside corner": {pt: geom.Point{9, 1}, fraction: 0.45, projected: geom.Point{9, 0}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	type tcase struct {
+		from, to float64
+		expected geom.LineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Substring(lineL, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got) != len(tc.expected) {
+				t.Fatalf("substring, expected %v got %v", tc.expected, got)
+			}
+			for i := range got {
+				if !cmp.PointEqual(tc.expected[i], got[i]) {
+					t.Errorf("substring, expected %v got %v", tc.expected, got)
+					break
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"whole":         {from: 0, to: 1, expected: lineL},
+		"across vertex": {from: 0.25, to: 0.75, expected: geom.LineString{{5, 0}, {10, 0}, {10, 5}}},
+		"to vertex":     {from: 0.25, to: 0.5, expected: geom.LineString{{5, 0}, {10, 0}}},
+		"from vertex":   {from: 0.5, to: 0.75, expected: geom.LineString{{10, 0}, {10, 5}}},
+		"one segment":   {from: 0.1, to: 0.2, expected: geom.LineString{{2, 0}, {4, 0}}},
+		"reversed":      {from: 0.75, to: 0.25, expected: geom.LineString{{10, 5}, {10, 0}, {5, 0}}},
+		"a point":       {from: 0.25, to: 0.25, expected: geom.LineString{{5, 0}, {5, 0}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLineSplit(t *testing.T) {
+	type tcase struct {
+		pt     geom.Point
+		before geom.LineString
+		after  geom.LineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			before, after, err := LineSplit(lineL, tc.pt)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if tc.before == nil && before != nil || tc.before != nil && !cmp.LineStringEqual(tc.before, before) {
+				t.Errorf("before, expected %v got %v", tc.before, before)
+			}
+			if tc.after == nil && after != nil || tc.after != nil && !cmp.LineStringEqual(tc.after, after) {
+				t.Errorf("after, expected %v got %v", tc.after, after)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"middle of segment": {
+			pt:     geom.Point{5, 1},
+			before: geom.LineString{{0, 0}, {5, 0}},
+			after:  geom.LineString{{5, 0}, {10, 0}, {10, 10}},
+		},
+		"at vertex": {
+			pt:     geom.Point{10, 0},
+			before: geom.LineString{{0, 0}, {10, 0}},
+			after:  geom.LineString{{10, 0}, {10, 10}},
+		},
+		"at start": {
+			pt:    geom.Point{-1, 0},
+			after: lineL,
+		},
+		"at end": {
+			pt:     geom.Point{10, 11},
+			before: lineL,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
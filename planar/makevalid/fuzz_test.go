@@ -0,0 +1,133 @@
+package makevalid
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/geom/internal/tcase"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+// crosses reports if the segments cross at a point inside both of them.
+func crosses(a, b geom.Line) bool {
+	o1, o2 := robust.Orient2D(a[0], a[1], b[0]), robust.Orient2D(a[0], a[1], b[1])
+	o3, o4 := robust.Orient2D(b[0], b[1], a[0]), robust.Orient2D(b[0], b[1], a[1])
+	return (o1 > 0 && o2 < 0 || o1 < 0 && o2 > 0) && (o3 > 0 && o4 < 0 || o3 < 0 && o4 > 0)
+}
+
+/*
+checkValid checks that the rings of the multipolygon have at least three
+points and an area, that no two of its segments cross, and that its points
+are in the clipbox, if there is one, grown by tol.
+*/
+func checkValid(t *testing.T, mp geom.MultiPolygon, clipbox *geom.Extent, tol float64) {
+	var segs []geom.Line
+	for i, p := range mp {
+		for j, r := range p {
+			if len(r) < 3 {
+				t.Errorf("ring %v of polygon %v, expected at least 3 points got %v", j, i, r)
+				continue
+			}
+			// the area is taken relative to the first point, so it is not
+			// lost to rounding away from the origin
+			var area float64
+			for k := range r {
+				pt, next := r[k], r[(k+1)%len(r)]
+				area += (pt[0]-r[0][0])*(next[1]-r[0][1]) - (next[0]-r[0][0])*(pt[1]-r[0][1])
+				segs = append(segs, geom.Line{pt, next})
+				if clipbox != nil && (pt[0] < clipbox.MinX()-tol || pt[0] > clipbox.MaxX()+tol ||
+					pt[1] < clipbox.MinY()-tol || pt[1] > clipbox.MaxY()+tol) {
+					t.Errorf("point %v, expected in the clipbox %v", pt, clipbox)
+				}
+			}
+			if area == 0 {
+				t.Errorf("ring %v of polygon %v, expected an area %v", j, i, r)
+			}
+		}
+	}
+	for i := range segs {
+		for j := i + 1; j < len(segs); j++ {
+			if crosses(segs[i], segs[j]) {
+				t.Errorf("segments %v and %v, expected to not cross", segs[i], segs[j])
+			}
+		}
+	}
+}
+
+/*
+FuzzMakevalid checks that makevalid gives valid polygons in the clipbox. The
+geometry is WKB, and a clipbox with no area is none; the corpus is seeded
+from the polygons of the wkb test cases, without and with a clipbox of the
+middle of their extents.
+*/
+func FuzzMakevalid(f *testing.F) {
+	geos, err := tcase.Geometries(tcase.WKBTestdata())
+	if err != nil {
+		f.Fatalf("error getting test cases: %v", err)
+	}
+	for _, g := range geos {
+		switch g.(type) {
+		case geom.Polygoner, geom.MultiPolygoner:
+		default:
+			continue
+		}
+		data, err := wkb.EncodeBytes(g)
+		if err != nil {
+			f.Fatalf("encode error, expected nil got %v", err)
+		}
+		e, err := geom.NewExtentFromGeometry(g)
+		if err != nil {
+			continue
+		}
+		dx, dy := e.XSpan()/4, e.YSpan()/4
+		f.Add(data, 0.0, 0.0, 0.0, 0.0)
+		f.Add(data, e.MinX()+dx, e.MinY()+dy, e.MaxX()-dx, e.MaxY()-dy)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, minx, miny, maxx, maxy float64) {
+		g, err := wkb.DecodeBytes(data)
+		if err != nil {
+			return
+		}
+		switch g.(type) {
+		case geom.Polygoner, geom.MultiPolygoner:
+		default:
+			return
+		}
+		pts, err := geom.GetCoordinates(g)
+		if err != nil || len(pts) > 1000 {
+			return
+		}
+		scale := 1.0
+		for _, v := range append(pts, geom.Point{minx, miny}, geom.Point{maxx, maxy}) {
+			if math.IsNaN(v[0]) || math.IsInf(v[0], 0) || math.IsNaN(v[1]) || math.IsInf(v[1], 0) {
+				return
+			}
+			scale = math.Max(scale, math.Max(math.Abs(v[0]), math.Abs(v[1])))
+		}
+		var clipbox *geom.Extent
+		if minx != maxx && miny != maxy {
+			clipbox = geom.NewExtent([2]float64{minx, miny}, [2]float64{maxx, maxy})
+		}
+
+		mv := Makevalid{}
+		vg, _, err := mv.Makevalid(context.Background(), g, clipbox)
+		if err != nil {
+			// the geometry can not always be triangulated
+			return
+		}
+		mp, ok := vg.(*geom.MultiPolygon)
+		if !ok {
+			t.Fatalf("geometry, expected *geom.MultiPolygon got %T", vg)
+		}
+		if mp == nil {
+			return
+		}
+		// the intersections with the clipbox are calculated, so can be off
+		// a little
+		checkValid(t, *mp, clipbox, 1e-9*scale)
+	})
+}
@@ -152,3 +152,29 @@ func TestNewFromPolygon(t *testing.T) {
 		t.Run(name, func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestNewFromPolygonsClipbox(t *testing.T) {
+	type tcase struct {
+		pt    [2]float64
+		label planar.Label
+	}
+	clipbox := geom.NewExtent([2]float64{0, 0}, [2]float64{5, 5})
+	hm := MustNewFromPolygons(clipbox, geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}.LinearRings())
+
+	fn := func(t *testing.T, tc tcase) {
+		if label := hm.LabelFor(tc.pt); label != tc.label {
+			t.Errorf("label, expected %v got %v", tc.label, label)
+		}
+	}
+	tests := map[string]tcase{
+		"inside the clipbox":  {pt: [2]float64{2, 2}, label: planar.Inside},
+		"outside the clipbox": {pt: [2]float64{7, 7}, label: planar.Outside},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+	if extent := hm.Extent(); extent != clipbox.Extent() {
+		t.Errorf("extent, expected %v got %v", clipbox.Extent(), extent)
+	}
+}
@@ -33,6 +33,9 @@ func NewFromPolygons(clipbox *geom.Extent, plys ...[][][2]float64) (*PolygonHM,
 	hm := &PolygonHM{
 		clipBox: new(geom.Extent),
 	}
+	if clipbox != nil {
+		*hm.clipBox = *clipbox
+	}
 	if debug {
 		log.Printf("Setting up Hitmap")
 		log.Printf("Polygons provided % 5v", len(plys))
@@ -12,14 +12,22 @@ import (
 	"github.com/go-spatial/geom/planar/intersect"
 	"github.com/go-spatial/geom/planar/makevalid/hitmap"
 	"github.com/go-spatial/geom/planar/makevalid/walker"
+	"github.com/go-spatial/geom/planar/snapround"
 )
 
 type Makevalid struct {
+	// Hitmap, if set, is used to label the triangles of the geometry as
+	// inside or outside. If nil a hitmap is built from the geometry.
 	Hitmap planar.HitMapper
 	// Currently not used, but once we have the IsValid function, we can use this instead
 	// Of running the MakeValid routine on a Geometry that is alreayd valid.
 	// Used to clip geometries that are not Polygon and MultiPolygons
 	Clipper planar.Clipper
+	// Precision, if set, is the grid the output is snap-rounded to. Polygons
+	// are snap-rounded before they are processed, so the result is
+	// deterministic and stays valid after quantization; lines and points
+	// are snap-rounded after they are clipped.
+	Precision *geom.PrecisionModel
 }
 
 // asSegments calls the AsSegments functions and flattens the array of segments that are returned.
@@ -80,6 +88,13 @@ func (a ByXYLine) Less(i, j int) bool {
 // 3. line segments are generally unique.
 // 4. line segments outside of the clipbox will be clipped
 func Destructure(ctx context.Context, clipbox *geom.Extent, multipolygon *geom.MultiPolygon) (geom.MultiLineString, error) {
+	return DestructureWithPrecision(ctx, nil, clipbox, multipolygon)
+}
+
+// DestructureWithPrecision is like Destructure, but the segments and the clipbox are
+// first snap-rounded to the given precision model, so all the returned segments have
+// their end points on the grid.
+func DestructureWithPrecision(ctx context.Context, pm *geom.PrecisionModel, clipbox *geom.Extent, multipolygon *geom.MultiPolygon) (geom.MultiLineString, error) {
 
 	segments, err := asSegments(*multipolygon)
 	if err != nil {
@@ -96,6 +111,9 @@ func Destructure(ctx context.Context, clipbox *geom.Extent, multipolygon *geom.M
 	// Let's see if our clip box is bigger then our polygon.
 	// if it is we don't need the clip box.
 	hasClipbox := clipbox != nil && !clipbox.Contains(gext)
+	if hasClipbox && !pm.IsFloating() {
+		clipbox = geom.NewExtent(pm.MakePrecisePoint(clipbox.Min()), pm.MakePrecisePoint(clipbox.Max()))
+	}
 	// Let's get the edges of our clipbox; as segments and add it to the begining.
 	if hasClipbox {
 		edges := clipbox.Edges(nil)
@@ -104,10 +122,24 @@ func Destructure(ctx context.Context, clipbox *geom.Extent, multipolygon *geom.M
 			geom.Line(edges[2]), geom.Line(edges[3]),
 		}, segments...)
 	}
+	if !pm.IsFloating() {
+		// Snap-rounding splits the segments at all the places they cross.
+		snapped, err := snapround.Lines(ctx, pm, segments)
+		if err != nil {
+			return nil, err
+		}
+		segments = segments[:0]
+		for i := range snapped {
+			for j := 1; j < len(snapped[i]); j++ {
+				segments = append(segments, geom.Line{snapped[i][j-1], snapped[i][j]})
+			}
+		}
+	}
 	ipts := make(map[int][][2]float64)
 
 	// Lets find all the places we need to split the lines on.
 	eq := intersect.NewEventQueue(segments)
+	eq.Precision = pm
 	eq.FindIntersects(ctx, true, func(src, dest int, pt [2]float64) error {
 		ipts[src] = append(ipts[src], pt)
 		ipts[dest] = append(ipts[dest], pt)
@@ -165,10 +197,24 @@ func unique(segs []geom.Line) {
 }
 
 func (mv *Makevalid) makevalidPolygon(ctx context.Context, clipbox *geom.Extent, multipolygon *geom.MultiPolygon) (*geom.MultiPolygon, error) {
+	if !mv.Precision.IsFloating() {
+		if debug {
+			log.Printf("*Step  0 : Snap-round the geometry to the precision model.")
+		}
+		g, err := snapround.Geometry(ctx, mv.Precision, *multipolygon)
+		if err != nil {
+			return nil, err
+		}
+		mp, _ := g.(geom.MultiPolygon)
+		if len(mp) == 0 {
+			return nil, nil
+		}
+		multipolygon = &mp
+	}
 	if debug {
 		log.Printf("*Step  1 : Destructure the geometry into segments w/ the clipbox applied.")
 	}
-	segs, err := Destructure(ctx, clipbox, multipolygon)
+	segs, err := DestructureWithPrecision(ctx, mv.Precision, clipbox, multipolygon)
 	if err != nil {
 		if debug {
 			log.Printf("Destructure returned err %v", err)
@@ -187,11 +233,17 @@ func (mv *Makevalid) makevalidPolygon(ctx context.Context, clipbox *geom.Extent,
 		log.Printf("Step   2 : Convert segments to linestrings to use in triangleuation.")
 	}
 
-	hm, err := hitmap.NewFromPolygons(nil, (*multipolygon)...)
+	hm := mv.Hitmap
+	if hm == nil {
+		hm, err = hitmap.NewFromPolygons(nil, (*multipolygon)...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	triangles, err := InsideTrianglesForGeometry(ctx, segs, hm)
 	if err != nil {
 		return nil, err
 	}
-	triangles, err := InsideTrianglesForGeometry(ctx, multipolygon, hm)
 	if debug {
 		log.Printf("Step   5 : generate multipolygon from triangles")
 	}
@@ -207,13 +259,18 @@ func (mv *Makevalid) Makevalid(ctx context.Context, geo geom.Geometry, clipbox *
 
 	case geom.LineStringer, geom.MultiLineStringer, geom.Pointer, geom.MultiPointer:
 		if mv.Clipper != nil {
-			gg, err := mv.Clipper.Clip(ctx, geo, clipbox)
+			geo, err = mv.Clipper.Clip(ctx, geo, clipbox)
 			if err != nil {
 				return nil, false, err
 			}
-			return gg, true, nil
+			didClip = true
+		}
+		// the Clipper need not snap, so it is done here
+		geo, err = snapround.Geometry(ctx, mv.Precision, geo)
+		if err != nil {
+			return nil, false, err
 		}
-		return geo, false, nil
+		return geo, didClip, nil
 	case geom.Polygoner:
 		if debug {
 			log.Printf("Working on Polygoner: %v", geo)
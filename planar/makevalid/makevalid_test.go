@@ -3,11 +3,15 @@ package makevalid
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/clip"
 	"github.com/go-spatial/geom/planar/makevalid/hitmap"
+	"github.com/go-spatial/geom/planar/prepared"
 )
 
 func TestMakeValid(t *testing.T)      { checkMakeValid(t) }
@@ -62,6 +66,12 @@ func checkMakeValid(tb testing.TB) {
 			didClip:              true,
 		}
 	}
+	tests["clipbox"] = tcase{
+		MultiPolygon:         &geom.MultiPolygon{{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}},
+		ClipBox:              geom.NewExtent([2]float64{-1, -1}, [2]float64{5, 5}),
+		ExpectedMultiPolygon: &geom.MultiPolygon{{{{0, 0}, {5, 0}, {5, 5}, {0, 5}}}},
+		didClip:              true,
+	}
 	for name, tc := range tests {
 		tc := tc
 		switch t := tb.(type) {
@@ -77,3 +87,105 @@ func checkMakeValid(tb testing.TB) {
 		}
 	}
 }
+
+func TestMakeValidPrecision(t *testing.T) {
+	mp := &geom.MultiPolygon{
+		{{{0.1, 0.2}, {0.3, 9.8}, {10.2, 10.1}, {9.9, 0.4}}},
+	}
+	hm, err := hitmap.NewFromPolygons(nil, mp.Polygons()...)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	mv := &Makevalid{
+		Hitmap:    hm,
+		Precision: geom.NewFixedPrecisionModel(1),
+	}
+	gmp, _, err := mv.Makevalid(context.Background(), mp, nil)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := &geom.MultiPolygon{
+		{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+	}
+	got, ok := gmp.(geom.MultiPolygoner)
+	if !ok {
+		t.Fatalf("return MultiPolygon, expected MultiPolygon got %T", gmp)
+	}
+	if !cmp.MultiPolygonerEqual(expected, got) {
+		t.Errorf("mulitpolygon, expected %v got %v", expected, got)
+	}
+}
+
+func TestMakeValidPrecisionLines(t *testing.T) {
+	type tcase struct {
+		geo      geom.Geometry
+		clipper  planar.Clipper
+		expected geom.Geometry
+		didClip  bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		mv := &Makevalid{
+			Clipper:   tc.clipper,
+			Precision: geom.NewFixedPrecisionModel(1),
+		}
+		clipbox := geom.NewExtent([2]float64{0, 0}, [2]float64{5, 5})
+		got, didClip, err := mv.Makevalid(context.Background(), tc.geo, clipbox)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if didClip != tc.didClip {
+			t.Errorf("didClip, expected %v got %v", tc.didClip, didClip)
+		}
+		if !reflect.DeepEqual(tc.expected, got) {
+			t.Errorf("geometry, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geo:      geom.Point{1.2, 2.7},
+			expected: geom.Point{1, 3},
+		},
+		"linestring": {
+			geo:      geom.LineString{{0.2, 0.4}, {3.4, 1.6}},
+			expected: geom.LineString{{0, 0}, {3, 2}},
+		},
+		"clipped linestring": {
+			geo:      geom.LineString{{-2.6, 1.2}, {7.3, 1.2}},
+			clipper:  clip.Default,
+			expected: geom.MultiLineString{{{0, 1}, {5, 1}}},
+			didClip:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestMakeValidPreparedHitmap(t *testing.T) {
+	mp := &geom.MultiPolygon{
+		{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+		},
+	}
+	hm, err := prepared.New(mp)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	mv := &Makevalid{Hitmap: hm}
+	gmp, _, err := mv.Makevalid(context.Background(), mp, nil)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	got, ok := gmp.(geom.MultiPolygoner)
+	if !ok {
+		t.Fatalf("return MultiPolygon, expected MultiPolygon got %T", gmp)
+	}
+	if !cmp.MultiPolygonerEqual(mp, got) {
+		t.Errorf("mulitpolygon, expected %v got %v", mp, got)
+	}
+}
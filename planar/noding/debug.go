@@ -0,0 +1,3 @@
+package noding
+
+const debug = false
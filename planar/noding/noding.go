@@ -0,0 +1,161 @@
+/*
+Package noding splits linework at every point where lines touch or cross,
+so the resulting edges only meet at their end points.
+*/
+package noding
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/intersect"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+// Edge is a noded edge, along with the inputs it came from.
+type Edge struct {
+	geom.Line
+	// Sources are the indices of the inputs the edge is part of, in
+	// increasing order. More than one input means the inputs overlapped.
+	Sources []int
+}
+
+// Node returns the lines split at all the points where they touch or cross
+// each other. Edges that are the same, in either direction, are merged and
+// zero length lines are dropped.
+func Node(ctx context.Context, lines []geom.Line) ([]geom.Line, error) {
+	edges, err := NodeEdges(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]geom.Line, len(edges))
+	for i := range edges {
+		ret[i] = edges[i].Line
+	}
+	return ret, nil
+}
+
+// NodeEdges is like Node, but each edge records the indices of the lines it
+// came from.
+func NodeEdges(ctx context.Context, lines []geom.Line) ([]Edge, error) {
+	srcs := make([]int, len(lines))
+	for i := range srcs {
+		srcs[i] = i
+	}
+	return node(ctx, lines, srcs)
+}
+
+// NodeLineStrings nodes the segments of the line strings. The sources of the
+// edges are the indices of the line strings. planar.LineMerge can be used
+// to join the edges back into longer line strings.
+func NodeLineStrings(ctx context.Context, mls geom.MultiLineString) ([]Edge, error) {
+	var (
+		lines []geom.Line
+		srcs  []int
+	)
+	for i, ls := range mls {
+		for j := 1; j < len(ls); j++ {
+			lines = append(lines, geom.Line{ls[j-1], ls[j]})
+			srcs = append(srcs, i)
+		}
+	}
+	return node(ctx, lines, srcs)
+}
+
+func node(ctx context.Context, lines []geom.Line, sources []int) ([]Edge, error) {
+	segs := make([]geom.Line, 0, len(lines))
+	srcs := make([]int, 0, len(lines))
+	for i := range lines {
+		if lines[i][0] != lines[i][1] {
+			segs = append(segs, lines[i])
+			srcs = append(srcs, sources[i])
+		}
+	}
+
+	splits := make([][][2]float64, len(segs))
+	eq := intersect.NewEventQueue(segs)
+	err := eq.FindIntersects(ctx, false, func(src, dest int, pt [2]float64) error {
+		splits[src] = append(splits[src], pt)
+		splits[dest] = append(splits[dest], pt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Intersections of collinear lines are not reported by the event queue,
+	// so also split at the end points of other lines that are on a line.
+	if idx := intersect.NewSearchSegmentIdxs(segs); idx != nil {
+		for i := range segs {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			for _, j := range idx.SearchIntersectIdxs(segs[i]) {
+				if i == j {
+					continue
+				}
+				for _, pt := range segs[j] {
+					if robust.Orient2D(segs[i][0], segs[i][1], pt) == 0 && segs[i].ContainsPoint(pt) {
+						splits[i] = append(splits[i], pt)
+					}
+				}
+			}
+		}
+	}
+
+	seen := make(map[geom.Line]int, len(segs))
+	edges := make([]Edge, 0, len(segs))
+	for i := range segs {
+		pts := append([][2]float64{segs[i][0], segs[i][1]}, splits[i]...)
+		sort.Sort(byParam{line: segs[i], pts: pts})
+		for j := 1; j < len(pts); j++ {
+			if pts[j-1] == pts[j] {
+				continue
+			}
+			l := geom.Line{pts[j-1], pts[j]}
+			key := l
+			if key[1][0] < key[0][0] || (key[1][0] == key[0][0] && key[1][1] < key[0][1]) {
+				key[0], key[1] = key[1], key[0]
+			}
+			if e, ok := seen[key]; ok {
+				edges[e].Sources = addSource(edges[e].Sources, srcs[i])
+				continue
+			}
+			seen[key] = len(edges)
+			edges = append(edges, Edge{Line: l, Sources: []int{srcs[i]}})
+		}
+	}
+	if debug {
+		log.Printf("noded %v lines into %v edges", len(lines), len(edges))
+	}
+	return edges, nil
+}
+
+// addSource adds src to the sorted sources, if it's not already there.
+func addSource(srcs []int, src int) []int {
+	i := sort.SearchInts(srcs, src)
+	if i < len(srcs) && srcs[i] == src {
+		return srcs
+	}
+	srcs = append(srcs, 0)
+	copy(srcs[i+1:], srcs[i:])
+	srcs[i] = src
+	return srcs
+}
+
+// byParam sorts points by their position along the line.
+type byParam struct {
+	line geom.Line
+	pts  [][2]float64
+}
+
+func (bp byParam) param(pt [2]float64) float64 {
+	dx, dy := bp.line[1][0]-bp.line[0][0], bp.line[1][1]-bp.line[0][1]
+	return (pt[0]-bp.line[0][0])*dx + (pt[1]-bp.line[0][1])*dy
+}
+
+func (bp byParam) Len() int           { return len(bp.pts) }
+func (bp byParam) Swap(i, j int)      { bp.pts[i], bp.pts[j] = bp.pts[j], bp.pts[i] }
+func (bp byParam) Less(i, j int) bool { return bp.param(bp.pts[i]) < bp.param(bp.pts[j]) }
@@ -0,0 +1,112 @@
+package noding
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestNodeEdges(t *testing.T) {
+	type tcase struct {
+		lines    []geom.Line
+		expected []Edge
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := NodeEdges(context.Background(), tc.lines)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got) != len(tc.expected) {
+				t.Fatalf("edges, expected %v got %v", tc.expected, got)
+			}
+			for i := range tc.expected {
+				if !reflect.DeepEqual(tc.expected[i], got[i]) {
+					t.Errorf("edge %v, expected %v got %v", i, tc.expected[i], got[i])
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"zero length": {
+			lines: []geom.Line{{{1, 1}, {1, 1}}},
+		},
+		"cross": {
+			lines: []geom.Line{
+				{{0, 0}, {2, 2}},
+				{{0, 2}, {2, 0}},
+			},
+			expected: []Edge{
+				{Line: geom.Line{{0, 0}, {1, 1}}, Sources: []int{0}},
+				{Line: geom.Line{{1, 1}, {2, 2}}, Sources: []int{0}},
+				{Line: geom.Line{{0, 2}, {1, 1}}, Sources: []int{1}},
+				{Line: geom.Line{{1, 1}, {2, 0}}, Sources: []int{1}},
+			},
+		},
+		"t junction": {
+			lines: []geom.Line{
+				{{0, 0}, {4, 0}},
+				{{2, 0}, {2, 2}},
+			},
+			expected: []Edge{
+				{Line: geom.Line{{0, 0}, {2, 0}}, Sources: []int{0}},
+				{Line: geom.Line{{2, 0}, {4, 0}}, Sources: []int{0}},
+				{Line: geom.Line{{2, 0}, {2, 2}}, Sources: []int{1}},
+			},
+		},
+		"overlap": {
+			lines: []geom.Line{
+				{{0, 0}, {4, 0}},
+				{{6, 0}, {2, 0}},
+			},
+			expected: []Edge{
+				{Line: geom.Line{{0, 0}, {2, 0}}, Sources: []int{0}},
+				{Line: geom.Line{{2, 0}, {4, 0}}, Sources: []int{0, 1}},
+				{Line: geom.Line{{6, 0}, {4, 0}}, Sources: []int{1}},
+			},
+		},
+		"duplicates": {
+			lines: []geom.Line{
+				{{0, 0}, {1, 1}},
+				{{1, 1}, {0, 0}},
+				{{0, 0}, {1, 1}},
+			},
+			expected: []Edge{
+				{Line: geom.Line{{0, 0}, {1, 1}}, Sources: []int{0, 1, 2}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestNodeLineStrings(t *testing.T) {
+	mls := geom.MultiLineString{
+		{{0, 0}, {2, 0}, {2, 2}},
+		{{1, -1}, {1, 1}, {3, 1}},
+	}
+	got, err := NodeLineStrings(context.Background(), mls)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := []Edge{
+		{Line: geom.Line{{0, 0}, {1, 0}}, Sources: []int{0}},
+		{Line: geom.Line{{1, 0}, {2, 0}}, Sources: []int{0}},
+		{Line: geom.Line{{2, 0}, {2, 1}}, Sources: []int{0}},
+		{Line: geom.Line{{2, 1}, {2, 2}}, Sources: []int{0}},
+		{Line: geom.Line{{1, -1}, {1, 0}}, Sources: []int{1}},
+		{Line: geom.Line{{1, 0}, {1, 1}}, Sources: []int{1}},
+		{Line: geom.Line{{1, 1}, {2, 1}}, Sources: []int{1}},
+		{Line: geom.Line{{2, 1}, {3, 1}}, Sources: []int{1}},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("edges, expected %v got %v", expected, got)
+	}
+}
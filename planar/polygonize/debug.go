@@ -0,0 +1,3 @@
+package polygonize
+
+const debug = false
@@ -0,0 +1,330 @@
+/*
+Package polygonize builds polygons from linework.
+
+The lines are noded, so that they only meet at their end points, and
+turned into a planar graph. Edges that do not take part in enclosing an
+area are removed: dangles (edges with an end point no other edge touches)
+and cut edges (edges with the same face on both sides). The faces of what
+is left become the polygons.
+
+This lives outside of the planar package as it uses the noding package to
+node the lines, which depends on planar.
+*/
+package polygonize
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/noding"
+	"github.com/go-spatial/geom/planar/prepared"
+)
+
+// Result is what Polygonize found in the linework.
+type Result struct {
+	// Polygons are the areas enclosed by the lines. Exterior rings are
+	// counter-clockwise, and interior rings clockwise.
+	Polygons []geom.Polygon
+	// Dangles are the noded edges that have an end point that no other edge
+	// touches, directly or through other dangles.
+	Dangles []geom.Line
+	// CutEdges are the noded edges that have the same face on both sides.
+	CutEdges []geom.Line
+	// InvalidRings are the rings that enclose no area.
+	InvalidRings []geom.LineString
+}
+
+// Polygonize returns the polygons formed by the given lines. The lines do
+// not need to be noded, nor directed.
+func Polygonize(ctx context.Context, lines []geom.Line) (*Result, error) {
+	segs, err := noding.Node(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+	if debug {
+		log.Printf("polygonize: %v lines noded into %v edges", len(lines), len(segs))
+	}
+
+	g := newGraph(segs)
+	res := new(Result)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res.Dangles = append(res.Dangles, g.removeDangles()...)
+		faces := g.faces()
+		cuts := g.cutEdges(faces)
+		if len(cuts) == 0 {
+			res.Polygons, res.InvalidRings = assemble(g, faces)
+			return res, nil
+		}
+		res.CutEdges = append(res.CutEdges, cuts...)
+	}
+}
+
+// graph is a planar graph of half edges. Edge i is made up of the half
+// edges 2i and 2i+1, which go in opposite directions.
+type graph struct {
+	pts [][2]float64
+	// origin node of each half edge
+	origin []int
+	// out going half edges of each node, sorted counter-clockwise
+	out     [][]int
+	removed []bool
+}
+
+func newGraph(segs []geom.Line) *graph {
+	g := &graph{
+		origin:  make([]int, 0, 2*len(segs)),
+		removed: make([]bool, len(segs)),
+	}
+	nodes := make(map[[2]float64]int)
+	nodeFor := func(pt [2]float64) int {
+		n, ok := nodes[pt]
+		if !ok {
+			n = len(g.pts)
+			nodes[pt] = n
+			g.pts = append(g.pts, pt)
+			g.out = append(g.out, nil)
+		}
+		return n
+	}
+	for i := range segs {
+		a, b := nodeFor(segs[i][0]), nodeFor(segs[i][1])
+		g.origin = append(g.origin, a, b)
+		g.out[a] = append(g.out[a], 2*i)
+		g.out[b] = append(g.out[b], 2*i+1)
+	}
+	for n := range g.out {
+		out := g.out[n]
+		sort.Slice(out, func(i, j int) bool { return g.angle(out[i]) < g.angle(out[j]) })
+	}
+	return g
+}
+
+func sym(he int) int { return he ^ 1 }
+
+func (g *graph) dest(he int) int { return g.origin[sym(he)] }
+
+func (g *graph) line(he int) geom.Line {
+	return geom.Line{g.pts[g.origin[he]], g.pts[g.dest(he)]}
+}
+
+func (g *graph) angle(he int) float64 {
+	a, b := g.pts[g.origin[he]], g.pts[g.dest(he)]
+	return math.Atan2(b[1]-a[1], b[0]-a[0])
+}
+
+func (g *graph) degree(n int) (d int) {
+	for _, he := range g.out[n] {
+		if !g.removed[he/2] {
+			d++
+		}
+	}
+	return d
+}
+
+// removeDangles removes, and returns, the edges that have a node of degree
+// one, until there are none left.
+func (g *graph) removeDangles() (dangles []geom.Line) {
+	var stack []int
+	for n := range g.out {
+		if g.degree(n) == 1 {
+			stack = append(stack, n)
+		}
+	}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, he := range g.out[n] {
+			if g.removed[he/2] {
+				continue
+			}
+			g.removed[he/2] = true
+			dangles = append(dangles, g.line(he))
+			if d := g.dest(he); g.degree(d) == 1 {
+				stack = append(stack, d)
+			}
+		}
+	}
+	return dangles
+}
+
+// next returns the half edge that follows he, with the face of he on its
+// left; it is the first edge clockwise from the reverse of he.
+func (g *graph) next(he int) int {
+	out := g.out[g.dest(he)]
+	rev := sym(he)
+	pos := 0
+	for i := range out {
+		if out[i] == rev {
+			pos = i
+			break
+		}
+	}
+	for i := 1; i <= len(out); i++ {
+		cand := out[(pos-i+len(out))%len(out)]
+		if !g.removed[cand/2] {
+			return cand
+		}
+	}
+	return rev
+}
+
+// faces holds the face number of each half edge, and the half edges
+// around each face; removed half edges have a face of -1.
+type faces struct {
+	of    []int
+	edges [][]int
+}
+
+func (g *graph) faces() faces {
+	f := faces{of: make([]int, len(g.origin))}
+	for i := range f.of {
+		f.of[i] = -1
+	}
+	for he := range g.origin {
+		if g.removed[he/2] || f.of[he] != -1 {
+			continue
+		}
+		id := len(f.edges)
+		var ring []int
+		for e := he; f.of[e] == -1; e = g.next(e) {
+			f.of[e] = id
+			ring = append(ring, e)
+		}
+		f.edges = append(f.edges, ring)
+	}
+	return f
+}
+
+// cutEdges removes, and returns, the edges that have the same face on both
+// sides.
+func (g *graph) cutEdges(f faces) (cuts []geom.Line) {
+	for i := range g.removed {
+		if g.removed[i] {
+			continue
+		}
+		if f.of[2*i] == f.of[2*i+1] {
+			g.removed[i] = true
+			cuts = append(cuts, g.line(2*i))
+		}
+	}
+	return cuts
+}
+
+// minimalRings splits a ring that touches itself into rings that do not.
+func minimalRings(pts [][2]float64) (rings [][][2]float64) {
+	var stack [][2]float64
+	pos := make(map[[2]float64]int)
+	for _, pt := range pts {
+		if p, ok := pos[pt]; ok {
+			ring := make([][2]float64, len(stack)-p)
+			copy(ring, stack[p:])
+			rings = append(rings, ring)
+			for _, rpt := range stack[p+1:] {
+				delete(pos, rpt)
+			}
+			stack = stack[:p+1]
+			continue
+		}
+		pos[pt] = len(stack)
+		stack = append(stack, pt)
+	}
+	return append(rings, stack)
+}
+
+// signedArea returns the area of the ring, positive if counter-clockwise.
+func signedArea(r [][2]float64) (area float64) {
+	for i := range r {
+		j := (i + 1) % len(r)
+		area += r[i][0]*r[j][1] - r[j][0]*r[i][1]
+	}
+	return area / 2
+}
+
+type shell struct {
+	ring  [][2]float64
+	area  float64
+	index *prepared.Polygon
+	holes [][][2]float64
+}
+
+// contains reports weather the hole is inside of the shell. The hole may
+// touch the shell, so the first point of the hole that is not on the
+// boundary of the shell decides.
+func (s *shell) contains(hole [][2]float64) bool {
+	for i := range hole {
+		if s.index.ContainsPoint(hole[i]) {
+			return true
+		}
+		if !s.index.CoversPoint(hole[i]) {
+			return false
+		}
+	}
+	for i := range hole {
+		j := (i + 1) % len(hole)
+		mid := [2]float64{(hole[i][0] + hole[j][0]) / 2, (hole[i][1] + hole[j][1]) / 2}
+		if s.index.ContainsPoint(mid) {
+			return true
+		}
+		if !s.index.CoversPoint(mid) {
+			return false
+		}
+	}
+	return false
+}
+
+// assemble builds the polygons from the faces. Counter-clockwise rings are
+// exteriors of polygons; clockwise rings are either holes, or the outside
+// boundary of a group of polygons when no polygon contains them.
+func assemble(g *graph, f faces) (polygons []geom.Polygon, invalid []geom.LineString) {
+	var (
+		shells []*shell
+		holes  [][][2]float64
+	)
+	for _, edges := range f.edges {
+		pts := make([][2]float64, len(edges))
+		for i, he := range edges {
+			pts[i] = g.pts[g.origin[he]]
+		}
+		for _, ring := range minimalRings(pts) {
+			area := signedArea(ring)
+			switch {
+			case len(ring) < 3 || area == 0:
+				invalid = append(invalid, append(geom.LineString(ring), ring[0]))
+			case area > 0:
+				index, _ := prepared.NewFromPolygons([][][2]float64{ring})
+				shells = append(shells, &shell{ring: ring, area: area, index: index})
+			default:
+				holes = append(holes, ring)
+			}
+		}
+	}
+
+	// The smallest shell that contains the hole is the one it belongs to.
+	sort.SliceStable(shells, func(i, j int) bool { return shells[i].area < shells[j].area })
+	for _, hole := range holes {
+		ext := geom.NewExtent(hole...)
+		for _, s := range shells {
+			if !s.index.Intersects(ext) {
+				continue
+			}
+			if s.contains(hole) {
+				s.holes = append(s.holes, hole)
+				break
+			}
+		}
+	}
+
+	polygons = make([]geom.Polygon, 0, len(shells))
+	for _, s := range shells {
+		plyg := geom.Polygon{s.ring}
+		plyg = append(plyg, s.holes...)
+		polygons = append(polygons, plyg)
+	}
+	return polygons, invalid
+}
@@ -0,0 +1,193 @@
+package polygonize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+// ring returns the segments of the closed ring through the points.
+func ring(pts ...[2]float64) []geom.Line {
+	lines := make([]geom.Line, len(pts))
+	for i := range pts {
+		lines[i] = geom.Line{pts[i], pts[(i+1)%len(pts)]}
+	}
+	return lines
+}
+
+func join(lines ...[]geom.Line) (ret []geom.Line) {
+	for i := range lines {
+		ret = append(ret, lines[i]...)
+	}
+	return ret
+}
+
+func TestPolygonize(t *testing.T) {
+	type tcase struct {
+		lines    []geom.Line
+		polygons geom.MultiPolygon
+		dangles  []geom.Line
+		cuts     []geom.Line
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			res, err := Polygonize(context.Background(), tc.lines)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.MultiPolygonerEqual(tc.polygons, geom.MultiPolygon(polygons(res.Polygons))) {
+				t.Errorf("polygons, expected %v got %v", tc.polygons, res.Polygons)
+			}
+			if !linesEqual(tc.dangles, res.Dangles) {
+				t.Errorf("dangles, expected %v got %v", tc.dangles, res.Dangles)
+			}
+			if !linesEqual(tc.cuts, res.CutEdges) {
+				t.Errorf("cut edges, expected %v got %v", tc.cuts, res.CutEdges)
+			}
+			if len(res.InvalidRings) != 0 {
+				t.Errorf("invalid rings, expected none got %v", res.InvalidRings)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"square": {
+			lines: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{10, 10}, {10, 0}},
+				{{10, 10}, {0, 10}},
+				{{0, 0}, {0, 10}},
+			},
+			polygons: geom.MultiPolygon{
+				{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			},
+		},
+		"crossing lines": {
+			// a # shape closed by a square: nine cells
+			lines: join(
+				ring([2]float64{0, 0}, [2]float64{3, 0}, [2]float64{3, 3}, [2]float64{0, 3}),
+				[]geom.Line{
+					{{1, 0}, {1, 3}},
+					{{2, 0}, {2, 3}},
+					{{0, 1}, {3, 1}},
+					{{0, 2}, {3, 2}},
+				},
+			),
+			polygons: geom.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+				{{{1, 0}, {2, 0}, {2, 1}, {1, 1}}},
+				{{{2, 0}, {3, 0}, {3, 1}, {2, 1}}},
+				{{{0, 1}, {1, 1}, {1, 2}, {0, 2}}},
+				{{{1, 1}, {2, 1}, {2, 2}, {1, 2}}},
+				{{{2, 1}, {3, 1}, {3, 2}, {2, 2}}},
+				{{{0, 2}, {1, 2}, {1, 3}, {0, 3}}},
+				{{{1, 2}, {2, 2}, {2, 3}, {1, 3}}},
+				{{{2, 2}, {3, 2}, {3, 3}, {2, 3}}},
+			},
+		},
+		"hole": {
+			lines: join(
+				ring([2]float64{0, 0}, [2]float64{10, 0}, [2]float64{10, 10}, [2]float64{0, 10}),
+				ring([2]float64{4, 4}, [2]float64{6, 4}, [2]float64{6, 6}, [2]float64{4, 6}),
+			),
+			polygons: geom.MultiPolygon{
+				{
+					{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+					{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+				},
+				{{{4, 4}, {6, 4}, {6, 6}, {4, 6}}},
+			},
+		},
+		"touching hole": {
+			lines: join(
+				ring([2]float64{0, 0}, [2]float64{10, 0}, [2]float64{10, 10}, [2]float64{0, 10}),
+				ring([2]float64{0, 0}, [2]float64{6, 4}, [2]float64{4, 6}),
+			),
+			polygons: geom.MultiPolygon{
+				{
+					{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+					{{0, 0}, {4, 6}, {6, 4}},
+				},
+				{{{0, 0}, {6, 4}, {4, 6}}},
+			},
+		},
+		"collinear overlap": {
+			lines: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{5, 0}, {15, 0}},
+				{{15, 0}, {15, 10}},
+				{{15, 10}, {0, 0}},
+			},
+			polygons: geom.MultiPolygon{
+				{{{0, 0}, {5, 0}, {10, 0}, {15, 0}, {15, 10}}},
+			},
+		},
+		"dangles and cut edge": {
+			lines: join(
+				ring([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1}, [2]float64{0, 1}),
+				ring([2]float64{3, 0}, [2]float64{4, 0}, [2]float64{4, 1}, [2]float64{3, 1}),
+				[]geom.Line{
+					{{1, 0}, {3, 0}},
+					{{4, 1}, {5, 2}},
+					{{5, 2}, {6, 2}},
+				},
+			),
+			polygons: geom.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+				{{{3, 0}, {4, 0}, {4, 1}, {3, 1}}},
+			},
+			dangles: []geom.Line{
+				{{5, 2}, {6, 2}},
+				{{4, 1}, {5, 2}},
+			},
+			cuts: []geom.Line{
+				{{1, 0}, {3, 0}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func polygons(plygs []geom.Polygon) [][][][2]float64 {
+	ret := make([][][][2]float64, len(plygs))
+	for i := range plygs {
+		ret[i] = plygs[i]
+	}
+	return ret
+}
+
+// linesEqual compares lines ignoring their order and direction.
+func linesEqual(l1, l2 []geom.Line) bool {
+	if len(l1) != len(l2) {
+		return false
+	}
+LOOP:
+	for i := range l1 {
+		for j := range l2 {
+			if (l1[i][0] == l2[j][0] && l1[i][1] == l2[j][1]) ||
+				(l1[i][0] == l2[j][1] && l1[i][1] == l2[j][0]) {
+				continue LOOP
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func TestMinimalRings(t *testing.T) {
+	got := minimalRings([][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 0}, {4, 6}, {6, 4}})
+	expected := [][][2]float64{
+		{{0, 0}, {10, 0}, {10, 10}},
+		{{0, 0}, {4, 6}, {6, 4}},
+	}
+	if !cmp.MultiLineEqual(expected, got) {
+		t.Errorf("rings, expected %v got %v", expected, got)
+	}
+}
@@ -0,0 +1,277 @@
+package planar
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrInvalidPrecision is returned when the precision is not larger then zero.
+type ErrInvalidPrecision float64
+
+func (e ErrInvalidPrecision) Error() string {
+	return fmt.Sprintf("planar: precision must be larger then zero, got %v", float64(e))
+}
+
+// labelCell is a square cell of the polylabel search.
+type labelCell struct {
+	center [2]float64
+	// half the cell size
+	h float64
+	// distance from the center to the polygon outline; negative if outside
+	d float64
+	// the largest distance possible for a point in the cell
+	max float64
+}
+
+func newLabelCell(plyg [][][2]float64, x, y, h float64) *labelCell {
+	c := &labelCell{center: [2]float64{x, y}, h: h}
+	c.d = pointToPolygonDistance(plyg, c.center)
+	c.max = c.d + h*math.Sqrt2
+	return c
+}
+
+// cellQueue is a max-heap of cells by their potential distance.
+type cellQueue []*labelCell
+
+func (q cellQueue) Len() int            { return len(q) }
+func (q cellQueue) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q cellQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *cellQueue) Push(x interface{}) { *q = append(*q, x.(*labelCell)) }
+func (q *cellQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// pointToPolygonDistance returns the distance from the point to the closest
+// ring of the polygon; negative if the point is outside.
+func pointToPolygonDistance(plyg [][][2]float64, pt [2]float64) float64 {
+	d := math.Inf(1)
+	for _, ring := range plyg {
+		lp := len(ring) - 1
+		for i := range ring {
+			d = math.Min(d, DistanceToLineSegment(geom.Point(pt), geom.Point(ring[lp]), geom.Point(ring[i])))
+			lp = i
+		}
+	}
+	if !polygonCovers(plyg, pt) {
+		return -d
+	}
+	return d
+}
+
+// polygonArea returns the area of the polygon, the area of the exterior
+// ring less that of the holes.
+func polygonArea(plyg [][][2]float64) float64 {
+	area := 0.0
+	for i, ring := range plyg {
+		a := 0.0
+		lp := len(ring) - 1
+		for j := range ring {
+			a += ring[lp][0]*ring[j][1] - ring[j][0]*ring[lp][1]
+			lp = j
+		}
+		if i == 0 {
+			area += math.Abs(a / 2)
+		} else {
+			area -= math.Abs(a / 2)
+		}
+	}
+	return area
+}
+
+// largestPolygon returns the polygon, or the polygon of the multipolygon
+// with the largest area.
+func largestPolygon(g geom.Geometry) ([][][2]float64, error) {
+	switch gg := g.(type) {
+	case geom.Polygoner:
+		return gg.LinearRings(), nil
+	case geom.MultiPolygoner:
+		var (
+			largest [][][2]float64
+			area    = -1.0
+		)
+		for _, plyg := range gg.Polygons() {
+			if a := polygonArea(plyg); a > area {
+				largest, area = plyg, a
+			}
+		}
+		return largest, nil
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+// maxInitialCells is the largest number of cells PoleOfInaccessibility starts
+// with along the longer side of the polygon.
+const maxInitialCells = 1000
+
+// PoleOfInaccessibility returns the point inside of the polygon that is the
+// farthest from its outline, within precision, and the distance from the
+// point to the outline. Holes are taken into account. For a MultiPolygon the
+// point is found for the polygon with the largest area.
+//
+// The search divides the polygon in square cells, discarding the cells
+// that can not contain a better point than the best one found so far.
+// ref: https://github.com/mapbox/polylabel
+func PoleOfInaccessibility(g geom.Geometry, precision float64) (geom.Point, float64, error) {
+	if !(precision > 0) {
+		return geom.Point{}, 0, ErrInvalidPrecision(precision)
+	}
+	plyg, err := largestPolygon(g)
+	if err != nil {
+		return geom.Point{}, 0, err
+	}
+	if len(plyg) == 0 || len(plyg[0]) == 0 {
+		return geom.Point{}, 0, ErrEmptyGeometry
+	}
+
+	ext := geom.NewExtent(plyg[0]...)
+	w, ht := ext.XSpan(), ext.YSpan()
+	if math.Min(w, ht) == 0 {
+		return geom.Point(ext.Min()), 0, nil
+	}
+	// the initial cells are as large as the shorter side, but not smaller
+	// than the precision, and there are at most maxInitialCells of them
+	// along the longer side; they are split as needed anyway.
+	size := math.Max(precision, math.Min(w, ht))
+	size = math.Max(size, math.Max(w, ht)/maxInitialCells)
+	h := size / 2
+
+	// cover the polygon with the initial cells; counting the cells keeps
+	// the steps from being lost to rounding at large coordinates.
+	q := new(cellQueue)
+	nx, ny := int(math.Ceil(w/size)), int(math.Ceil(ht/size))
+	for i := 0; i < nx; i++ {
+		for j := 0; j < ny; j++ {
+			x, y := ext.MinX()+float64(i)*size, ext.MinY()+float64(j)*size
+			heap.Push(q, newLabelCell(plyg, x+h, y+h, h))
+		}
+	}
+
+	// the centroid is often a good first guess
+	centroid := polygonCentroid(plyg[0])
+	best := newLabelCell(plyg, centroid[0], centroid[1], 0)
+	// as is the center of the extent, for thin polygons
+	if c := newLabelCell(plyg, (ext.MinX()+ext.MaxX())/2, (ext.MinY()+ext.MaxY())/2, 0); c.d > best.d {
+		best = c
+	}
+
+	for q.Len() > 0 {
+		c := heap.Pop(q).(*labelCell)
+		if c.d > best.d {
+			best = c
+		}
+		// there can't be a better point in this cell
+		if c.max-best.d <= precision {
+			continue
+		}
+		h := c.h / 2
+		heap.Push(q, newLabelCell(plyg, c.center[0]-h, c.center[1]-h, h))
+		heap.Push(q, newLabelCell(plyg, c.center[0]+h, c.center[1]-h, h))
+		heap.Push(q, newLabelCell(plyg, c.center[0]-h, c.center[1]+h, h))
+		heap.Push(q, newLabelCell(plyg, c.center[0]+h, c.center[1]+h, h))
+	}
+	return geom.Point(best.center), best.d, nil
+}
+
+// polygonCentroid returns the centroid of the area of the ring, or its first
+// point if the ring has no area.
+func polygonCentroid(ring [][2]float64) [2]float64 {
+	var x, y, area float64
+	lp := len(ring) - 1
+	for i := range ring {
+		a, b := ring[lp], ring[i]
+		f := a[0]*b[1] - b[0]*a[1]
+		x += (a[0] + b[0]) * f
+		y += (a[1] + b[1]) * f
+		area += f * 3
+		lp = i
+	}
+	if area == 0 {
+		return ring[0]
+	}
+	return [2]float64{x / area, y / area}
+}
+
+// scanlinePoint returns the middle of the widest crossing of the polygon
+// by a horizontal line that is strictly inside of it. The lines are
+// half way between the y of the points, the largest gaps first.
+func scanlinePoint(plyg [][][2]float64) ([2]float64, bool) {
+	var ys []float64
+	for _, ring := range plyg {
+		for _, pt := range ring {
+			ys = append(ys, pt[1])
+		}
+	}
+	sort.Float64s(ys)
+	type band struct{ y, gap float64 }
+	var bands []band
+	for i := 1; i < len(ys); i++ {
+		if ys[i] > ys[i-1] {
+			bands = append(bands, band{y: ys[i-1] + (ys[i]-ys[i-1])/2, gap: ys[i] - ys[i-1]})
+		}
+	}
+	sort.Slice(bands, func(i, j int) bool { return bands[i].gap > bands[j].gap })
+
+	for _, b := range bands {
+		var xs []float64
+		for _, ring := range plyg {
+			lp := len(ring) - 1
+			for i := range ring {
+				p1, p2 := ring[lp], ring[i]
+				lp = i
+				if (p1[1] < b.y) != (p2[1] < b.y) {
+					xs = append(xs, p1[0]+(b.y-p1[1])*(p2[0]-p1[0])/(p2[1]-p1[1]))
+				}
+			}
+		}
+		sort.Float64s(xs)
+		var best [2]float64
+		width := 0.0
+		// the crossings pair up into the parts inside
+		for i := 1; i < len(xs); i += 2 {
+			if w := xs[i] - xs[i-1]; w > width {
+				best, width = [2]float64{xs[i-1] + w/2, b.y}, w
+			}
+		}
+		if width > 0 && pointToPolygonDistance(plyg, best) > 0 {
+			return best, true
+		}
+	}
+	return [2]float64{}, false
+}
+
+// InteriorPoint returns a point inside of the (Multi)Polygon, away from its
+// outline when possible. It is the pole of inaccessibility found with a
+// precision of a hundredth of the polygon's size. That search can miss a
+// polygon thinner than its precision, so for those the middle of the widest
+// crossing of a horizontal line is used when it is farther from the
+// outline. Only a polygon without an area gets a point on its outline.
+func InteriorPoint(g geom.Geometry) (geom.Point, error) {
+	plyg, err := largestPolygon(g)
+	if err != nil {
+		return geom.Point{}, err
+	}
+	if len(plyg) == 0 || len(plyg[0]) == 0 {
+		return geom.Point{}, ErrEmptyGeometry
+	}
+	ext := geom.NewExtent(plyg[0]...)
+	precision := math.Max(ext.XSpan(), ext.YSpan()) / 100
+	if precision == 0 {
+		return geom.Point(plyg[0][0]), nil
+	}
+	pt, d, err := PoleOfInaccessibility(geom.Polygon(plyg), precision)
+	if err != nil || d >= precision {
+		return pt, err
+	}
+	if spt, ok := scanlinePoint(plyg); ok && pointToPolygonDistance(plyg, spt) > d {
+		return geom.Point(spt), nil
+	}
+	return pt, nil
+}
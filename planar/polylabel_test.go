@@ -0,0 +1,156 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPoleOfInaccessibility(t *testing.T) {
+	type tcase struct {
+		geom      geom.Geometry
+		precision float64
+		// expected is the pole, if it's unique
+		expected *geom.Point
+		distance float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			pt, d, err := PoleOfInaccessibility(tc.geom, tc.precision)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			plyg, _ := largestPolygon(tc.geom)
+			if !polygonCovers(plyg, pt) {
+				t.Errorf("point %v, expected to be inside of the polygon", pt)
+			}
+			if tc.expected != nil && PointDistance(tc.expected, pt) > tc.precision {
+				t.Errorf("point, expected %v got %v", tc.expected, pt)
+			}
+			if d > tc.distance || tc.distance-d > tc.precision {
+				t.Errorf("distance, expected %v got %v", tc.distance, d)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"square": {
+			geom:      geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			precision: 0.01,
+			expected:  &geom.Point{5, 5},
+			distance:  5,
+		},
+		"concave": {
+			// a U shape; the centroid is outside, in the notch. The pole is
+			// in one of the corners of the base, as far from the sides as
+			// from the inner corner of the notch.
+			geom: geom.Polygon{{
+				{0, 0}, {30, 0}, {30, 30}, {20, 30}, {20, 10}, {10, 10}, {10, 30}, {0, 30},
+			}},
+			precision: 0.01,
+			distance:  10 * (2 - math.Sqrt2),
+		},
+		"hole": {
+			// a square with a large hole on the left side.
+			geom: geom.Polygon{
+				{{0, 0}, {40, 0}, {40, 20}, {0, 20}},
+				{{2, 2}, {2, 18}, {24, 18}, {24, 2}},
+			},
+			precision: 0.01,
+			distance:  8,
+		},
+		"largest polygon": {
+			geom: geom.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}},
+				{{{10, 10}, {20, 10}, {20, 20}, {10, 20}}},
+			},
+			precision: 0.01,
+			expected:  &geom.Point{15, 15},
+			distance:  5,
+		},
+		"thin": {
+			// much longer than it is wide, and thinner than the precision
+			geom:      geom.Polygon{{{0, 0}, {1000, 0}, {1000, 1e-7}, {0, 1e-7}}},
+			precision: 10,
+			distance:  5e-8,
+		},
+		"large coordinates": {
+			// the cells are smaller than the spacing of the floats
+			geom:      geom.Polygon{{{1e16, 0}, {1e16 + 1000, 0}, {1e16 + 1000, 0.5}, {1e16, 0.5}}},
+			precision: 0.1,
+			distance:  0.25,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, _, err := PoleOfInaccessibility(geom.Point{0, 0}, 1); err == nil {
+		t.Errorf("error, expected ErrUnknownGeometry got nil")
+	}
+	if _, _, err := PoleOfInaccessibility(geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}, 0); err != ErrInvalidPrecision(0) {
+		t.Errorf("error, expected %v got %v", ErrInvalidPrecision(0), err)
+	}
+}
+
+func TestInteriorPoint(t *testing.T) {
+	plyg := geom.Polygon{{
+		{0, 0}, {30, 0}, {30, 30}, {20, 30}, {20, 10}, {10, 10}, {10, 30}, {0, 30},
+	}}
+	pt, err := InteriorPoint(plyg)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !polygonCovers(plyg, pt) {
+		t.Errorf("point %v, expected to be inside of the polygon", pt)
+	}
+	centroid := polygonCentroid(plyg[0])
+	if polygonCovers(plyg, centroid) {
+		t.Errorf("centroid %v, expected to be outside of the polygon", centroid)
+	}
+	if pt[1] > 10 {
+		t.Errorf("point %v, expected it in the base of the U", pt)
+	}
+
+	thin := geom.Polygon{{{0, 0}, {1000, 0}, {1000, 1e-7}, {0, 1e-7}}}
+	if pt, err = InteriorPoint(thin); err != nil {
+		t.Fatalf("thin error, expected nil got %v", err)
+	}
+	if d := pointToPolygonDistance(thin, pt); !(d > 0) {
+		t.Errorf("thin point %v, expected inside got distance %v", pt, d)
+	}
+}
+
+func TestInteriorPointSliver(t *testing.T) {
+	type tcase struct {
+		width float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		// a bent sliver, with its centroid and the center of its extent
+		// outside of it
+		w := tc.width
+		plyg := geom.Polygon{{{0, 0}, {41, 53}, {100, 3}, {100, 3 + w}, {41, 53 + w}, {0, w}}}
+		pt, err := InteriorPoint(plyg)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		// the sliver is at least a fifth of w across
+		if d := pointToPolygonDistance(plyg, pt); d < w/10 {
+			t.Errorf("point %v, expected inside away from the outline got distance %v", pt, d)
+		}
+	}
+
+	tests := map[string]tcase{
+		"missed":     {width: 1e-3},
+		"on outline": {width: 1e-5},
+		"narrow":     {width: 0.05},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
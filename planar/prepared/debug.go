@@ -0,0 +1,3 @@
+package prepared
+
+const debug = false
@@ -0,0 +1,317 @@
+/*
+Package prepared provides polygons that are indexed once so that repeated
+spatial predicates against them are fast.
+
+The edges of the polygon are bucketed into horizontal bands. A point query
+only has to look at the edges in the band the point falls in, instead of
+every edge of the polygon, which makes labelling many points against a large
+polygon cheap.
+*/
+package prepared
+
+import (
+	"log"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+// edgesPerBand is the average number of edges we aim to have in a band.
+const edgesPerBand = 4
+
+// maxBands limits the memory used by the index for very large polygons.
+const maxBands = 1 << 16
+
+// Polygon is a prepared (Multi)Polygon. It is immutable once created and
+// safe for concurrent use.
+//
+// The rings are treated with the even-odd rule, so the polygon is expected
+// to be valid: holes inside their exterior ring, and polygons not
+// overlapping each other.
+type Polygon struct {
+	edges []geom.Line
+	// first vertex of each ring, used to check if the polygon is within an extent.
+	starts [][2]float64
+	bbox   geom.Extent
+
+	// bands hold the indices of the edges that overlap each horizontal band.
+	bands     [][]int
+	bandMinY  float64
+	bandWidth float64
+}
+
+// New returns a prepared polygon for a geom.Polygoner or geom.MultiPolygoner.
+func New(geo geom.Geometry) (*Polygon, error) {
+	var plygs [][][][2]float64
+	switch g := geo.(type) {
+	case geom.Polygoner:
+		plygs = [][][][2]float64{g.LinearRings()}
+	case geom.MultiPolygoner:
+		plygs = g.Polygons()
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: geo}
+	}
+	return NewFromPolygons(plygs...)
+}
+
+// NewFromPolygons returns a prepared polygon from the rings of the given
+// polygons. Rings do not need to be closed.
+func NewFromPolygons(plygs ...[][][2]float64) (*Polygon, error) {
+	p := new(Polygon)
+	for i := range plygs {
+		for _, ring := range plygs[i] {
+			if len(ring) < 3 {
+				return nil, geom.ErrInvalidLinearRing
+			}
+			if len(p.starts) == 0 {
+				p.bbox = *geom.NewExtent(ring...)
+			}
+			p.starts = append(p.starts, ring[0])
+			lp := len(ring) - 1
+			for j := range ring {
+				if ring[lp] != ring[j] {
+					p.edges = append(p.edges, geom.Line{ring[lp], ring[j]})
+				}
+				lp = j
+			}
+			p.bbox.AddPoints(ring...)
+		}
+	}
+	p.buildBands()
+	if debug {
+		log.Printf("prepared polygon: %v edges in %v bands", len(p.edges), len(p.bands))
+	}
+	return p, nil
+}
+
+func (p *Polygon) buildBands() {
+	if len(p.edges) == 0 {
+		return
+	}
+	n := len(p.edges) / edgesPerBand
+	if n < 1 {
+		n = 1
+	}
+	if n > maxBands {
+		n = maxBands
+	}
+	p.bandMinY = p.bbox.MinY()
+	p.bandWidth = p.bbox.YSpan() / float64(n)
+	if p.bandWidth == 0 {
+		n = 1
+	}
+	p.bands = make([][]int, n)
+	for i, e := range p.edges {
+		lo, hi := p.bandRange(e[0][1], e[1][1])
+		for b := lo; b <= hi; b++ {
+			p.bands[b] = append(p.bands[b], i)
+		}
+	}
+}
+
+// band returns the band y falls into; values outside of the extent are
+// clamped to the first or last band.
+func (p *Polygon) band(y float64) int {
+	if p.bandWidth == 0 {
+		return 0
+	}
+	b := int(math.Floor((y - p.bandMinY) / p.bandWidth))
+	switch {
+	case b < 0:
+		return 0
+	case b >= len(p.bands):
+		return len(p.bands) - 1
+	}
+	return b
+}
+
+// bandRange returns the first and last band covered by the y values.
+func (p *Polygon) bandRange(y1, y2 float64) (lo, hi int) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	return p.band(y1), p.band(y2)
+}
+
+// locate returns the location of the point: 1 for the interior, 0 for the
+// boundary and -1 for the exterior.
+func (p *Polygon) locate(pt [2]float64) int {
+	if p == nil || len(p.bands) == 0 || !p.bbox.ContainsPoint(pt) {
+		return -1
+	}
+	inside := false
+	for _, idx := range p.bands[p.band(pt[1])] {
+		a, b := p.edges[idx][0], p.edges[idx][1]
+		o := robust.Orient2D(a, b, pt)
+		if o == 0 && p.edges[idx].ContainsPoint(pt) {
+			return 0
+		}
+		// count the edges crossing the ray going to the left of pt.
+		switch {
+		case a[1] <= pt[1] && b[1] > pt[1]:
+			if o < 0 {
+				inside = !inside
+			}
+		case b[1] <= pt[1] && a[1] > pt[1]:
+			if o > 0 {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return 1
+	}
+	return -1
+}
+
+// ContainsPoint returns weather the point is in the interior of the polygon.
+// Points on the boundary are not contained.
+func (p *Polygon) ContainsPoint(pt [2]float64) bool { return p.locate(pt) == 1 }
+
+// CoversPoint returns weather the point is in the interior or on the boundary
+// of the polygon.
+func (p *Polygon) CoversPoint(pt [2]float64) bool { return p.locate(pt) >= 0 }
+
+// edgesIn calls fn for each edge that may overlap the y range, stopping
+// when fn returns false. An edge may be visited more than once.
+func (p *Polygon) edgesIn(miny, maxy float64, fn func(geom.Line) bool) {
+	lo, hi := p.bandRange(miny, maxy)
+	for b := lo; b <= hi; b++ {
+		for _, idx := range p.bands[b] {
+			if !fn(p.edges[idx]) {
+				return
+			}
+		}
+	}
+}
+
+// Intersects returns weather the polygon and the extent have at least one
+// point in common, including their boundaries.
+func (p *Polygon) Intersects(e *geom.Extent) bool {
+	if p == nil || e == nil || len(p.edges) == 0 {
+		return false
+	}
+	if !overlaps(&p.bbox, e) {
+		return false
+	}
+	hit := false
+	p.edgesIn(e.MinY(), e.MaxY(), func(l geom.Line) bool {
+		_, _, hit = clipLine(l, e)
+		return !hit
+	})
+	if hit {
+		return true
+	}
+	// No boundaries cross, so either the extent is inside of the polygon,
+	// the polygon is inside of the extent, or they are disjoint.
+	if p.CoversPoint(e.Min()) {
+		return true
+	}
+	for _, pt := range p.starts {
+		if e.ContainsPoint(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers returns weather every point of the extent is in the interior or on
+// the boundary of the polygon.
+func (p *Polygon) Covers(e *geom.Extent) bool {
+	if p == nil || e == nil || len(p.edges) == 0 {
+		return false
+	}
+	if !p.bbox.Contains(e) {
+		return false
+	}
+	// If an edge goes through the interior of the extent there are points of
+	// the extent on both sides of it, and one of the sides is outside.
+	crosses := false
+	p.edgesIn(e.MinY(), e.MaxY(), func(l geom.Line) bool {
+		a, b, ok := clipLine(l, e)
+		if !ok {
+			return true
+		}
+		mid := [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+		crosses = mid[0] > e.MinX() && mid[0] < e.MaxX() && mid[1] > e.MinY() && mid[1] < e.MaxY()
+		return !crosses
+	})
+	if crosses {
+		return false
+	}
+	for _, v := range e.Vertices() {
+		if !p.CoversPoint(v) {
+			return false
+		}
+	}
+	// With no edge through its interior, the interior of the extent is either
+	// all inside or all outside; the corners can be on the boundary in both
+	// cases (e.g. an extent matching a hole) so check the center.
+	return p.CoversPoint([2]float64{(e.MinX() + e.MaxX()) / 2, (e.MinY() + e.MaxY()) / 2})
+}
+
+// LabelFor returns the label for the given point, points on the boundary are
+// labeled inside.
+func (p *Polygon) LabelFor(pt [2]float64) planar.Label {
+	if p.CoversPoint(pt) {
+		return planar.Inside
+	}
+	return planar.Outside
+}
+
+// Extent returns the extent of the polygon.
+func (p *Polygon) Extent() [4]float64 { return p.bbox.Extent() }
+
+// Area returns the area of the extent of the polygon, like the other
+// hitmaps do.
+func (p *Polygon) Area() float64 { return p.bbox.Area() }
+
+// overlaps returns weather the two extents have any point in common;
+// unlike geom.Extent.Intersect, touching extents overlap.
+func overlaps(e1, e2 *geom.Extent) bool {
+	return e1.MinX() <= e2.MaxX() && e2.MinX() <= e1.MaxX() &&
+		e1.MinY() <= e2.MaxY() && e2.MinY() <= e1.MaxY()
+}
+
+// clipLine clips the line to the extent, returning the part of the line
+// inside the extent, and false if there is none. (Liang–Barsky)
+func clipLine(l geom.Line, e *geom.Extent) (a, b [2]float64, ok bool) {
+	dx, dy := l[1][0]-l[0][0], l[1][1]-l[0][1]
+	p := [4]float64{-dx, dx, -dy, dy}
+	q := [4]float64{
+		l[0][0] - e.MinX(),
+		e.MaxX() - l[0][0],
+		l[0][1] - e.MinY(),
+		e.MaxY() - l[0][1],
+	}
+	t0, t1 := 0.0, 1.0
+	for i := range p {
+		if p[i] == 0 {
+			if q[i] < 0 {
+				return a, b, false
+			}
+			continue
+		}
+		r := q[i] / p[i]
+		if p[i] < 0 {
+			if r > t1 {
+				return a, b, false
+			}
+			if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return a, b, false
+			}
+			if r < t1 {
+				t1 = r
+			}
+		}
+	}
+	a = [2]float64{l[0][0] + t0*dx, l[0][1] + t0*dy}
+	b = [2]float64{l[0][0] + t1*dx, l[0][1] + t1*dy}
+	return a, b, true
+}
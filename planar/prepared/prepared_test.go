@@ -0,0 +1,190 @@
+package prepared
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/makevalid/hitmap"
+)
+
+// a square with a square hole
+var squareWithHole = geom.Polygon{
+	{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+	{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+}
+
+func TestContainsPoint(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		pt       [2]float64
+		contains bool
+		covers   bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			p, err := New(tc.geom)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if got := p.ContainsPoint(tc.pt); got != tc.contains {
+				t.Errorf("contains, expected %v got %v", tc.contains, got)
+			}
+			if got := p.CoversPoint(tc.pt); got != tc.covers {
+				t.Errorf("covers, expected %v got %v", tc.covers, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside":                 {geom: squareWithHole, pt: [2]float64{2, 2}, contains: true, covers: true},
+		"in hole":                {geom: squareWithHole, pt: [2]float64{5, 5}},
+		"outside":                {geom: squareWithHole, pt: [2]float64{11, 5}},
+		"on exterior vertex":     {geom: squareWithHole, pt: [2]float64{10, 10}, covers: true},
+		"on exterior edge":       {geom: squareWithHole, pt: [2]float64{0, 5}, covers: true},
+		"on hole edge":           {geom: squareWithHole, pt: [2]float64{5, 6}, covers: true},
+		"level with hole vertex": {geom: squareWithHole, pt: [2]float64{8, 4}, contains: true, covers: true},
+		"second polygon": {
+			geom: geom.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}}},
+				{{{5, 5}, {7, 5}, {7, 7}, {5, 7}}},
+			},
+			pt:       [2]float64{6, 6},
+			contains: true,
+			covers:   true,
+		},
+		"between polygons": {
+			geom: geom.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}}},
+				{{{5, 5}, {7, 5}, {7, 7}, {5, 7}}},
+			},
+			pt: [2]float64{3, 3},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestExtentPredicates(t *testing.T) {
+	type tcase struct {
+		extent     *geom.Extent
+		intersects bool
+		covers     bool
+	}
+
+	p, err := New(squareWithHole)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := p.Intersects(tc.extent); got != tc.intersects {
+				t.Errorf("intersects, expected %v got %v", tc.intersects, got)
+			}
+			if got := p.Covers(tc.extent); got != tc.covers {
+				t.Errorf("covers, expected %v got %v", tc.covers, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside": {
+			extent:     geom.NewExtent([2]float64{1, 1}, [2]float64{3, 3}),
+			intersects: true,
+			covers:     true,
+		},
+		"touching exterior from inside": {
+			extent:     geom.NewExtent([2]float64{0, 0}, [2]float64{3, 3}),
+			intersects: true,
+			covers:     true,
+		},
+		"crossing the hole": {
+			extent:     geom.NewExtent([2]float64{3, 3}, [2]float64{5, 5}),
+			intersects: true,
+		},
+		"matching the hole": {
+			extent:     geom.NewExtent([2]float64{4, 4}, [2]float64{6, 6}),
+			intersects: true,
+		},
+		"inside the hole": {
+			extent: geom.NewExtent([2]float64{4.5, 4.5}, [2]float64{5.5, 5.5}),
+		},
+		"containing the polygon": {
+			extent:     geom.NewExtent([2]float64{-1, -1}, [2]float64{11, 11}),
+			intersects: true,
+		},
+		"touching from outside": {
+			extent:     geom.NewExtent([2]float64{10, 2}, [2]float64{12, 3}),
+			intersects: true,
+		},
+		"disjoint": {
+			extent: geom.NewExtent([2]float64{11, 11}, [2]float64{12, 12}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// circle returns a ring of n points around the center.
+func circle(center [2]float64, radius float64, n int) [][2]float64 {
+	ring := make([][2]float64, n)
+	for i := range ring {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		ring[i] = [2]float64{center[0] + radius*math.Cos(a), center[1] + radius*math.Sin(a)}
+	}
+	return ring
+}
+
+func TestLabelForMatchesHitmap(t *testing.T) {
+	plyg := [][][2]float64{
+		circle([2]float64{0, 0}, 100, 1000),
+		circle([2]float64{10, 10}, 20, 100),
+	}
+	p, err := NewFromPolygons(plyg)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	var _ planar.HitMapper = p
+
+	hm := hitmap.MustNewFromPolygons(nil, plyg)
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		pt := [2]float64{rnd.Float64()*240 - 120, rnd.Float64()*240 - 120}
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if got, expected := p.LabelFor(pt), hm.LabelFor(pt); got != expected {
+				t.Errorf("label for %v, expected %v got %v", pt, expected, got)
+			}
+		})
+	}
+}
+
+func BenchmarkLabelFor(b *testing.B) {
+	plyg := [][][2]float64{circle([2]float64{0, 0}, 100, 10000)}
+	pts := make([][2]float64, 1024)
+	rnd := rand.New(rand.NewSource(1))
+	for i := range pts {
+		pts[i] = [2]float64{rnd.Float64()*240 - 120, rnd.Float64()*240 - 120}
+	}
+
+	b.Run("prepared", func(b *testing.B) {
+		p, _ := NewFromPolygons(plyg)
+		for i := 0; i < b.N; i++ {
+			p.LabelFor(pts[i%len(pts)])
+		}
+	})
+	b.Run("hitmap", func(b *testing.B) {
+		hm := hitmap.MustNewFromPolygons(nil, plyg)
+		for i := 0; i < b.N; i++ {
+			hm.LabelFor(pts[i%len(pts)])
+		}
+	})
+}
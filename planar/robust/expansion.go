@@ -0,0 +1,121 @@
+package robust
+
+import "math"
+
+// expansion is a sum of float64 components, ordered by increasing
+// magnitude, where no two components overlap. The value of the expansion
+// is the exact sum of it's components.
+type expansion []float64
+
+// twoSum returns x = fl(a+b) and the roundoff error y such that a+b = x+y exactly.
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	br := b - bv
+	ar := a - av
+	return x, ar + br
+}
+
+// fastTwoSum is like twoSum, but requires |a| >= |b|.
+func fastTwoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	return x, b - bv
+}
+
+// twoProduct returns x = fl(a*b) and the roundoff error y such that a*b = x+y exactly.
+func twoProduct(a, b float64) (x, y float64) {
+	x = a * b
+	return x, math.FMA(a, b, -x)
+}
+
+// diff returns the exact value of a-b.
+func diff(a, b float64) expansion {
+	x, y := twoSum(a, -b)
+	if y == 0 {
+		return expansion{x}
+	}
+	return expansion{y, x}
+}
+
+// grow adds b to e. (Shewchuk's grow_expansion_zeroelim)
+func (e expansion) grow(b float64) expansion {
+	h := make(expansion, 0, len(e)+1)
+	q := b
+	for _, enow := range e {
+		var hh float64
+		q, hh = twoSum(q, enow)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+// add returns e+f. (Shewchuk's expansion_sum_zeroelim)
+func (e expansion) add(f expansion) expansion {
+	for _, v := range f {
+		e = e.grow(v)
+	}
+	return e
+}
+
+// neg returns -e.
+func (e expansion) neg() expansion {
+	n := make(expansion, len(e))
+	for i := range e {
+		n[i] = -e[i]
+	}
+	return n
+}
+
+// sub returns e-f.
+func (e expansion) sub(f expansion) expansion { return e.add(f.neg()) }
+
+// scale returns e*b. (Shewchuk's scale_expansion_zeroelim)
+func (e expansion) scale(b float64) expansion {
+	if len(e) == 0 {
+		return expansion{0}
+	}
+	h := make(expansion, 0, 2*len(e))
+	q, hh := twoProduct(e[0], b)
+	if hh != 0 {
+		h = append(h, hh)
+	}
+	for _, enow := range e[1:] {
+		p1, p0 := twoProduct(enow, b)
+		sum, hh := twoSum(q, p0)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+		q, hh = fastTwoSum(p1, sum)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+// mul returns e*f.
+func (e expansion) mul(f expansion) expansion {
+	ret := expansion{0}
+	for _, v := range f {
+		ret = ret.add(e.scale(v))
+	}
+	return ret
+}
+
+// estimate returns a float64 approximation of e, with the same sign as e.
+func (e expansion) estimate() (sum float64) {
+	for _, v := range e {
+		sum += v
+	}
+	return sum
+}
@@ -0,0 +1,167 @@
+/*
+Package robust provides adaptive precision geometric predicates.
+
+The predicates first evaluate the determinant with plain float64
+arithmetic and an error bound. Only when the error bound can not
+guarantee the sign of the result is the determinant recomputed with exact
+expansion arithmetic. This makes the common case as fast as the non robust
+version, while the sign of the result is always correct.
+
+Ref: Shewchuk, J. R., "Adaptive Precision Floating-Point Arithmetic and
+Fast Robust Geometric Predicates", https://www.cs.cmu.edu/~quake/robust.html
+*/
+package robust
+
+const (
+	// epsilon is half of the machine epsilon of float64 (2^-53)
+	epsilon = 1.0 / (1 << 53)
+
+	ccwErrBoundA = (3.0 + 16.0*epsilon) * epsilon
+	iccErrBoundA = (10.0 + 96.0*epsilon) * epsilon
+)
+
+// Orient2D returns a positive value if the points a, b, c are in
+// counter-clockwise order (when the y axis points up), a negative value if
+// they are in clockwise order and zero if they are collinear. The value is
+// an approximation of twice the signed area of the triangle, but its
+// sign is always exact.
+func Orient2D(a, b, c [2]float64) float64 {
+	detleft := (a[0] - c[0]) * (b[1] - c[1])
+	detright := (a[1] - c[1]) * (b[0] - c[0])
+	det := detleft - detright
+
+	var detsum float64
+	switch {
+	case detleft > 0:
+		if detright <= 0 {
+			return det
+		}
+		detsum = detleft + detright
+	case detleft < 0:
+		if detright >= 0 {
+			return det
+		}
+		detsum = -detleft - detright
+	default:
+		return det
+	}
+
+	errbound := ccwErrBoundA * detsum
+	if det >= errbound || -det >= errbound {
+		return det
+	}
+	return orient2DExact(a, b, c)
+}
+
+func orient2DExact(a, b, c [2]float64) float64 {
+	term := func(x, y float64) expansion {
+		hi, lo := twoProduct(x, y)
+		if lo == 0 {
+			return expansion{hi}
+		}
+		return expansion{lo, hi}
+	}
+
+	det := term(a[0], b[1]).
+		sub(term(a[0], c[1])).
+		sub(term(a[1], b[0])).
+		add(term(a[1], c[0])).
+		add(term(b[0], c[1])).
+		sub(term(b[1], c[0]))
+	return det.estimate()
+}
+
+// Cross returns the cross product of the vectors from a to b and from c to
+// d: positive if the second turns counter-clockwise from the first,
+// negative if it turns clockwise and zero if they are parallel. As with
+// Orient2D only the sign of the result is exact.
+func Cross(a, b, c, d [2]float64) float64 {
+	detleft := (b[0] - a[0]) * (d[1] - c[1])
+	detright := (b[1] - a[1]) * (d[0] - c[0])
+	det := detleft - detright
+
+	// the same form as the orientation determinant, so the same bound holds
+	var detsum float64
+	switch {
+	case detleft > 0:
+		if detright <= 0 {
+			return det
+		}
+		detsum = detleft + detright
+	case detleft < 0:
+		if detright >= 0 {
+			return det
+		}
+		detsum = -detleft - detright
+	default:
+		return det
+	}
+
+	errbound := ccwErrBoundA * detsum
+	if det >= errbound || -det >= errbound {
+		return det
+	}
+	return diff(b[0], a[0]).mul(diff(d[1], c[1])).
+		sub(diff(b[1], a[1]).mul(diff(d[0], c[0]))).
+		estimate()
+}
+
+// InCircle returns a positive value if the point d is inside the circle
+// through a, b and c, a negative value if it is outside and zero if the
+// four points are cocircular. The points a, b, c must be in
+// counter-clockwise order, otherwise the sign is reversed. As with
+// Orient2D only the sign of the result is exact.
+func InCircle(a, b, c, d [2]float64) float64 {
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	alift := adx*adx + ady*ady
+
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	blift := bdx*bdx + bdy*bdy
+
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdxcdy-cdxbdy) +
+		blift*(cdxady-adxcdy) +
+		clift*(adxbdy-bdxady)
+
+	permanent := (abs(bdxcdy)+abs(cdxbdy))*alift +
+		(abs(cdxady)+abs(adxcdy))*blift +
+		(abs(adxbdy)+abs(bdxady))*clift
+
+	errbound := iccErrBoundA * permanent
+	if det > errbound || -det > errbound {
+		return det
+	}
+	return inCircleExact(a, b, c, d)
+}
+
+func inCircleExact(a, b, c, d [2]float64) float64 {
+	adx, ady := diff(a[0], d[0]), diff(a[1], d[1])
+	bdx, bdy := diff(b[0], d[0]), diff(b[1], d[1])
+	cdx, cdy := diff(c[0], d[0]), diff(c[1], d[1])
+
+	alift := adx.mul(adx).add(ady.mul(ady))
+	blift := bdx.mul(bdx).add(bdy.mul(bdy))
+	clift := cdx.mul(cdx).add(cdy.mul(cdy))
+
+	bcdet := bdx.mul(cdy).sub(cdx.mul(bdy))
+	cadet := cdx.mul(ady).sub(adx.mul(cdy))
+	abdet := adx.mul(bdy).sub(bdx.mul(ady))
+
+	det := alift.mul(bcdet).
+		add(blift.mul(cadet)).
+		add(clift.mul(abdet))
+	return det.estimate()
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,196 @@
+package robust
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func rat(f float64) *big.Rat { return new(big.Rat).SetFloat64(f) }
+
+// orient2DRat computes the sign of the orientation determinant using rationals.
+func orient2DRat(a, b, c [2]float64) int {
+	acx := new(big.Rat).Sub(rat(a[0]), rat(c[0]))
+	bcy := new(big.Rat).Sub(rat(b[1]), rat(c[1]))
+	acy := new(big.Rat).Sub(rat(a[1]), rat(c[1]))
+	bcx := new(big.Rat).Sub(rat(b[0]), rat(c[0]))
+	l := new(big.Rat).Mul(acx, bcy)
+	r := new(big.Rat).Mul(acy, bcx)
+	return l.Sub(l, r).Sign()
+}
+
+// inCircleRat computes the sign of the in circle determinant using rationals.
+func inCircleRat(a, b, c, d [2]float64) int {
+	sub := func(x, y float64) *big.Rat { return new(big.Rat).Sub(rat(x), rat(y)) }
+	mul := func(x, y *big.Rat) *big.Rat { return new(big.Rat).Mul(x, y) }
+	add := func(x, y *big.Rat) *big.Rat { return new(big.Rat).Add(x, y) }
+
+	adx, ady := sub(a[0], d[0]), sub(a[1], d[1])
+	bdx, bdy := sub(b[0], d[0]), sub(b[1], d[1])
+	cdx, cdy := sub(c[0], d[0]), sub(c[1], d[1])
+
+	alift := add(mul(adx, adx), mul(ady, ady))
+	blift := add(mul(bdx, bdx), mul(bdy, bdy))
+	clift := add(mul(cdx, cdx), mul(cdy, cdy))
+
+	bcdet := new(big.Rat).Sub(mul(bdx, cdy), mul(cdx, bdy))
+	cadet := new(big.Rat).Sub(mul(cdx, ady), mul(adx, cdy))
+	abdet := new(big.Rat).Sub(mul(adx, bdy), mul(bdx, ady))
+
+	return add(add(mul(alift, bcdet), mul(blift, cadet)), mul(clift, abdet)).Sign()
+}
+
+func sign(f float64) int {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func TestOrient2D(t *testing.T) {
+	type tcase struct {
+		a, b, c  [2]float64
+		expected int
+	}
+
+	tests := map[string]tcase{
+		"counter clockwise": {a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{0, 1}, expected: 1},
+		"clockwise":         {a: [2]float64{0, 0}, b: [2]float64{0, 1}, c: [2]float64{1, 0}, expected: -1},
+		"collinear":         {a: [2]float64{0, 0}, b: [2]float64{1, 1}, c: [2]float64{2, 2}, expected: 0},
+		"nearly collinear": {
+			a:        [2]float64{0.5, 0.5},
+			b:        [2]float64{12, 12},
+			c:        [2]float64{24, math.Nextafter(24, 25)},
+			expected: 1,
+		},
+		"large coordinates": {
+			a:        [2]float64{1507029.9878, 518325.7547},
+			b:        [2]float64{1507022.1120341457, 518332.8225183258},
+			c:        [2]float64{1507029.9833, 518325.7458},
+			expected: orient2DRat([2]float64{1507029.9878, 518325.7547}, [2]float64{1507022.1120341457, 518332.8225183258}, [2]float64{1507029.9833, 518325.7458}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := sign(Orient2D(tc.a, tc.b, tc.c)); got != tc.expected {
+				t.Errorf("orientation, expected %v got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestOrient2DNearlyCollinear(t *testing.T) {
+	// points on a grid of ulps around a line, where naive arithmetic fails
+	// the majority of the time.
+	a := [2]float64{0.5, 0.5}
+	b := [2]float64{12, 12}
+	c := [2]float64{24, 24}
+	for i := 0; i < 64; i++ {
+		for j := 0; j < 64; j++ {
+			p := [2]float64{a[0], a[1]}
+			for k := 0; k < i; k++ {
+				p[0] = math.Nextafter(p[0], 1)
+			}
+			for k := 0; k < j; k++ {
+				p[1] = math.Nextafter(p[1], 1)
+			}
+			if got, exp := sign(Orient2D(p, b, c)), orient2DRat(p, b, c); got != exp {
+				t.Fatalf("orientation of %v, expected %v got %v", p, exp, got)
+			}
+		}
+	}
+}
+
+// crossRat computes the sign of the cross product using rationals.
+func crossRat(a, b, c, d [2]float64) int {
+	abx := new(big.Rat).Sub(rat(b[0]), rat(a[0]))
+	cdy := new(big.Rat).Sub(rat(d[1]), rat(c[1]))
+	aby := new(big.Rat).Sub(rat(b[1]), rat(a[1]))
+	cdx := new(big.Rat).Sub(rat(d[0]), rat(c[0]))
+	l := new(big.Rat).Mul(abx, cdy)
+	r := new(big.Rat).Mul(aby, cdx)
+	return l.Sub(l, r).Sign()
+}
+
+func TestCross(t *testing.T) {
+	type tcase struct {
+		a, b, c, d [2]float64
+		expected   int
+	}
+
+	// the products of the differences round to the same value
+	near := 1 + 1.0/(1<<30)
+	tests := map[string]tcase{
+		"counter clockwise": {a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{5, 5}, d: [2]float64{5, 6}, expected: 1},
+		"clockwise":         {a: [2]float64{0, 0}, b: [2]float64{0, 1}, c: [2]float64{5, 5}, d: [2]float64{6, 5}, expected: -1},
+		"parallel":          {a: [2]float64{0, 0}, b: [2]float64{1, 1}, c: [2]float64{0, 5}, d: [2]float64{3, 8}, expected: 0},
+		"nearly parallel": {
+			a:        [2]float64{0, 0},
+			b:        [2]float64{near, 1},
+			c:        [2]float64{0, 0},
+			d:        [2]float64{1 + 1.0/(1<<29), near},
+			expected: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if exp := crossRat(tc.a, tc.b, tc.c, tc.d); exp != tc.expected {
+				t.Fatalf("test case, expected %v got %v from rationals", tc.expected, exp)
+			}
+			if got := sign(Cross(tc.a, tc.b, tc.c, tc.d)); got != tc.expected {
+				t.Errorf("cross, expected %v got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	type tcase struct {
+		a, b, c, d [2]float64
+		expected   int
+	}
+
+	tests := []tcase{
+		{a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{0, 1}, d: [2]float64{0.5, 0.5}, expected: 1},
+		{a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{0, 1}, d: [2]float64{2, 2}, expected: -1},
+		{a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{0, 1}, d: [2]float64{1, 1}, expected: 0},
+		// reversed orientation flips the sign
+		{a: [2]float64{0, 0}, b: [2]float64{0, 1}, c: [2]float64{1, 0}, d: [2]float64{0.5, 0.5}, expected: -1},
+		{a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{0, 1}, d: [2]float64{1, math.Nextafter(1, 2)}, expected: -1},
+		{a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{0, 1}, d: [2]float64{1, math.Nextafter(1, 0)}, expected: 1},
+	}
+
+	for i, tc := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if got := sign(InCircle(tc.a, tc.b, tc.c, tc.d)); got != tc.expected {
+				t.Errorf("in circle, expected %v got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestInCircleNearlyCocircular(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		// points on a circle, snapped to float64, with a fourth point nudged by a few ulps
+		angle := func() [2]float64 {
+			th := r.Float64() * 2 * math.Pi
+			return [2]float64{1000 + 100*math.Cos(th), 1000 + 100*math.Sin(th)}
+		}
+		a, b, c, d := angle(), angle(), angle(), angle()
+		for k := r.Intn(4); k > 0; k-- {
+			d[0] = math.Nextafter(d[0], 0)
+		}
+		if got, exp := sign(InCircle(a, b, c, d)), inCircleRat(a, b, c, d); got != exp {
+			t.Fatalf("in circle of %v %v %v %v, expected %v got %v", a, b, c, d, exp, got)
+		}
+	}
+}
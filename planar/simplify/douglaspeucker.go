@@ -92,7 +92,9 @@ func (dp DouglasPeucker) simplify(ctx context.Context, depth uint8, linestring [
 		if debug {
 			printf("returning combined lines: %v %v", depth, rec1, rec2)
 		}
-		return append(rec1, rec2...), nil
+		// rec1 can be a part of linestring, so it is capped to not append
+		// over the rest of the caller's points.
+		return append(rec1[:len(rec1):len(rec1)], rec2...), nil
 	}
 
 	// Drop all points between the end points.
@@ -22,6 +22,7 @@ func TestDouglasPeucker(t *testing.T) {
 
 	fn := func(t *testing.T, tc tcase) {
 		ctx := context.Background()
+		l := append([][2]float64(nil), tc.l...)
 		gl, err := tc.dp.Simplify(ctx, tc.l, false)
 		// Douglas Peucker should never return an error.
 		// This is more of a sanity check.
@@ -33,6 +34,10 @@ func TestDouglasPeucker(t *testing.T) {
 			t.Errorf("simplified points, expected %v got %v", tc.el, gl)
 			return
 		}
+		if !reflect.DeepEqual(l, tc.l) {
+			t.Errorf("points changed, expected %v got %v", l, tc.l)
+			return
+		}
 
 		if ignoreSanityCheck {
 			return
@@ -56,6 +61,13 @@ func TestDouglasPeucker(t *testing.T) {
 			},
 			el: [][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}},
 		},
+		"dropped points after a kept point": {
+			l: [][2]float64{{0, 0}, {1, 5}, {2, 0.1}, {3, 0}, {4, 0.1}, {5, 0}},
+			dp: DouglasPeucker{
+				Tolerance: 0.5,
+			},
+			el: [][2]float64{{0, 0}, {1, 5}, {2, 0.1}, {5, 0}},
+		},
 	}
 
 	for name, tc := range tests {
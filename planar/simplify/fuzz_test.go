@@ -0,0 +1,133 @@
+package simplify
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/geom/internal/tcase"
+	"github.com/go-spatial/geom/planar"
+)
+
+// lines returns the point sequences of the geometry that are simplified, in
+// order.
+func lines(g geom.Geometry) (lns [][][2]float64) {
+	switch g := g.(type) {
+	case geom.LineStringer:
+		return [][][2]float64{g.Verticies()}
+	case geom.MultiLineStringer:
+		return g.LineStrings()
+	case geom.Polygoner:
+		return g.LinearRings()
+	case geom.MultiPolygoner:
+		for _, p := range g.Polygons() {
+			lns = append(lns, p...)
+		}
+	case geom.Collectioner:
+		for _, gg := range g.Geometries() {
+			lns = append(lns, lines(gg)...)
+		}
+	}
+	return lns
+}
+
+/*
+matches reports if sln, which starts at the start of ln, has points of ln
+in order, ends at the end of ln, and the points of ln between are within
+the tolerance of the segment they are dropped for. Points can repeat in ln,
+so each one that matches is tried.
+*/
+func matches(ln, sln [][2]float64, tolerance float64) bool {
+	if len(sln) == 1 {
+		return len(ln) == 1
+	}
+	seg := [2][2]float64{sln[0], sln[1]}
+	for j := 1; j < len(ln); j++ {
+		if ln[j] == sln[1] && matches(ln[j:], sln[1:], tolerance) {
+			return true
+		}
+		if planar.PerpendicularDistance(seg, ln[j]) > tolerance {
+			return false
+		}
+	}
+	return false
+}
+
+/*
+FuzzDouglasPeucker checks that the simplified lines keep their end points,
+only have points of the lines, and that every point that is dropped is
+within the tolerance of the line that replaces it. The geometry is WKB;
+the corpus is seeded from the wkb test cases.
+*/
+func FuzzDouglasPeucker(f *testing.F) {
+	geos, err := tcase.Geometries(tcase.WKBTestdata())
+	if err != nil {
+		f.Fatalf("error getting test cases: %v", err)
+	}
+	for _, g := range geos {
+		data, err := wkb.EncodeBytes(g)
+		if err != nil {
+			f.Fatalf("encode error, expected nil got %v", err)
+		}
+		f.Add(data, 0.0)
+		f.Add(data, 1.0)
+		f.Add(data, 100.0)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, tolerance float64) {
+		if math.IsNaN(tolerance) {
+			return
+		}
+		g, err := wkb.DecodeBytes(data)
+		if err != nil {
+			return
+		}
+		in := lines(g)
+		for _, ln := range in {
+			for _, pt := range ln {
+				if math.IsNaN(pt[0]) || math.IsInf(pt[0], 0) || math.IsNaN(pt[1]) || math.IsInf(pt[1], 0) {
+					return
+				}
+			}
+		}
+
+		// a copy of the lines, to check g is not changed
+		orig := make([][][2]float64, len(in))
+		for i := range in {
+			orig[i] = append([][2]float64(nil), in[i]...)
+		}
+
+		sg, err := planar.Simplify(context.Background(), DouglasPeucker{Tolerance: tolerance}, g)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		for i := range in {
+			if len(in[i]) != len(orig[i]) || len(in[i]) > 0 && !reflect.DeepEqual(in[i], orig[i]) {
+				t.Fatalf("line %v of the input, expected unchanged %v got %v", i, orig[i], in[i])
+			}
+		}
+		out := lines(sg)
+		if len(out) != len(in) {
+			t.Fatalf("number of lines, expected %v got %v", len(in), len(out))
+		}
+		for i := range in {
+			ln, sln := in[i], out[i]
+			if len(ln) == 0 {
+				if len(sln) != 0 {
+					t.Errorf("line %v, expected empty got %v", i, sln)
+				}
+				continue
+			}
+			if len(sln) < 2 && len(ln) >= 2 || sln[0] != ln[0] || sln[len(sln)-1] != ln[len(ln)-1] {
+				t.Errorf("line %v, expected the end points of %v got %v", i, ln, sln)
+				continue
+			}
+			if !matches(ln, sln, tolerance) {
+				t.Errorf("line %v, expected the dropped points within %v of the simplified line", i, tolerance)
+			}
+		}
+	})
+}
@@ -0,0 +1,3 @@
+package snapround
+
+const debug = false
@@ -0,0 +1,415 @@
+/*
+Package snapround rounds geometries to the grid of a geom.PrecisionModel
+without changing their topology.
+
+Plain rounding of vertices can make segments cross that did not cross
+before. Snap-rounding avoids this by first finding every vertex and every
+intersection point and rounding those to "hot pixels" (grid cells). Every
+segment that passes through a hot pixel is then bent to go through the
+center of that pixel. The result has all vertices on the grid, and
+segments only meet at vertices.
+
+Ref: Hobby, J. D., "Practical segment intersection with finite precision output"
+*/
+package snapround
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/rtreego"
+	"github.com/go-spatial/geom/planar/intersect"
+)
+
+// Geometry will return the snap-rounded version of the given geometry.
+// Line strings and rings that collapse are removed, as are polygons whose
+// exterior ring collapses. If nothing remains nil is returned. A floating
+// precision model returns the geometry unchanged.
+func Geometry(ctx context.Context, pm *geom.PrecisionModel, geo geom.Geometry) (geom.Geometry, error) {
+	if pm.IsFloating() || geo == nil {
+		return geo, nil
+	}
+
+	var paths []path
+	if err := collectPaths(geo, &paths); err != nil {
+		return nil, err
+	}
+
+	snapped, err := snapPaths(ctx, pm, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	b := builder{pm: pm, paths: snapped}
+	return b.geometry(geo), nil
+}
+
+// Lines will snap-round the given segments against each other, returning
+// for each segment the points it passes through on the grid.
+func Lines(ctx context.Context, pm *geom.PrecisionModel, segs []geom.Line) ([][][2]float64, error) {
+	paths := make([]path, len(segs))
+	for i := range segs {
+		paths[i] = path{pts: segs[i][:]}
+	}
+	if pm.IsFloating() {
+		ret := make([][][2]float64, len(paths))
+		for i := range paths {
+			ret[i] = paths[i].pts
+		}
+		return ret, nil
+	}
+	return snapPaths(ctx, pm, paths)
+}
+
+type path struct {
+	pts    [][2]float64
+	closed bool
+}
+
+// collectPaths gathers all the linear components of the geometry, in the
+// order the builder will consume them.
+func collectPaths(geo geom.Geometry, paths *[]path) error {
+	switch g := geo.(type) {
+	case geom.Pointer, geom.MultiPointer:
+		return nil
+	case geom.LineStringer:
+		*paths = append(*paths, path{pts: g.Verticies()})
+		return nil
+	case geom.MultiLineStringer:
+		for _, ls := range g.LineStrings() {
+			*paths = append(*paths, path{pts: ls})
+		}
+		return nil
+	case geom.Polygoner:
+		for _, r := range g.LinearRings() {
+			*paths = append(*paths, path{pts: r, closed: true})
+		}
+		return nil
+	case geom.MultiPolygoner:
+		for _, p := range g.Polygons() {
+			for _, r := range p {
+				*paths = append(*paths, path{pts: r, closed: true})
+			}
+		}
+		return nil
+	case geom.Collectioner:
+		for _, child := range g.Geometries() {
+			if err := collectPaths(child, paths); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return geom.ErrUnknownGeometry{Geom: geo}
+	}
+}
+
+type hotPixel struct {
+	pt   [2]float64
+	rect *rtreego.Rect
+}
+
+func (hp *hotPixel) Bounds() *rtreego.Rect { return hp.rect }
+
+func rectFor(minx, miny, maxx, maxy float64) *rtreego.Rect {
+	// rtreego does not allow zero lengths
+	const smallep = 0.00001
+	rect, err := rtreego.NewRect(rtreego.Point{minx, miny}, []float64{(maxx - minx) + smallep, (maxy - miny) + smallep})
+	if err != nil {
+		panic("Assumption broken:" + err.Error())
+	}
+	return rect
+}
+
+type segRef struct {
+	path int
+	line geom.Line
+}
+
+func snapPaths(ctx context.Context, pm *geom.PrecisionModel, paths []path) ([][][2]float64, error) {
+	var segs []segRef
+	for i := range paths {
+		pts := paths[i].pts
+		for j := 1; j < len(pts); j++ {
+			segs = append(segs, segRef{path: i, line: geom.Line{pts[j-1], pts[j]}})
+		}
+		if paths[i].closed && len(pts) > 2 && pts[0] != pts[len(pts)-1] {
+			segs = append(segs, segRef{path: i, line: geom.Line{pts[len(pts)-1], pts[0]}})
+		}
+	}
+
+	// Find all the hot pixels; these are the vertices and intersections.
+	hot := make(map[[2]float64]struct{})
+	for i := range paths {
+		for _, pt := range paths[i].pts {
+			hot[pm.MakePrecisePoint(pt)] = struct{}{}
+		}
+	}
+
+	lines := make([]geom.Line, 0, len(segs))
+	for i := range segs {
+		if segs[i].line[0] != segs[i].line[1] {
+			lines = append(lines, segs[i].line)
+		}
+	}
+	eq := intersect.NewEventQueue(lines)
+	eq.Precision = pm
+	err := eq.FindIntersects(ctx, true, func(_, _ int, pt [2]float64) error {
+		hot[pt] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if debug {
+		log.Printf("found %v hot pixels for %v segments", len(hot), len(segs))
+	}
+
+	half := pm.GridSize() / 2
+	spatials := make([]rtreego.Spatial, 0, len(hot))
+	for pt := range hot {
+		spatials = append(spatials, &hotPixel{
+			pt:   pt,
+			rect: rectFor(pt[0]-half, pt[1]-half, pt[0]+half, pt[1]+half),
+		})
+	}
+	tree := rtreego.NewTree(2, 25, 50, spatials...)
+
+	snapped := make([][][2]float64, len(paths))
+	for i := range paths {
+		snapped[i] = make([][2]float64, 0, len(paths[i].pts))
+	}
+
+	for _, seg := range segs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		a, b := seg.line[0], seg.line[1]
+		minx, maxx := a[0], b[0]
+		if minx > maxx {
+			minx, maxx = maxx, minx
+		}
+		miny, maxy := a[1], b[1]
+		if miny > maxy {
+			miny, maxy = maxy, miny
+		}
+
+		var pixels [][2]float64
+		for _, s := range tree.SearchIntersect(rectFor(minx-half, miny-half, maxx+half, maxy+half)) {
+			hp := s.(*hotPixel)
+			if segmentHitsPixel(seg.line, hp.pt, half) {
+				pixels = append(pixels, hp.pt)
+			}
+		}
+		sort.Sort(byParam{line: seg.line, pts: pixels})
+
+		pts := snapped[seg.path]
+		pts = append(pts, pm.MakePrecisePoint(a))
+		pts = append(pts, pixels...)
+		snapped[seg.path] = pts
+	}
+
+	for i := range paths {
+		pts := snapped[i]
+		if !paths[i].closed && len(paths[i].pts) > 0 {
+			pts = append(pts, pm.MakePrecisePoint(paths[i].pts[len(paths[i].pts)-1]))
+		}
+		pts = dedupe(pts)
+		if paths[i].closed && len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+			pts = pts[:len(pts)-1]
+		}
+		snapped[i] = pts
+	}
+
+	return snapped, nil
+}
+
+// dedupe removes consecutive duplicate points.
+func dedupe(pts [][2]float64) [][2]float64 {
+	if len(pts) == 0 {
+		return pts
+	}
+	ret := pts[:1]
+	for _, pt := range pts[1:] {
+		if pt != ret[len(ret)-1] {
+			ret = append(ret, pt)
+		}
+	}
+	return ret
+}
+
+// segmentHitsPixel reports whether the segment passes through the square
+// centered on c with the given half width. (Liang–Barsky)
+func segmentHitsPixel(seg geom.Line, c [2]float64, half float64) bool {
+	a := seg[0]
+	dx, dy := seg[1][0]-a[0], seg[1][1]-a[1]
+
+	p := [4]float64{-dx, dx, -dy, dy}
+	q := [4]float64{
+		a[0] - (c[0] - half),
+		(c[0] + half) - a[0],
+		a[1] - (c[1] - half),
+		(c[1] + half) - a[1],
+	}
+
+	t0, t1 := 0.0, 1.0
+	for i := range p {
+		if p[i] == 0 {
+			if q[i] < 0 {
+				return false
+			}
+			continue
+		}
+		r := q[i] / p[i]
+		if p[i] < 0 {
+			if r > t1 {
+				return false
+			}
+			if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return false
+			}
+			if r < t1 {
+				t1 = r
+			}
+		}
+	}
+	return true
+}
+
+// byParam sorts points by their position along the line.
+type byParam struct {
+	line geom.Line
+	pts  [][2]float64
+}
+
+func (bp byParam) param(pt [2]float64) float64 {
+	dx, dy := bp.line[1][0]-bp.line[0][0], bp.line[1][1]-bp.line[0][1]
+	return (pt[0]-bp.line[0][0])*dx + (pt[1]-bp.line[0][1])*dy
+}
+
+func (bp byParam) Len() int           { return len(bp.pts) }
+func (bp byParam) Swap(i, j int)      { bp.pts[i], bp.pts[j] = bp.pts[j], bp.pts[i] }
+func (bp byParam) Less(i, j int) bool { return bp.param(bp.pts[i]) < bp.param(bp.pts[j]) }
+
+// builder rebuilds a geometry from the snapped paths, consuming them in
+// the same order collectPaths produced them.
+type builder struct {
+	pm    *geom.PrecisionModel
+	paths [][][2]float64
+	idx   int
+}
+
+func (b *builder) next() [][2]float64 {
+	p := b.paths[b.idx]
+	b.idx++
+	return p
+}
+
+func (b *builder) lineString() [][2]float64 {
+	ls := b.next()
+	if len(ls) < 2 {
+		return nil
+	}
+	return ls
+}
+
+func (b *builder) polygon(rings int) [][][2]float64 {
+	plg := make([][][2]float64, 0, rings)
+	for i := 0; i < rings; i++ {
+		r := b.next()
+		if len(r) < 3 || ringArea(r) == 0 {
+			if i == 0 {
+				// without an exterior there is no polygon, but we
+				// still need to consume the interior rings.
+				b.idx += rings - 1
+				return nil
+			}
+			continue
+		}
+		plg = append(plg, r)
+	}
+	return plg
+}
+
+func (b *builder) geometry(geo geom.Geometry) geom.Geometry {
+	switch g := geo.(type) {
+	case geom.Pointer:
+		return geom.Point(b.pm.MakePrecisePoint(g.XY()))
+
+	case geom.MultiPointer:
+		pts := g.Points()
+		mp := make(geom.MultiPoint, len(pts))
+		for i := range pts {
+			mp[i] = b.pm.MakePrecisePoint(pts[i])
+		}
+		return mp
+
+	case geom.LineStringer:
+		ls := b.lineString()
+		if ls == nil {
+			return nil
+		}
+		return geom.LineString(ls)
+
+	case geom.MultiLineStringer:
+		var mls geom.MultiLineString
+		for range g.LineStrings() {
+			if ls := b.lineString(); ls != nil {
+				mls = append(mls, ls)
+			}
+		}
+		if len(mls) == 0 {
+			return nil
+		}
+		return mls
+
+	case geom.Polygoner:
+		plg := b.polygon(len(g.LinearRings()))
+		if plg == nil {
+			return nil
+		}
+		return geom.Polygon(plg)
+
+	case geom.MultiPolygoner:
+		var mplg geom.MultiPolygon
+		for _, p := range g.Polygons() {
+			if plg := b.polygon(len(p)); plg != nil {
+				mplg = append(mplg, plg)
+			}
+		}
+		if len(mplg) == 0 {
+			return nil
+		}
+		return mplg
+
+	case geom.Collectioner:
+		var coll geom.Collection
+		for _, child := range g.Geometries() {
+			if cg := b.geometry(child); cg != nil {
+				coll = append(coll, cg)
+			}
+		}
+		if len(coll) == 0 {
+			return nil
+		}
+		return coll
+	}
+	return nil
+}
+
+// ringArea returns the signed area of a ring whose first point is not
+// repeated at the end.
+func ringArea(r [][2]float64) (area float64) {
+	for i := range r {
+		j := (i + 1) % len(r)
+		area += r[i][0]*r[j][1] - r[j][0]*r[i][1]
+	}
+	return area / 2
+}
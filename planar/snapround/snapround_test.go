@@ -0,0 +1,128 @@
+package snapround
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestGeometry(t *testing.T) {
+	type tcase struct {
+		pm       *geom.PrecisionModel
+		geom     geom.Geometry
+		expected geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			g, err := Geometry(context.Background(), tc.pm, tc.geom)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if tc.expected == nil {
+				if g != nil {
+					t.Errorf("geometry, expected nil got %v", g)
+				}
+				return
+			}
+			if !cmp.GeometryEqual(tc.expected, g) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, g)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"floating": {
+			geom:     geom.LineString{{0.1, 0.1}, {2.3, 4.5}},
+			expected: geom.LineString{{0.1, 0.1}, {2.3, 4.5}},
+		},
+		"point": {
+			pm:       geom.NewFixedPrecisionModel(1),
+			geom:     geom.Point{0.4, 2.6},
+			expected: geom.Point{0, 3},
+		},
+		"crossing lines share the intersection": {
+			pm: geom.NewFixedPrecisionModel(1),
+			geom: geom.MultiLineString{
+				{{0, 0}, {10, 1}},
+				{{4.2, -3}, {5.4, 3}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {5, 0}, {10, 1}},
+				{{4, -3}, {5, 0}, {5, 3}},
+			},
+		},
+		"line through a vertex's pixel": {
+			pm: geom.NewFixedPrecisionModel(1),
+			geom: geom.MultiLineString{
+				{{0, 0}, {10, 0.2}},
+				{{5.1, 0.3}, {5.1, 8}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {5, 0}, {10, 0}},
+				{{5, 0}, {5, 8}},
+			},
+		},
+		"collapsed line": {
+			pm:   geom.NewFixedPrecisionModel(1),
+			geom: geom.LineString{{0.1, 0.1}, {0.2, 0.3}},
+		},
+		"polygon with collapsed hole": {
+			pm: geom.NewFixedPrecisionModel(1),
+			geom: geom.Polygon{
+				{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+				{{2.1, 2.1}, {2.2, 2.4}, {2.4, 2.2}},
+			},
+			expected: geom.Polygon{
+				{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+			},
+		},
+		"collapsed polygon": {
+			pm: geom.NewFixedPrecisionModel(1),
+			geom: geom.MultiPolygon{
+				{{{0, 0}, {0, 0.2}, {0.3, 0}}},
+			},
+		},
+		"collection": {
+			pm: geom.NewFixedPrecisionModel(10),
+			geom: geom.Collection{
+				geom.Point{0.04, 0.06},
+				geom.LineString{{0.01, 0.01}, {1.01, 1.01}},
+			},
+			expected: geom.Collection{
+				geom.Point{0, 0.1},
+				geom.LineString{{0, 0}, {1, 1}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSegmentHitsPixel(t *testing.T) {
+	type tcase struct {
+		seg      geom.Line
+		pt       [2]float64
+		expected bool
+	}
+
+	tests := map[string]tcase{
+		"through":   {seg: geom.Line{{0, 0}, {10, 10}}, pt: [2]float64{5, 5}, expected: true},
+		"corner":    {seg: geom.Line{{0, 1}, {1, 0}}, pt: [2]float64{1, 1}, expected: true},
+		"miss":      {seg: geom.Line{{0, 0}, {10, 0}}, pt: [2]float64{5, 1}, expected: false},
+		"past end":  {seg: geom.Line{{0, 0}, {3, 0}}, pt: [2]float64{5, 0}, expected: false},
+		"end point": {seg: geom.Line{{0, 0}, {3.2, 0}}, pt: [2]float64{3, 0}, expected: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := segmentHitsPixel(tc.seg, tc.pt, 0.5); got != tc.expected {
+				t.Errorf("hit, expected %v got %v", tc.expected, got)
+			}
+		})
+	}
+}
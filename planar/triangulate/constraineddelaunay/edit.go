@@ -0,0 +1,494 @@
+package constraineddelaunay
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/planar/robust"
+	"github.com/go-spatial/geom/planar/triangulate"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+var ErrConstraintNotFound = errors.New("constraint not found")
+var ErrFrameVertex = errors.New("vertex is part of the frame")
+
+/*
+locateTriangle finds the triangle that contains v, or has v on one of its
+edges. The locator of the subdivision finds an edge close to v, and the
+triangle is found from there by walking towards v with robust orientation
+tests, as the search of the subdivision may not finish on a constrained
+triangulation. If the walk fails all of the edges are searched.
+
+Returns a quadedge with the triangle on its left.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) locateTriangle(v quadedge.Vertex) (*quadedge.QuadEdge, error) {
+	if start, err := tri.subdiv.Locate(v); err == nil {
+		if e, ok := tri.walkToTriangle(start, v); ok {
+			return e, nil
+		}
+	}
+
+	for _, qe := range tri.subdiv.GetPrimaryEdges(true) {
+		for _, e := range []*quadedge.QuadEdge{qe, qe.Sym()} {
+			if isTriangle(e) && exitEdge(e, v) == nil {
+				return e, nil
+			}
+		}
+	}
+	return nil, quadedge.ErrLocateFailure{V: &v}
+}
+
+// isTriangle returns weather the face on the left of e is a counter
+// clockwise triangle.
+func isTriangle(e *quadedge.QuadEdge) bool {
+	return e.LNext().LNext().LNext() == e && robust.Orient2D(e.Orig(), e.Dest(), e.LNext().Dest()) > 0
+}
+
+// exitEdge returns the first edge of the triangle on the left of e
+// that has v on its right, or nil if v is in the triangle.
+func exitEdge(e *quadedge.QuadEdge, v quadedge.Vertex) *quadedge.QuadEdge {
+	for i, f := 0, e; i < 3; i, f = i+1, f.LNext() {
+		if robust.Orient2D(f.Orig(), f.Dest(), v) < 0 {
+			return f
+		}
+	}
+	return nil
+}
+
+/*
+walkToTriangle walks from the triangle on either side of e to the triangle
+that contains v, crossing an edge that has v on its right at each step. The
+edges of a triangle are tried from a different one at each step, so that
+the walk does not go around in circles on a constrained triangulation.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) walkToTriangle(e *quadedge.QuadEdge, v quadedge.Vertex) (*quadedge.QuadEdge, bool) {
+	if !isTriangle(e) {
+		e = e.Sym()
+	}
+	maxIter := len(tri.subdiv.GetEdges())
+	for iter := 0; iter < maxIter; iter++ {
+		if !isTriangle(e) {
+			return nil, false
+		}
+		for i := 0; i < iter%3; i++ {
+			e = e.LNext()
+		}
+		f := exitEdge(e, v)
+		if f == nil {
+			return e, true
+		}
+		e = f.Sym()
+	}
+	return nil, false
+}
+
+/*
+swapEdge turns the edge counterclockwise inside of the quadrilateral made by
+the triangles on both sides, and updates the vertex index.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) swapEdge(e *quadedge.QuadEdge) error {
+	toRemove := map[*quadedge.QuadEdge]bool{e: true, e.Sym(): true}
+	if err := tri.removeEdgesFromVertexIndex(toRemove, e.Orig()); err != nil {
+		return err
+	}
+	if err := tri.removeEdgesFromVertexIndex(toRemove, e.Dest()); err != nil {
+		return err
+	}
+	quadedge.Swap(e)
+	return nil
+}
+
+/*
+legalize swaps edges until none of the given edges, or the edges that are
+swapped as a result, have a vertex in the circumcircle of the triangle on the
+other side. Constraints are never swapped, so the result is a constrained
+Delaunay triangulation around the edges.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) legalize(edges []*quadedge.QuadEdge) error {
+	for len(edges) > 0 {
+		e := edges[len(edges)-1]
+		edges = edges[:len(edges)-1]
+
+		if !e.IsLive() || tri.IsConstraint(e) {
+			continue
+		}
+		// the edges of the frame are on the outside
+		if tri.subdiv.IsFrameVertex(e.Orig()) && tri.subdiv.IsFrameVertex(e.Dest()) {
+			continue
+		}
+		// both sides must be triangles
+		if e.LNext().LNext().LNext() != e || e.Sym().LNext().LNext().LNext() != e.Sym() {
+			continue
+		}
+		a, b := e.Orig(), e.Dest()
+		c, d := e.LNext().Dest(), e.Sym().LNext().Dest()
+		if !isInCircle(a, b, c, d) {
+			continue
+		}
+		// the quadrilateral must be convex to swap the diagonal
+		if robust.Orient2D(c, d, a)*robust.Orient2D(c, d, b) >= 0 {
+			continue
+		}
+		if debug {
+			log.Printf("legalize: swapping %v", e)
+		}
+		if err := tri.swapEdge(e); err != nil {
+			return err
+		}
+		edges = append(edges, e.LNext(), e.LPrev(), e.Sym().LNext(), e.Sym().LPrev())
+	}
+	return nil
+}
+
+/*
+InsertPoint inserts the vertex v into the triangulation, and swaps edges
+around it to keep a constrained Delaunay triangulation. If v is on a
+constraint the constraint is split, and both parts keep its data. Nothing is
+changed if v is already a vertex of the triangulation.
+
+The vertex must be inside of the frame of the triangulation, which is
+created by InsertGeometries around the geometries.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) InsertPoint(v quadedge.Vertex) error {
+	if tri.subdiv == nil {
+		return quadedge.ErrLocateFailure{V: &v}
+	}
+	if _, ok := tri.vertexIndex[v]; ok {
+		return nil
+	}
+	if err := tri.insertVertex(v); err != nil {
+		return err
+	}
+	tri.edited, tri.refinement = true, nil
+
+	return tri.Validate()
+}
+
+/*
+insertVertex inserts v, which is not a vertex of the triangulation yet, and
+swaps edges around it to keep a constrained Delaunay triangulation.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) insertVertex(v quadedge.Vertex) error {
+	e, err := tri.locateTriangle(v)
+	if err != nil {
+		return err
+	}
+
+	var onEdge *quadedge.QuadEdge
+	for i, f := 0, e; i < 3; i, f = i+1, f.LNext() {
+		if robust.Orient2D(f.Orig(), f.Dest(), v) == 0 {
+			onEdge = f
+		}
+	}
+
+	switch {
+	case onEdge != nil:
+		if tri.subdiv.IsFrameVertex(onEdge.Orig()) && tri.subdiv.IsFrameVertex(onEdge.Dest()) {
+			return quadedge.ErrLocateFailure{V: &v}
+		}
+		if err := tri.splitEdge(onEdge, v); err != nil {
+			return err
+		}
+
+	default:
+		// connect v to the vertices of the triangle
+		base := tri.subdiv.MakeEdge(e.Orig(), v)
+		quadedge.Splice(base, e)
+		start := base
+		for {
+			base = tri.subdiv.Connect(e, base.Sym())
+			e = base.OPrev()
+			if e.LNext() == start {
+				break
+			}
+		}
+		tri.vertexIndex[v] = start.Sym()
+	}
+	return tri.legalizeAround(v)
+}
+
+/*
+legalizeAround legalizes the edges opposite of the vertex v, which may not
+be Delaunay anymore once v has been added.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) legalizeAround(v quadedge.Vertex) error {
+	var opposite []*quadedge.QuadEdge
+	start := tri.vertexIndex[v]
+	for s := start; ; {
+		opposite = append(opposite, s.LNext())
+		if s = s.ONext(); s == start {
+			break
+		}
+	}
+	return tri.legalize(opposite)
+}
+
+/*
+RemoveVertex removes the vertex v and its edges from the triangulation, and
+triangulates the hole left behind again. Constraints that end at v are
+removed; if v is between two parts of a straight constraint the parts are
+joined into a single constraint.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) RemoveVertex(v quadedge.Vertex) error {
+	start, ok := tri.vertexIndex[v]
+	if !ok {
+		return quadedge.ErrLocateFailure{V: &v}
+	}
+	if tri.subdiv.IsFrameVertex(v) {
+		return ErrFrameVertex
+	}
+
+	// the edges from v, and the vertices and edges around it counter
+	// clockwise. The edges around v are the boundary of the hole.
+	var (
+		spokes, boundary, constrained []*quadedge.QuadEdge
+		ring                          []quadedge.Vertex
+	)
+	for s := start; ; {
+		spokes = append(spokes, s)
+		boundary = append(boundary, s.LNext())
+		ring = append(ring, s.Dest())
+		if tri.IsConstraint(s) {
+			constrained = append(constrained, s)
+		}
+		if s = s.ONext(); s == start {
+			break
+		}
+	}
+
+	var joined *triangulate.Segment
+	var joinedData []interface{}
+	if len(constrained) == 2 {
+		a, b := constrained[0].Dest(), constrained[1].Dest()
+		if robust.Orient2D(a, v, b) == 0 && a.Sub(v).Dot(b.Sub(v)) < 0 {
+			if !cmp.PointLess(a, b) {
+				a, b = b, a
+			}
+			seg := triangulate.NewSegment(geom.Line{a, b})
+			joined = &seg
+			joinedData = mergeData(constrained[0].GetData(), constrained[1].GetData())
+		}
+	}
+
+	delete(tri.vertexIndex, v)
+	for _, s := range spokes {
+		tri.removeConstraintEdge(s)
+		toRemove := map[*quadedge.QuadEdge]bool{s: true, s.Sym(): true}
+		if err := tri.removeEdgesFromVertexIndex(toRemove, s.Dest()); err != nil {
+			return err
+		}
+		tri.subdiv.Delete(s)
+	}
+
+	// ear clip the hole; legalize fixes the triangles up after.
+	newEdges := append([]*quadedge.QuadEdge{}, boundary...)
+	for len(ring) > 3 {
+		n := len(ring)
+		ear := -1
+		for j := 0; j < n && ear < 0; j++ {
+			if isEar(ring, j) {
+				ear = j
+			}
+		}
+		if ear < 0 {
+			return fmt.Errorf("no ear found in the hole around %v: %v", v, ring)
+		}
+		prev, next := (ear+n-1)%n, (ear+1)%n
+		// the edges into the ear's previous vertex and out of its next
+		// vertex have the hole on the left.
+		e := tri.subdiv.Connect(boundary[(ear+n-2)%n], boundary[next])
+		newEdges = append(newEdges, e)
+		boundary[prev] = e
+		ring = append(ring[:ear], ring[ear+1:]...)
+		boundary = append(boundary[:ear], boundary[ear+1:]...)
+	}
+	if err := tri.legalize(newEdges); err != nil {
+		return err
+	}
+
+	if joined != nil {
+		tri.constraints[*joined] = true
+		if err := tri.insertEdgeCDT(joined, nil); err != nil {
+			return err
+		}
+		qe, err := tri.LocateSegment(joined.GetStart(), joined.GetEnd())
+		if err != nil {
+			return err
+		}
+		if joinedData != nil {
+			qe.SetData(joinedData)
+			qe.Sym().SetData(joinedData)
+		}
+	}
+	tri.edited, tri.refinement = true, nil
+
+	return tri.Validate()
+}
+
+// isEar returns weather the vertex j of the counter clockwise ring can be
+// cut off.
+func isEar(ring []quadedge.Vertex, j int) bool {
+	n := len(ring)
+	a, b, c := ring[(j+n-1)%n], ring[j], ring[(j+1)%n]
+	if robust.Orient2D(a, b, c) <= 0 {
+		return false
+	}
+	for _, p := range ring {
+		if p.Equals(a) || p.Equals(b) || p.Equals(c) {
+			continue
+		}
+		if robust.Orient2D(a, b, p) >= 0 && robust.Orient2D(b, c, p) >= 0 && robust.Orient2D(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeData returns the data of both edges, without repeating values.
+func mergeData(d1, d2 interface{}) []interface{} {
+	var ret []interface{}
+	for _, d := range []interface{}{d1, d2} {
+		arr, _ := d.([]interface{})
+	NextValue:
+		for _, v := range arr {
+			for _, r := range ret {
+				if reflect.DeepEqual(r, v) {
+					continue NextValue
+				}
+			}
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+/*
+RemoveConstraint removes the constraint along l, and swaps edges to make the
+triangulation Delaunay around it again. The line may be made of several
+constraint edges, as constraints are split where they cross. The edges are
+kept, along with the vertices on the line, but are no longer constraints and
+lose their data.
+
+If there is no constraint from one end of l to the other
+ErrConstraintNotFound is returned and nothing is changed.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) RemoveConstraint(l geom.Line) error {
+	if tri.subdiv == nil {
+		return ErrConstraintNotFound
+	}
+	a, b := quadedge.Vertex(l[0]), quadedge.Vertex(l[1])
+	if a.Equals(b) {
+		return ErrConstraintNotFound
+	}
+	ab := b.Sub(a)
+
+	var edges []*quadedge.QuadEdge
+	for cur := a; !cur.Equals(b); {
+		start, ok := tri.vertexIndex[cur]
+		if !ok {
+			return ErrConstraintNotFound
+		}
+		var next *quadedge.QuadEdge
+		for e := start; ; {
+			d := e.Dest()
+			// d is on the line, further along than cur and not past b.
+			onLine := robust.Orient2D(a, b, d) == 0
+			if onLine && tri.IsConstraint(e) && d.Sub(cur).Dot(ab) > 0 && b.Sub(d).Dot(ab) >= 0 {
+				next = e
+				break
+			}
+			if e = e.ONext(); e == start {
+				break
+			}
+		}
+		if next == nil {
+			return ErrConstraintNotFound
+		}
+		edges = append(edges, next)
+		cur = next.Dest()
+	}
+
+	for _, e := range edges {
+		tri.removeConstraintEdge(e)
+		e.SetData(nil)
+		e.Sym().SetData(nil)
+	}
+	if err := tri.legalize(edges); err != nil {
+		return err
+	}
+	tri.edited, tri.refinement = true, nil
+
+	return tri.Validate()
+}
+
+/*
+snapshot returns the geometries and data that make up the triangulation as
+it is now; the constraints, and a MultiPoint of all the vertices. Refine uses
+this after the triangulation has been edited.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) snapshot() ([]geom.Geometry, []interface{}) {
+	var (
+		geoms []geom.Geometry
+		data  []interface{}
+		plain geom.MultiLineString
+	)
+	for _, e := range tri.subdiv.GetPrimaryEdges(false) {
+		if !tri.IsConstraint(e) {
+			continue
+		}
+		line := [][2]float64{e.Orig(), e.Dest()}
+		arr, _ := e.GetData().([]interface{})
+		if len(arr) == 0 {
+			plain = append(plain, line)
+			continue
+		}
+		for _, d := range arr {
+			geoms = append(geoms, geom.LineString(line))
+			data = append(data, d)
+		}
+	}
+	if len(plain) > 0 {
+		geoms = append(geoms, plain)
+		data = append(data, nil)
+	}
+
+	var pts geom.MultiPoint
+	for v := range tri.vertexIndex {
+		if !tri.subdiv.IsFrameVertex(v) {
+			pts = append(pts, v)
+		}
+	}
+	geoms = append(geoms, pts)
+	data = append(data, nil)
+
+	for _, d := range data {
+		if d != nil {
+			return geoms, data
+		}
+	}
+	return geoms, nil
+}
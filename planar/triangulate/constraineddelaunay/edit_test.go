@@ -0,0 +1,314 @@
+package constraineddelaunay
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+// checkDelaunay checks that every edge that is not a constraint is Delaunay.
+func checkDelaunay(t *testing.T, tri *Triangulator) {
+	t.Helper()
+	for _, e := range tri.subdiv.GetPrimaryEdges(true) {
+		if tri.IsConstraint(e) || (tri.subdiv.IsFrameVertex(e.Orig()) && tri.subdiv.IsFrameVertex(e.Dest())) {
+			continue
+		}
+		if e.LNext().LNext().LNext() != e || e.Sym().LNext().LNext().LNext() != e.Sym() {
+			continue
+		}
+		a, b := e.Orig(), e.Dest()
+		c, d := e.LNext().Dest(), e.Sym().LNext().Dest()
+		if robust.Orient2D(c, d, a)*robust.Orient2D(c, d, b) >= 0 {
+			continue
+		}
+		if isInCircle(a, b, c, d) {
+			t.Errorf("edge %v, is not Delaunay", e)
+		}
+	}
+}
+
+// isEdgeConstraint returns weather there is a constraint edge from a to b.
+func isEdgeConstraint(tri *Triangulator, a, b quadedge.Vertex) bool {
+	qe, err := tri.LocateSegment(a, b)
+	return err == nil && tri.IsConstraint(qe)
+}
+
+func newEditTriangulator(t *testing.T, g geom.Geometry, data interface{}) *Triangulator {
+	t.Helper()
+	uut := new(Triangulator)
+	uut.validate = true
+	if err := uut.InsertGeometries([]geom.Geometry{g}, []interface{}{data}); err != nil {
+		t.Fatalf("insert error, expected nil got %v", err)
+	}
+	return uut
+}
+
+func TestInsertPoint(t *testing.T) {
+	type tcase struct {
+		point quadedge.Vertex
+		err   error
+		// constraints that are expected after the insert
+		constraints []geom.Line
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			uut := newEditTriangulator(t, geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}, "square")
+			err := uut.InsertPoint(tc.point)
+			if reflect.TypeOf(err) != reflect.TypeOf(tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if _, ok := uut.vertexIndex[tc.point]; !ok {
+				t.Errorf("vertex index, expected %v", tc.point)
+			}
+			checkDelaunay(t, uut)
+			for _, l := range tc.constraints {
+				qe, err := uut.LocateSegment(l[0], l[1])
+				if err != nil || !uut.IsConstraint(qe) {
+					t.Errorf("constraint %v, expected to exist", l)
+					continue
+				}
+				if data := qe.GetData(); !reflect.DeepEqual(data, []interface{}{"square"}) {
+					t.Errorf("constraint %v data, expected [square] got %v", l, data)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside": {
+			point: quadedge.Vertex{3, 4},
+		},
+		"center": {
+			point: quadedge.Vertex{5, 5},
+		},
+		"existing vertex": {
+			point: quadedge.Vertex{10, 10},
+		},
+		"on constraint": {
+			point:       quadedge.Vertex{5, 0},
+			constraints: []geom.Line{{{0, 0}, {5, 0}}, {{5, 0}, {10, 0}}},
+		},
+		"outside": {
+			point: quadedge.Vertex{15, 5},
+		},
+		"outside of the frame": {
+			point: quadedge.Vertex{1000, 5},
+			err:   quadedge.ErrLocateFailure{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRemoveVertex(t *testing.T) {
+	type tcase struct {
+		geom   geom.Geometry
+		insert []quadedge.Vertex
+		remove quadedge.Vertex
+		err    error
+		// constraints that are expected after the removal
+		constraints []geom.Line
+		// edges that are expected to be gone after the removal
+		removed []geom.Line
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			uut := newEditTriangulator(t, tc.geom, "data")
+			for _, v := range tc.insert {
+				if err := uut.InsertPoint(v); err != nil {
+					t.Fatalf("insert point error, expected nil got %v", err)
+				}
+			}
+			err := uut.RemoveVertex(tc.remove)
+			if reflect.TypeOf(err) != reflect.TypeOf(tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if _, ok := uut.vertexIndex[tc.remove]; ok {
+				t.Errorf("vertex index, expected %v to be removed", tc.remove)
+			}
+			for _, e := range uut.GetEdges() {
+				if e[0] == tc.remove || e[1] == tc.remove {
+					t.Errorf("edge %v, expected %v to be removed", e, tc.remove)
+				}
+			}
+			checkDelaunay(t, uut)
+			for _, l := range tc.constraints {
+				if !isEdgeConstraint(uut, l[0], l[1]) {
+					t.Errorf("constraint %v, expected to exist", l)
+				}
+			}
+			for _, l := range tc.removed {
+				if isEdgeConstraint(uut, l[0], l[1]) {
+					t.Errorf("constraint %v, expected to be removed", l)
+				}
+			}
+		}
+	}
+
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	tests := map[string]tcase{
+		"inserted point": {
+			geom:        square,
+			insert:      []quadedge.Vertex{{5, 5}, {2, 7}},
+			remove:      quadedge.Vertex{5, 5},
+			constraints: []geom.Line{{{0, 0}, {10, 0}}, {{10, 10}, {0, 10}}},
+		},
+		"point on constraint": {
+			geom:        square,
+			insert:      []quadedge.Vertex{{5, 0}, {5, 2}},
+			remove:      quadedge.Vertex{5, 0},
+			constraints: []geom.Line{{{0, 0}, {10, 0}}},
+		},
+		"corner": {
+			geom:    geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {-2, 5}}},
+			remove:  quadedge.Vertex{-2, 5},
+			removed: []geom.Line{{{0, 10}, {-2, 5}}, {{-2, 5}, {0, 0}}},
+		},
+		"many neighbours": {
+			geom:   geom.MultiPoint{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {5, 5}, {5, 0}, {10, 5}, {5, 10}, {0, 5}, {2, 2}, {8, 8}},
+			remove: quadedge.Vertex{5, 5},
+		},
+		"unknown vertex": {
+			geom:   square,
+			remove: quadedge.Vertex{3, 3},
+			err:    quadedge.ErrLocateFailure{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLocateTriangle(t *testing.T) {
+	// thin constrained triangles between long constraints, which are not
+	// Delaunay.
+	var lines geom.MultiLineString
+	for y := 0.0; y <= 10; y++ {
+		lines = append(lines, [][2]float64{{0, y}, {100, y + 0.5}})
+	}
+	uut := newEditTriangulator(t, lines, "lines")
+	for x := 0.5; x < 100; x += 7 {
+		for y := 0.25; y < 10; y += 0.75 {
+			v := quadedge.Vertex{x, y}
+			e, err := uut.locateTriangle(v)
+			if err != nil {
+				t.Fatalf("locate %v error, expected nil got %v", v, err)
+			}
+			if !isTriangle(e) || exitEdge(e, v) != nil {
+				t.Errorf("locate %v, expected a triangle containing it got %v", v, e)
+			}
+		}
+	}
+}
+
+func TestRemoveConstraint(t *testing.T) {
+	type tcase struct {
+		// the geometry to triangulate; nil for two lines crossing at 5,5
+		geom geom.Geometry
+		line geom.Line
+		err  error
+		// constraints that are expected after the removal
+		constraints []geom.Line
+		// edges that are expected to not be constraints after the removal
+		removed []geom.Line
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			g := tc.geom
+			if g == nil {
+				g = geom.Collection{
+					geom.MultiLineString{{{0, 0}, {10, 10}}, {{0, 10}, {10, 0}}},
+					geom.MultiPoint{{0, 5}, {10, 5}},
+				}
+			}
+			uut := newEditTriangulator(t, g, "lines")
+			err := uut.RemoveConstraint(tc.line)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			checkDelaunay(t, uut)
+			for _, l := range tc.constraints {
+				if !isEdgeConstraint(uut, l[0], l[1]) {
+					t.Errorf("constraint %v, expected to exist", l)
+				}
+			}
+			for _, l := range tc.removed {
+				if isEdgeConstraint(uut, l[0], l[1]) {
+					t.Errorf("constraint %v, expected to be removed", l)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"crossing": {
+			line:        geom.Line{{0, 0}, {10, 10}},
+			constraints: []geom.Line{{{0, 10}, {5, 5}}, {{5, 5}, {10, 0}}},
+			removed:     []geom.Line{{{5, 5}, {10, 10}}},
+		},
+		"reversed": {
+			line:        geom.Line{{10, 10}, {0, 0}},
+			constraints: []geom.Line{{{0, 10}, {5, 5}}, {{5, 5}, {10, 0}}},
+			removed:     []geom.Line{{{5, 5}, {10, 10}}},
+		},
+		"part": {
+			line:        geom.Line{{5, 5}, {10, 10}},
+			constraints: []geom.Line{{{0, 0}, {5, 5}}},
+			removed:     []geom.Line{{{5, 5}, {10, 10}}},
+		},
+		"not a constraint": {
+			line: geom.Line{{0, 0}, {0, 5}},
+			err:  ErrConstraintNotFound,
+		},
+		"bent": {
+			// the middle vertex is only just off of the line
+			geom: geom.Collection{
+				geom.LineString{{0, 0}, {5e5, 1e-4}, {1e6, 0}},
+				geom.MultiPoint{{5e5, 1e5}, {5e5, -1e5}},
+			},
+			line: geom.Line{{0, 0}, {1e6, 0}},
+			err:  ErrConstraintNotFound,
+		},
+		"unknown vertex": {
+			line: geom.Line{{1, 0}, {0, 10}},
+			err:  ErrConstraintNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEditRefine(t *testing.T) {
+	uut := newEditTriangulator(t, geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}, "square")
+	uut.validate = false
+	if err := uut.InsertPoint(quadedge.Vertex{3, 4}); err != nil {
+		t.Fatalf("insert point error, expected nil got %v", err)
+	}
+	if err := uut.Refine(RefineOptions{MinAngle: 20}); err != nil {
+		t.Fatalf("refine error, expected nil got %v", err)
+	}
+	if _, ok := uut.vertexIndex[quadedge.Vertex{3, 4}]; !ok {
+		t.Errorf("vertex index, expected the inserted point to be kept")
+	}
+	checkConforming(t, uut)
+}
@@ -0,0 +1,204 @@
+package constraineddelaunay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+// binaryMagic starts the binary encoding of a triangulator, followed by the
+// version of the encoding.
+var binaryMagic = [4]byte{'C', 'D', 'T', 'R'}
+
+const binaryVersion = 1
+
+var ErrInvalidEncoding = errors.New("invalid triangulator encoding")
+
+// triangulatorData is the encoded form of a triangulator.
+type triangulatorData struct {
+	Tolerance   float64                       `json:"tolerance"`
+	Subdivision *quadedge.QuadEdgeSubdivision `json:"subdivision"`
+	// the constraints, with the lesser point first
+	Constraints [][2][2]float64 `json:"constraints"`
+}
+
+/*
+encode returns the encoded form of the triangulator. The constraints are
+sorted so the encoding is repeatable.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) encode() *triangulatorData {
+	d := &triangulatorData{
+		Tolerance:   tri.tolerance,
+		Subdivision: tri.subdiv,
+		Constraints: make([][2][2]float64, 0, len(tri.constraints)),
+	}
+	for seg := range tri.constraints {
+		d.Constraints = append(d.Constraints, seg.GetLineSegment())
+	}
+	sort.Slice(d.Constraints, func(i, j int) bool {
+		a, b := d.Constraints[i], d.Constraints[j]
+		for k := 0; k < 4; k++ {
+			if a[k/2][k%2] != b[k/2][k%2] {
+				return a[k/2][k%2] < b[k/2][k%2]
+			}
+		}
+		return false
+	})
+	return d
+}
+
+/*
+decode sets up the triangulator from its encoded form, and rebuilds the
+vertex index.
+
+The geometries given to InsertGeometries are not part of the encoding, so
+Refine works from the constraints of the restored triangulation, as it does
+after the triangulation has been edited.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) decode(d *triangulatorData) error {
+	if d.Subdivision == nil {
+		return ErrInvalidEncoding
+	}
+	*tri = Triangulator{
+		constraints: make(map[triangulate.Segment]bool, len(d.Constraints)),
+		subdiv:      d.Subdivision,
+		tolerance:   d.Tolerance,
+		vertexIndex: make(map[quadedge.Vertex]*quadedge.QuadEdge),
+		edited:      true,
+	}
+	for _, l := range d.Constraints {
+		tri.constraints[triangulate.NewSegment(geom.Line(l))] = true
+	}
+
+	edges := tri.subdiv.GetPrimaryEdges(true)
+	for _, e := range edges {
+		if _, ok := tri.vertexIndex[e.Orig()]; ok == false {
+			tri.vertexIndex[e.Orig()] = e
+		}
+		if _, ok := tri.vertexIndex[e.Dest()]; ok == false {
+			tri.vertexIndex[e.Dest()] = e.Sym()
+		}
+	}
+	for seg := range tri.constraints {
+		if _, err := tri.LocateSegment(seg.GetStart(), seg.GetEnd()); err != nil {
+			return ErrInvalidEncoding
+		}
+	}
+	return nil
+}
+
+/*
+MarshalJSON encodes the triangulation as JSON; the subdivision, including
+the data of each edge, and the constraints.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tri.encode())
+}
+
+/*
+UnmarshalJSON restores a triangulation encoded by MarshalJSON.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) UnmarshalJSON(buf []byte) error {
+	var d triangulatorData
+	if err := json.Unmarshal(buf, &d); err != nil {
+		return err
+	}
+	return tri.decode(&d)
+}
+
+/*
+MarshalBinary encodes the triangulation in a compact little endian binary
+form, which holds the binary encoding of the subdivision.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) MarshalBinary() ([]byte, error) {
+	d := tri.encode()
+	if d.Subdivision == nil {
+		return nil, ErrInvalidEncoding
+	}
+	subdiv, err := d.Subdivision.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := func(v interface{}) { binary.Write(&buf, binary.LittleEndian, v) }
+
+	buf.Write(binaryMagic[:])
+	buf.WriteByte(binaryVersion)
+	w(d.Tolerance)
+	w(uint32(len(d.Constraints)))
+	w(d.Constraints)
+	w(uint32(len(subdiv)))
+	buf.Write(subdiv)
+	return buf.Bytes(), nil
+}
+
+/*
+UnmarshalBinary restores a triangulation encoded by MarshalBinary.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) UnmarshalBinary(buf []byte) error {
+	var d triangulatorData
+	r := bytes.NewReader(buf)
+	var err error
+	read := func(v interface{}) {
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, v)
+		}
+	}
+	// count reads a length, limited by the remaining bytes so a corrupt
+	// length does not allocate too much.
+	count := func(size int) int {
+		var n uint32
+		read(&n)
+		if err == nil && int64(n)*int64(size) > int64(r.Len()) {
+			err = ErrInvalidEncoding
+		}
+		return int(n)
+	}
+
+	var magic [4]byte
+	var version uint8
+	read(&magic)
+	read(&version)
+	if err == nil && (magic != binaryMagic || version != binaryVersion) {
+		return ErrInvalidEncoding
+	}
+	read(&d.Tolerance)
+	d.Constraints = make([][2][2]float64, count(32))
+	read(d.Constraints)
+	subdiv := make([]byte, count(1))
+	read(subdiv)
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrInvalidEncoding
+	}
+	if err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return ErrInvalidEncoding
+	}
+	d.Subdivision = new(quadedge.QuadEdgeSubdivision)
+	if err := d.Subdivision.UnmarshalBinary(subdiv); err != nil {
+		return err
+	}
+	return tri.decode(&d)
+}
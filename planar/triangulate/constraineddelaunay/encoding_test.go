@@ -0,0 +1,91 @@
+package constraineddelaunay
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+// edgeMap returns the constraint flag and data of each edge of tri.
+func edgeMap(tri *Triangulator) map[geom.Line]string {
+	ret := make(map[geom.Line]string)
+	for _, e := range tri.Edges() {
+		ret[e.Line] = fmt.Sprint(e.Constraint, e.Data)
+	}
+	return ret
+}
+
+func TestEncoding(t *testing.T) {
+	type tcase struct {
+		marshal   func(*Triangulator) ([]byte, error)
+		unmarshal func(*Triangulator, []byte) error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			tri := new(Triangulator)
+			err := tri.InsertGeometries(
+				[]geom.Geometry{
+					geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+					geom.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}}},
+					geom.MultiPoint{{5, 5}, {15, 3}},
+				},
+				[]interface{}{"left", "right", nil},
+			)
+			if err != nil {
+				t.Fatalf("insert error, expected nil got %v", err)
+			}
+
+			buf, err := tc.marshal(tri)
+			if err != nil {
+				t.Fatalf("marshal error, expected nil got %v", err)
+			}
+			var uut Triangulator
+			if err := tc.unmarshal(&uut, buf); err != nil {
+				t.Fatalf("unmarshal error, expected nil got %v", err)
+			}
+			if err := uut.GetSubdivision().Validate(); err != nil {
+				t.Errorf("subdivision validate, expected nil got %v", err)
+			}
+			if err := uut.Validate(); err != nil {
+				t.Errorf("validate, expected nil got %v", err)
+			}
+			if expected, got := edgeMap(tri), edgeMap(&uut); !reflect.DeepEqual(got, expected) {
+				t.Errorf("edges, expected %v got %v", expected, got)
+			}
+			if !reflect.DeepEqual(uut.constraints, tri.constraints) {
+				t.Errorf("constraints, expected %v got %v", tri.constraints, uut.constraints)
+			}
+			again, err := tc.marshal(&uut)
+			if err != nil {
+				t.Fatalf("marshal error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(again, buf) {
+				t.Errorf("expected the same encoding after a round trip")
+			}
+
+			// the restored triangulation can be edited
+			if err := uut.InsertPoint(quadedge.Vertex{10, 5}); err != nil {
+				t.Fatalf("insert point error, expected nil got %v", err)
+			}
+			if !isEdgeConstraint(&uut, quadedge.Vertex{10, 0}, quadedge.Vertex{10, 5}) {
+				t.Errorf("edge (10 0, 10 5), expected a constraint")
+			}
+			if err := uut.Validate(); err != nil {
+				t.Errorf("validate, expected nil got %v", err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"json":   {(*Triangulator).MarshalJSON, (*Triangulator).UnmarshalJSON},
+		"binary": {(*Triangulator).MarshalBinary, (*Triangulator).UnmarshalBinary},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,116 @@
+package constraineddelaunay
+
+import (
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+/*
+Edge is an edge of the triangulation, as returned by Edges and Triangles.
+*/
+type Edge struct {
+	Line geom.Line
+	// Constraint is true if the edge is part of a constraint.
+	Constraint bool
+	// Data is the data given to InsertGeometries for each of the
+	// geometries the constraint came from, or nil if there is none.
+	Data []interface{}
+}
+
+/*
+Face is a triangle of the triangulation, as returned by Triangles.
+*/
+type Face struct {
+	// Triangle holds the vertices, counter clockwise in a y-up coordinate
+	// system.
+	Triangle geom.Triangle
+	// Edges holds the edges of the triangle; edge i goes from vertex i to
+	// vertex i+1.
+	Edges [3]Edge
+	// Neighbours holds the index of the face on the other side of each
+	// edge, or -1 if the edge is on the outside of the triangulation.
+	Neighbours [3]int
+}
+
+// newEdge returns the edge for the quadedge, with a copy of its data.
+func (tri *Triangulator) newEdge(e *quadedge.QuadEdge) Edge {
+	ret := Edge{
+		Line:       geom.Line{e.Orig(), e.Dest()},
+		Constraint: tri.IsConstraint(e),
+	}
+	if arr, ok := e.GetData().([]interface{}); ok && len(arr) > 0 {
+		ret.Data = append([]interface{}{}, arr...)
+	}
+	return ret
+}
+
+/*
+Edges returns the edges of the triangulation, without the edges of the
+frame. The lines start at the lesser point.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) Edges() []Edge {
+	if tri.subdiv == nil {
+		return nil
+	}
+	qes := tri.subdiv.GetPrimaryEdges(false)
+	edges := make([]Edge, len(qes))
+	for i, e := range qes {
+		edges[i] = tri.newEdge(e)
+	}
+	return edges
+}
+
+/*
+Triangles returns the triangles of the triangulation, without the triangles
+that touch the frame, along with their edges and neighbours.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) Triangles() []Face {
+	if tri.subdiv == nil {
+		return nil
+	}
+
+	var (
+		faces []Face
+		// the faces to the left of each quadedge
+		index = make(map[*quadedge.QuadEdge]int)
+		// the first quadedge of each face
+		starts []*quadedge.QuadEdge
+	)
+	for _, qe := range tri.subdiv.GetPrimaryEdges(true) {
+		for _, e := range []*quadedge.QuadEdge{qe, qe.Sym()} {
+			if _, ok := index[e]; ok || e.LNext().LNext().LNext() != e {
+				continue
+			}
+			if tri.subdiv.IsFrameEdge(e) || tri.subdiv.IsFrameEdge(e.LNext()) {
+				continue
+			}
+			if robust.Orient2D(e.Orig(), e.Dest(), e.LNext().Dest()) <= 0 {
+				continue
+			}
+			var f Face
+			for i, g := 0, e; i < 3; i, g = i+1, g.LNext() {
+				index[g] = len(faces)
+				f.Triangle[i] = g.Orig()
+				f.Edges[i] = tri.newEdge(g)
+			}
+			faces = append(faces, f)
+			starts = append(starts, e)
+		}
+	}
+
+	for i, e := range starts {
+		for j, g := 0, e; j < 3; j, g = j+1, g.LNext() {
+			n, ok := index[g.Sym()]
+			if !ok {
+				n = -1
+			}
+			faces[i].Neighbours[j] = n
+		}
+	}
+	return faces
+}
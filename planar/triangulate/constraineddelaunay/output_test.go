@@ -0,0 +1,81 @@
+package constraineddelaunay
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+func TestEdgesAndTriangles(t *testing.T) {
+	uut := new(Triangulator)
+	err := uut.InsertGeometries(
+		[]geom.Geometry{
+			geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			geom.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}}},
+		},
+		[]interface{}{"left", "right"},
+	)
+	if err != nil {
+		t.Fatalf("insert error, expected nil got %v", err)
+	}
+
+	expected := map[geom.Line][]interface{}{
+		{{0, 0}, {10, 0}}:    {"left"},
+		{{0, 0}, {0, 10}}:    {"left"},
+		{{0, 10}, {10, 10}}:  {"left"},
+		{{10, 0}, {10, 10}}:  {"left", "right"},
+		{{10, 0}, {20, 0}}:   {"right"},
+		{{10, 10}, {20, 10}}: {"right"},
+		{{20, 0}, {20, 10}}:  {"right"},
+	}
+	edges := uut.Edges()
+	if len(edges) != 9 {
+		t.Errorf("edges, expected 9 got %v", len(edges))
+	}
+	for _, e := range edges {
+		data, ok := expected[e.Line]
+		if e.Constraint != ok {
+			t.Errorf("edge %v constraint, expected %v got %v", e.Line, ok, e.Constraint)
+		}
+		if !reflect.DeepEqual(e.Data, data) {
+			t.Errorf("edge %v data, expected %v got %v", e.Line, data, e.Data)
+		}
+	}
+
+	faces := uut.Triangles()
+	if len(faces) != 4 {
+		t.Fatalf("triangles, expected 4 got %v", len(faces))
+	}
+	outside := 0
+	for i, f := range faces {
+		if robust.Orient2D(f.Triangle[0], f.Triangle[1], f.Triangle[2]) <= 0 {
+			t.Errorf("triangle %v, expected counter clockwise", f.Triangle)
+		}
+		for j, n := range f.Neighbours {
+			e := f.Edges[j]
+			if e.Line[0] != f.Triangle[j] || e.Line[1] != f.Triangle[(j+1)%3] {
+				t.Errorf("triangle %v edge %v, expected to go from vertex %v", f.Triangle, e.Line, j)
+			}
+			if n == -1 {
+				outside++
+				continue
+			}
+			// the neighbour has the same edge the other way around
+			found := false
+			for k, m := range faces[n].Neighbours {
+				ne := faces[n].Edges[k].Line
+				if m == i && ne[0] == e.Line[1] && ne[1] == e.Line[0] {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("triangle %v edge %v, neighbour %v does not refer back", f.Triangle, e.Line, faces[n].Triangle)
+			}
+		}
+	}
+	if outside != 6 {
+		t.Errorf("outside edges, expected 6 got %v", outside)
+	}
+}
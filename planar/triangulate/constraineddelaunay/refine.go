@@ -0,0 +1,637 @@
+package constraineddelaunay
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/planar/prepared"
+	"github.com/go-spatial/geom/planar/robust"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+// DefaultMaxSteinerPoints is the number of points Refine may add when
+// RefineOptions.MaxSteinerPoints is zero.
+const DefaultMaxSteinerPoints = 100000
+
+// ErrTooManySteinerPoints is returned by Refine when it would need to add
+// more points than allowed to meet the quality constraints.
+var ErrTooManySteinerPoints = errors.New("refinement needs more steiner points than allowed")
+
+/*
+RefineOptions are the quality constraints Refine enforces on the triangles
+of the triangulation.
+*/
+type RefineOptions struct {
+	// MinAngle is the smallest angle, in degrees, allowed in a triangle.
+	// Refinement is guaranteed to finish for angles up to about 20.7
+	// degrees, and usually does up to about 33 degrees. Triangles between
+	// two constraints that meet at a smaller angle can not be fixed.
+	MinAngle float64
+	// MaxArea is the largest area allowed for a triangle, or zero for no
+	// limit.
+	MaxArea float64
+	// MaxSteinerPoints is the largest number of points that may be
+	// added, or zero for DefaultMaxSteinerPoints.
+	MaxSteinerPoints int
+}
+
+// segmentRef refers to a segment of the refinement.
+type segmentRef struct {
+	group, idx int
+}
+
+// refinement is the state of the refinement of a triangulation.
+type refinement struct {
+	// the constraint segments of each input geometry, and of the convex
+	// hull when there are no polygons, as they have been split.
+	segments [][]geom.Line
+	// the points added inside of the domain.
+	points [][2]float64
+	// the vertices of the input geometries.
+	inputVertices map[[2]float64]bool
+	// the input vertex each point added on a circle around one was split
+	// from.
+	shells map[[2]float64][2]float64
+	// the area that is refined.
+	domain *prepared.Polygon
+	// the number of points added so far.
+	added int
+	// set once the segments are all constraints of the triangulation, so
+	// that points can be inserted into it.
+	incremental bool
+}
+
+// errSplitVertex is returned by insertSteinerPoints when a segment can not
+// be split at a point.
+var errSplitVertex = errors.New("split point is already a vertex")
+
+// segmentSplit is a segment of the refinement and where it is split.
+type segmentSplit struct {
+	s geom.Line
+	p [2]float64
+}
+
+// distance returns the distance between two points.
+func distance(a, b [2]float64) float64 { return math.Hypot(b[0]-a[0], b[1]-a[1]) }
+
+// encroaches returns weather p is inside of the diametral circle of the
+// segment.
+func encroaches(s geom.Line, p [2]float64) bool {
+	return (s[0][0]-p[0])*(s[1][0]-p[0])+(s[0][1]-p[1])*(s[1][1]-p[1]) < 0
+}
+
+// edgeKey returns a key for the edge that does not depend on its direction.
+func edgeKey(a, b [2]float64) [2][2]float64 {
+	if a[0] > b[0] || (a[0] == b[0] && a[1] > b[1]) {
+		a, b = b, a
+	}
+	return [2][2]float64{a, b}
+}
+
+// convexHull returns the convex hull of the points, counter clockwise in a
+// y-up coordinate system (Andrew's monotone chain).
+func convexHull(pts [][2]float64) [][2]float64 {
+	pts = append([][2]float64{}, pts...)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i][0] != pts[j][0] {
+			return pts[i][0] < pts[j][0]
+		}
+		return pts[i][1] < pts[j][1]
+	})
+	hull := make([][2]float64, 0, 2*len(pts))
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range pts {
+			for len(hull) >= start+2 && robust.Orient2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		// the last point is the first point of the other chain
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	return hull
+}
+
+// collectPolygons appends the polygons of the geometry to plygs.
+func collectPolygons(plygs [][][][2]float64, g geom.Geometry) [][][][2]float64 {
+	switch gg := g.(type) {
+	case geom.Polygoner:
+		plygs = append(plygs, gg.LinearRings())
+	case geom.MultiPolygoner:
+		plygs = append(plygs, gg.Polygons()...)
+	case geom.Collectioner:
+		for _, cg := range gg.Geometries() {
+			plygs = collectPolygons(plygs, cg)
+		}
+	}
+	return plygs
+}
+
+/*
+newRefinement sets up the refinement of the triangulation. The domain is
+the inside of the polygons of the input, or the convex hull of the input if
+there are none. The edges of the convex hull are then also used as
+constraints.
+*/
+func (tri *Triangulator) newRefinement() (*refinement, error) {
+	r := &refinement{
+		inputVertices: make(map[[2]float64]bool),
+		shells:        make(map[[2]float64][2]float64),
+	}
+	var vertices [][2]float64
+	for _, g := range tri.input {
+		pts, err := geom.GetCoordinates(g)
+		if err != nil {
+			return nil, err
+		}
+		for _, pt := range pts {
+			if !r.inputVertices[pt] {
+				r.inputVertices[pt] = true
+				vertices = append(vertices, pt)
+			}
+		}
+		lines, err := geom.ExtractLines(g)
+		if err != nil {
+			return nil, err
+		}
+		r.segments = append(r.segments, lines)
+	}
+
+	var plygs [][][][2]float64
+	for _, g := range tri.input {
+		plygs = collectPolygons(plygs, g)
+	}
+	if len(plygs) == 0 {
+		hull := convexHull(vertices)
+		if len(hull) < 3 {
+			// there is nothing to refine
+			return r, nil
+		}
+		plygs = [][][][2]float64{{hull}}
+		lines := make([]geom.Line, len(hull))
+		for i := range hull {
+			lines[i] = geom.Line{hull[i], hull[(i+1)%len(hull)]}
+		}
+		r.segments = append(r.segments, lines)
+	}
+	domain, err := prepared.NewFromPolygons(plygs...)
+	if err != nil {
+		return nil, err
+	}
+	r.domain = domain
+
+	// the triangulation adds vertices where the constraints cross; split
+	// the segments there as well.
+	vertexSet := make(map[[2]float64]bool)
+	for _, e := range tri.subdiv.GetEdgesAsMultiLineString() {
+		vertexSet[e[0]], vertexSet[e[1]] = true, true
+	}
+	for g := range r.segments {
+		var split []geom.Line
+		for _, s := range r.segments[g] {
+			split = append(split, splitAtVertices(s, vertexSet)...)
+		}
+		r.segments[g] = split
+	}
+	return r, nil
+}
+
+// splitAtVertices returns the segment split at the vertices that lie on it.
+func splitAtVertices(s geom.Line, vertices map[[2]float64]bool) []geom.Line {
+	l := distance(s[0], s[1])
+	if l == 0 {
+		return nil
+	}
+	type onSegment struct {
+		t float64
+		v [2]float64
+	}
+	var on []onSegment
+	for v := range vertices {
+		if v == s[0] || v == s[1] {
+			continue
+		}
+		t := ((v[0]-s[0][0])*(s[1][0]-s[0][0]) + (v[1]-s[0][1])*(s[1][1]-s[0][1])) / (l * l)
+		if t <= 0 || t >= 1 {
+			continue
+		}
+		p := [2]float64{s[0][0] + t*(s[1][0]-s[0][0]), s[0][1] + t*(s[1][1]-s[0][1])}
+		if distance(p, v) <= 1e-9*l {
+			on = append(on, onSegment{t, v})
+		}
+	}
+	sort.Slice(on, func(i, j int) bool { return on[i].t < on[j].t })
+	lines := make([]geom.Line, 0, len(on)+1)
+	start := s[0]
+	for _, o := range on {
+		lines = append(lines, geom.Line{start, o.v})
+		start = o.v
+	}
+	return append(lines, geom.Line{start, s[1]})
+}
+
+// splitPoint returns where the segment should be split. Segments with one
+// end at an input vertex are split on circles around that vertex with a
+// radius that is a power of two, so that segments meeting at a small angle
+// are split at the same distances and do not encroach on each other.
+func (r *refinement) splitPoint(s geom.Line) [2]float64 {
+	a, b := s[0], s[1]
+	l := distance(a, b)
+	shell := math.Exp2(math.Round(math.Log2(l / 2)))
+	switch aIn, bIn := r.inputVertices[a], r.inputVertices[b]; {
+	case aIn && !bIn:
+		p := pointOnLine(a, b, shell/l)
+		r.shells[p] = a
+		return p
+	case bIn && !aIn:
+		p := pointOnLine(b, a, shell/l)
+		r.shells[p] = b
+		return p
+	}
+	return pointOnLine(a, b, 0.5)
+}
+
+// pointOnLine returns the point at t along the line from a to b.
+func pointOnLine(a, b [2]float64, t float64) [2]float64 {
+	return [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}
+}
+
+/*
+isNestled returns weather the smallest angle of the triangle is at a
+vertex where two constraint segments meet at a small angle. Splitting such a
+triangle only adds points closer and closer to the vertex, so it is left as
+is. These are triangles that have the vertex as a corner and both of the
+segments as sides, or that have both other corners on the same circle
+around the vertex.
+*/
+func (r *refinement) isNestled(t [3][2]float64, segments map[[2][2]float64]bool) bool {
+	// the smallest angle is opposite of the shortest side
+	k, shortest := 0, math.Inf(1)
+	for i := 0; i < 3; i++ {
+		if d := distance(t[(i+1)%3], t[(i+2)%3]); d < shortest {
+			k, shortest = i, d
+		}
+	}
+	a, p, q := t[k], t[(k+1)%3], t[(k+2)%3]
+	if r.inputVertices[a] && segments[edgeKey(a, p)] && segments[edgeKey(a, q)] {
+		return true
+	}
+	cp, okp := r.shells[p]
+	cq, okq := r.shells[q]
+	if !okp || !okq || cp != cq {
+		return false
+	}
+	dp, dq := distance(cp, p), distance(cq, q)
+	return math.Abs(dp-dq) <= 1e-9*math.Max(dp, dq)
+}
+
+// triangleQuality returns the smallest angle, in degrees, and the area of
+// the triangle.
+func triangleQuality(t [3][2]float64) (angle, area float64) {
+	a, b, c := distance(t[1], t[2]), distance(t[0], t[2]), distance(t[0], t[1])
+	// the smallest angle is opposite of the shortest side
+	if b < a {
+		a, b = b, a
+	}
+	if c < a {
+		a, c = c, a
+	}
+	if b == 0 || c == 0 {
+		return 0, 0
+	}
+	cos := (b*b + c*c - a*a) / (2 * b * c)
+	angle = math.Acos(math.Max(-1, math.Min(1, cos))) * 180 / math.Pi
+	area = math.Abs((t[1][0]-t[0][0])*(t[2][1]-t[0][1])-(t[2][0]-t[0][0])*(t[1][1]-t[0][1])) / 2
+	return angle, area
+}
+
+// circumcenter returns the center and radius of the circle through the
+// points of the triangle.
+func circumcenter(t [3][2]float64) ([2]float64, float64) {
+	bx, by := t[1][0]-t[0][0], t[1][1]-t[0][1]
+	cx, cy := t[2][0]-t[0][0], t[2][1]-t[0][1]
+	d := 2 * (bx*cy - by*cx)
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	ux, uy := (cy*b2-by*c2)/d, (bx*c2-cx*b2)/d
+	return [2]float64{t[0][0] + ux, t[0][1] + uy}, math.Hypot(ux, uy)
+}
+
+/*
+Refine adds points to the triangulation until every triangle inside of the
+domain meets the quality constraints, using Ruppert's algorithm: constraint
+segments that have a vertex inside of their diametral circle are split, and
+points are added at the circumcenters of the triangles that are too thin or
+too large. The domain is the inside of the polygons that were inserted, or
+the convex hull of all the geometries if there are no polygons. Once the
+triangulation has been edited with InsertPoint, RemoveVertex or
+RemoveConstraint the domain is the convex hull of the triangulation.
+
+The constraints are kept, split into smaller segments that keep the data of
+the constraint. As Refine splits any encroached segment, the constraint
+segments of the result are all Delaunay edges.
+
+Refine can be called again with other options to refine further.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) Refine(opts RefineOptions) error {
+	if tri.subdiv == nil {
+		return nil
+	}
+	if tri.edited {
+		tri.input, tri.inputData = tri.snapshot()
+		tri.edited = false
+	}
+	if tri.refinement == nil {
+		r, err := tri.newRefinement()
+		if err != nil {
+			return err
+		}
+		tri.refinement = r
+	}
+	r := tri.refinement
+	if r.domain == nil {
+		return nil
+	}
+	maxPoints := opts.MaxSteinerPoints
+	if maxPoints == 0 {
+		maxPoints = DefaultMaxSteinerPoints
+	}
+
+	for {
+		mp, err := tri.GetTriangles()
+		if err != nil {
+			return err
+		}
+		tris := make([][3][2]float64, 0, len(mp))
+		for _, ply := range mp {
+			if len(ply) == 0 || len(ply[0]) < 3 {
+				continue
+			}
+			tris = append(tris, [3][2]float64{ply[0][0], ply[0][1], ply[0][2]})
+		}
+
+		// the vertices opposite of each edge
+		apexes := make(map[[2][2]float64][][2]float64, len(tris)*3/2)
+		for _, t := range tris {
+			for i := 0; i < 3; i++ {
+				k := edgeKey(t[i], t[(i+1)%3])
+				apexes[k] = append(apexes[k], t[(i+2)%3])
+			}
+		}
+
+		split := make(map[segmentRef]bool)
+		segments := make(map[[2][2]float64]bool)
+		for g := range r.segments {
+			for i, s := range r.segments[g] {
+				segments[edgeKey(s[0], s[1])] = true
+				if r.isEncroached(s, apexes, tris) {
+					split[segmentRef{g, i}] = true
+				}
+			}
+		}
+		r.dropShort(split)
+
+		var points [][2]float64
+		// only add points once no segment is encroached.
+		if len(split) == 0 && (opts.MinAngle > 0 || opts.MaxArea > 0) {
+			points = r.qualityPoints(tris, opts, split, segments)
+			r.dropShort(split)
+		}
+
+		if len(split) == 0 && len(points) == 0 {
+			return nil
+		}
+		r.added += len(split) + len(points)
+		if r.added > maxPoints {
+			return ErrTooManySteinerPoints
+		}
+		if debug {
+			log.Printf("refine: splitting %v segments, adding %v points", len(split), len(points))
+		}
+
+		var splits []segmentSplit
+		for g := range r.segments {
+			segs := make([]geom.Line, 0, len(r.segments[g]))
+			for i, s := range r.segments[g] {
+				if !split[segmentRef{g, i}] {
+					segs = append(segs, s)
+					continue
+				}
+				p := r.splitPoint(s)
+				segs = append(segs, geom.Line{s[0], p}, geom.Line{p, s[1]})
+				splits = append(splits, segmentSplit{s, p})
+			}
+			r.segments[g] = segs
+		}
+		r.points = append(r.points, points...)
+
+		if !r.incremental || tri.insertSteinerPoints(splits, points) != nil {
+			// the first time, or if the points could not be inserted, the
+			// triangulation is built again from the segments and points.
+			if err := tri.rebuild(); err != nil {
+				return err
+			}
+			r.incremental = true
+		}
+	}
+}
+
+/*
+Conform splits the constraint segments until they are all edges of the
+Delaunay triangulation of the vertices, without any other quality
+constraints.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) Conform() error { return tri.Refine(RefineOptions{}) }
+
+// dropShort removes the segments that are too short to be split from split;
+// the points would be too close to tell apart from the end points.
+func (r *refinement) dropShort(split map[segmentRef]bool) {
+	for ref := range split {
+		s := r.segments[ref.group][ref.idx]
+		l := distance(s[0], s[1])
+		scale := math.Max(math.Max(math.Abs(s[0][0]), math.Abs(s[0][1])), math.Max(math.Abs(s[1][0]), math.Abs(s[1][1])))
+		if l <= 1e-6*math.Max(scale, 1) {
+			delete(split, ref)
+		}
+	}
+}
+
+// isEncroached returns weather a vertex of the triangulation is inside of
+// the diametral circle of the segment.
+func (r *refinement) isEncroached(s geom.Line, apexes map[[2][2]float64][][2]float64, tris [][3][2]float64) bool {
+	opposite, ok := apexes[edgeKey(s[0], s[1])]
+	if !ok {
+		// the segment is not an edge, so look at all of the vertices.
+		for _, t := range tris {
+			opposite = append(opposite, t[:]...)
+		}
+	}
+	for _, p := range opposite {
+		if p != s[0] && p != s[1] && encroaches(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualityPoints returns the circumcenters of the triangles in the domain
+// that do not meet the quality constraints, leaving out thin triangles that
+// are nestled in small input angles. Circumcenters that would
+// encroach on a segment are not returned; the segment is marked to be split
+// instead.
+func (r *refinement) qualityPoints(tris [][3][2]float64, opts RefineOptions, split map[segmentRef]bool, segments map[[2][2]float64]bool) [][2]float64 {
+	type badTriangle struct {
+		t     [3][2]float64
+		angle float64
+	}
+	var bad []badTriangle
+	for _, t := range tris {
+		angle, area := triangleQuality(t)
+		if area == 0 || (angle >= opts.MinAngle && (opts.MaxArea <= 0 || area <= opts.MaxArea)) {
+			continue
+		}
+		center := [2]float64{(t[0][0] + t[1][0] + t[2][0]) / 3, (t[0][1] + t[1][1] + t[2][1]) / 3}
+		if !r.domain.ContainsPoint(center) {
+			continue
+		}
+		if (opts.MaxArea <= 0 || area <= opts.MaxArea) && r.isNestled(t, segments) {
+			continue
+		}
+		bad = append(bad, badTriangle{t, angle})
+	}
+	// the worst triangles first
+	sort.SliceStable(bad, func(i, j int) bool { return bad[i].angle < bad[j].angle })
+
+	type circle struct {
+		center [2]float64
+		radius float64
+	}
+	var (
+		added  []circle
+		points [][2]float64
+	)
+NextTriangle:
+	for _, b := range bad {
+		c, radius := circumcenter(b.t)
+		if math.IsNaN(c[0]) || math.IsInf(c[0], 0) || math.IsNaN(c[1]) || math.IsInf(c[1], 0) {
+			continue
+		}
+		// adding a point close to one added in this pass would undo the
+		// work of the other.
+		for _, a := range added {
+			if d := distance(c, a.center); d < a.radius || d < radius {
+				continue NextTriangle
+			}
+		}
+		encroached := false
+		for g := range r.segments {
+			for i, s := range r.segments[g] {
+				if encroaches(s, c) {
+					split[segmentRef{g, i}] = true
+					encroached = true
+				}
+			}
+		}
+		if encroached || !r.domain.ContainsPoint(c) {
+			continue
+		}
+		added = append(added, circle{c, radius})
+		points = append(points, c)
+	}
+	return points
+}
+
+/*
+insertSteinerPoints splits the segments at their points, and inserts the
+points inside of the domain, swapping edges to keep the triangulation
+constrained Delaunay.
+
+If tri is nil a panic will occur.
+*/
+func (tri *Triangulator) insertSteinerPoints(splits []segmentSplit, points [][2]float64) error {
+	for _, sp := range splits {
+		v := quadedge.Vertex(sp.p)
+		if _, ok := tri.vertexIndex[v]; ok {
+			return errSplitVertex
+		}
+		// the point may be off of the segment by a rounding error, so
+		// the edge is split instead of locating the point.
+		e, err := tri.LocateSegment(quadedge.Vertex(sp.s[0]), quadedge.Vertex(sp.s[1]))
+		if err != nil {
+			return err
+		}
+		if err := tri.splitEdge(e, v); err != nil {
+			return err
+		}
+		if err := tri.legalizeAround(v); err != nil {
+			return err
+		}
+	}
+	for _, pt := range points {
+		if _, ok := tri.vertexIndex[quadedge.Vertex(pt)]; ok {
+			continue
+		}
+		if err := tri.insertVertex(quadedge.Vertex(pt)); err != nil {
+			return err
+		}
+	}
+	return tri.Validate()
+}
+
+// rebuild triangulates the split segments and the added points again. The
+// input vertices are sorted so that the triangulation does not depend on
+// the order of the map.
+func (tri *Triangulator) rebuild() error {
+	r := tri.refinement
+	geoms := make([]geom.Geometry, 0, len(r.segments)+1)
+	var data []interface{}
+	for g := range r.segments {
+		geoms = append(geoms, geom.MultiLineString(linesToPoints(r.segments[g])))
+		if len(tri.inputData) > 0 {
+			var d interface{}
+			if g < len(tri.inputData) {
+				d = tri.inputData[g]
+			}
+			data = append(data, d)
+		}
+	}
+	sites := make(geom.MultiPoint, 0, len(r.inputVertices)+len(r.points))
+	for v := range r.inputVertices {
+		sites = append(sites, v)
+	}
+	sort.Slice(sites, func(i, j int) bool { return cmp.PointLess(sites[i], sites[j]) })
+	sites = append(sites, r.points...)
+	geoms = append(geoms, sites)
+	if len(data) > 0 {
+		data = append(data, nil)
+	}
+
+	rebuilt := &Triangulator{tolerance: tri.tolerance, validate: tri.validate}
+	if err := rebuilt.InsertGeometries(geoms, data); err != nil {
+		return err
+	}
+	tri.builder = rebuilt.builder
+	tri.constraints = rebuilt.constraints
+	tri.subdiv = rebuilt.subdiv
+	tri.vertexIndex = rebuilt.vertexIndex
+	return nil
+}
+
+func linesToPoints(lines []geom.Line) [][][2]float64 {
+	ret := make([][][2]float64, len(lines))
+	for i := range lines {
+		ret[i] = [][2]float64{lines[i][0], lines[i][1]}
+	}
+	return ret
+}
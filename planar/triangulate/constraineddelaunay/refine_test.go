@@ -0,0 +1,200 @@
+package constraineddelaunay
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/prepared"
+)
+
+func TestRefine(t *testing.T) {
+	type tcase struct {
+		geom geom.Geometry
+		opts RefineOptions
+		err  error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			uut := new(Triangulator)
+			if err := uut.InsertGeometry(tc.geom); err != nil {
+				t.Fatalf("insert error, expected nil got %v", err)
+			}
+			err := uut.Refine(tc.opts)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if err := uut.Validate(); err != nil {
+				t.Errorf("validate, expected nil got %v", err)
+			}
+
+			domain := uut.refinement.domain
+			segments := make(map[[2][2]float64]bool)
+			for _, segs := range uut.refinement.segments {
+				for _, s := range segs {
+					segments[edgeKey(s[0], s[1])] = true
+				}
+			}
+			tris, err := uut.GetTriangles()
+			if err != nil {
+				t.Fatalf("triangles error, expected nil got %v", err)
+			}
+			for _, ply := range tris {
+				tri := [3][2]float64{ply[0][0], ply[0][1], ply[0][2]}
+				center := [2]float64{(tri[0][0] + tri[1][0] + tri[2][0]) / 3, (tri[0][1] + tri[1][1] + tri[2][1]) / 3}
+				if !domain.ContainsPoint(center) {
+					continue
+				}
+				angle, area := triangleQuality(tri)
+				if angle < tc.opts.MinAngle && !uut.refinement.isNestled(tri, segments) {
+					t.Errorf("triangle %v, expected angle >= %v got %v", tri, tc.opts.MinAngle, angle)
+				}
+				if tc.opts.MaxArea > 0 && area > tc.opts.MaxArea {
+					t.Errorf("triangle %v, expected area <= %v got %v", tri, tc.opts.MaxArea, area)
+				}
+			}
+			checkConforming(t, uut)
+		}
+	}
+
+	tests := map[string]tcase{
+		"square": {
+			geom: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			opts: RefineOptions{MinAngle: 20, MaxArea: 5},
+		},
+		"thin triangle": {
+			geom: geom.Polygon{{{0, 0}, {100, 0}, {50, 3}}},
+			opts: RefineOptions{MinAngle: 25},
+		},
+		"long rectangle": {
+			geom: geom.Polygon{{{0, 0}, {10, 0}, {10, 1}, {0, 1}}},
+			opts: RefineOptions{MinAngle: 30},
+		},
+		"polygon with hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {20, 0}, {20, 20}, {0, 20}},
+				{{8, 8}, {8, 12}, {12, 12}, {12, 8}},
+			},
+			opts: RefineOptions{MinAngle: 20.7},
+		},
+		"points": {
+			geom: geom.MultiPoint{{0, 0}, {10, 0}, {10, 1}, {0, 1}, {5, 0.5}},
+			opts: RefineOptions{MinAngle: 20},
+		},
+		"too many points": {
+			geom: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			opts: RefineOptions{MaxArea: 0.01, MaxSteinerPoints: 100},
+			err:  ErrTooManySteinerPoints,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// checkConforming checks that no vertex is inside of the diametral circle of
+// a constraint segment.
+func checkConforming(t *testing.T, tri *Triangulator) {
+	t.Helper()
+	var vertices [][2]float64
+	for _, e := range tri.GetEdges() {
+		vertices = append(vertices, e...)
+	}
+	for _, segs := range tri.refinement.segments {
+		for _, s := range segs {
+			for _, v := range vertices {
+				if v != s[0] && v != s[1] && encroaches(s, v) {
+					t.Errorf("segment %v, encroached by %v", s, v)
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestConform(t *testing.T) {
+	// the top vertex is in the diametral circle of the bottom segment.
+	plyg := geom.Polygon{{{0, 0}, {10, 0}, {5, 1}}}
+	uut := new(Triangulator)
+	if err := uut.InsertGeometry(plyg); err != nil {
+		t.Fatalf("insert error, expected nil got %v", err)
+	}
+	if err := uut.Conform(); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	checkConforming(t, uut)
+
+	// the constraints still cover the polygon.
+	domain, err := prepared.New(plyg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tris, err := uut.GetTriangles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	area := 0.0
+	for _, ply := range tris {
+		tri := [3][2]float64{ply[0][0], ply[0][1], ply[0][2]}
+		center := [2]float64{(tri[0][0] + tri[1][0] + tri[2][0]) / 3, (tri[0][1] + tri[1][1] + tri[2][1]) / 3}
+		if domain.ContainsPoint(center) {
+			_, a := triangleQuality(tri)
+			area += a
+		}
+	}
+	if area < 5-1e-9 || area > 5+1e-9 {
+		t.Errorf("area, expected 5 got %v", area)
+	}
+}
+
+func TestRefineDeterministic(t *testing.T) {
+	// a grid of points has many vertices on the same circles, where the
+	// triangulation depends on the order the vertices are inserted in.
+	var grid geom.MultiPoint
+	for x := 0.0; x <= 10; x++ {
+		for y := 0.0; y <= 10; y++ {
+			grid = append(grid, [2]float64{x, y})
+		}
+	}
+	refine := func() geom.MultiPolygon {
+		uut := new(Triangulator)
+		if err := uut.InsertGeometry(grid); err != nil {
+			t.Fatalf("insert error, expected nil got %v", err)
+		}
+		if err := uut.Refine(RefineOptions{MinAngle: 20, MaxArea: 0.3}); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		tris, err := uut.GetTriangles()
+		if err != nil {
+			t.Fatalf("triangles error, expected nil got %v", err)
+		}
+		return tris
+	}
+	expected := refine()
+	for i := 0; i < 5; i++ {
+		if got := refine(); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("triangles, expected the same triangles for each refinement")
+		}
+	}
+}
+
+func BenchmarkRefine(b *testing.B) {
+	plyg := geom.Polygon{
+		{{0, 0}, {100, 0}, {100, 100}, {0, 100}},
+		{{40, 40}, {40, 60}, {60, 60}, {60, 40}},
+	}
+	for i := 0; i < b.N; i++ {
+		uut := new(Triangulator)
+		if err := uut.InsertGeometry(plyg); err != nil {
+			b.Fatal(err)
+		}
+		if err := uut.Refine(RefineOptions{MinAngle: 25, MaxArea: 10}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -42,6 +42,13 @@ type Triangulator struct {
 	// one quad edge that has the vertex as an origin. The other quad edges
 	// that point to this vertex can be reached from there.
 	vertexIndex map[quadedge.Vertex]*quadedge.QuadEdge
+	// the geometries and data given to InsertGeometries, used by Refine.
+	input     []geom.Geometry
+	inputData []interface{}
+	// the state of the refinement, nil until Refine is called.
+	refinement *refinement
+	// set when the triangulation has been edited since InsertGeometries.
+	edited bool
 }
 
 /*
@@ -152,10 +159,14 @@ reflect the removal. The local vertex index is also updated to reflect the
 deletion.
 
 It is invalid to call this method on the last edge that links to a vertex.
+ErrUnexpectedDeadNode is returned if the edge has already been deleted.
 
 If tri is nil a panic will occur.
 */
 func (tri *Triangulator) deleteEdge(e *quadedge.QuadEdge) error {
+	if !e.IsLive() {
+		return ErrUnexpectedDeadNode
+	}
 
 	toRemove := make(map[*quadedge.QuadEdge]bool, 4)
 
@@ -292,7 +303,10 @@ returns the edges of the triangulation
 If tri is nil a panic will occur.
 */
 func (tri *Triangulator) GetEdges() geom.MultiLineString {
-	return tri.builder.GetEdges()
+	if tri.subdiv == nil {
+		return geom.MultiLineString{}
+	}
+	return tri.subdiv.GetEdgesAsMultiLineString()
 }
 
 /*
@@ -324,7 +338,10 @@ MultiPolygon.
 If tri is nil a panic will occur.
 */
 func (tri *Triangulator) GetTriangles() (geom.MultiPolygon, error) {
-	return tri.builder.GetTriangles()
+	if tri.subdiv == nil {
+		return geom.MultiPolygon{}, nil
+	}
+	return tri.subdiv.GetTriangles()
 }
 
 /*
@@ -354,6 +371,7 @@ func (tri *Triangulator) InsertGeometries(g []geom.Geometry, data []interface{})
 	if len(data) != 0 && len(g) != len(data) {
 		return ErrMismatchedLengths
 	}
+	tri.input, tri.inputData, tri.refinement, tri.edited = g, data, nil, false
 
 	if err := tri.insertSites(g...); err != nil {
 		if debug {
@@ -750,11 +768,15 @@ func (tri *Triangulator) insertEdgeCDT(ab *triangulate.Segment, data interface{}
 				return err
 			}
 
-			// split the constrained edge we interesect
+			// split the constrained edge we interesect, which deletes it
 			if err := tri.splitEdge(shared, iv); err != nil {
 				return err
 			}
-			tri.deleteEdge(shared)
+			if shared.IsLive() {
+				if err := tri.deleteEdge(shared); err != nil {
+					return err
+				}
+			}
 			tseq, err = t.opposedTriangle(v)
 			if err != nil {
 				return err
@@ -763,7 +785,9 @@ func (tri *Triangulator) insertEdgeCDT(ab *triangulate.Segment, data interface{}
 			// create a new edge for the rest of this segment and recursively
 			// insert the new edge.
 			vb := triangulate.NewSegment(geom.Line{iv, ab.GetEnd()})
-			tri.insertEdgeCDT(&vb, data)
+			if err := tri.insertEdgeCDT(&vb, data); err != nil {
+				return err
+			}
 
 			// the current insertion will stop at the interesction point
 			b = iv
@@ -807,7 +831,9 @@ func (tri *Triangulator) insertEdgeCDT(ab *triangulate.Segment, data interface{}
 	if ab.GetStart().Equals(ab.GetEnd()) == false {
 		// remove the previously marked edges
 		for i := range removalList {
-			tri.deleteEdge(removalList[i])
+			if err := tri.deleteEdge(removalList[i]); err != nil {
+				return err
+			}
 		}
 
 		// TriangulatePseudoPolygon(PU,ab,T)
@@ -159,6 +159,11 @@ func TestDeleteEdge(t *testing.T) {
 			return
 		}
 
+		// deleting it again is an error, not a panic.
+		if err = uut.deleteEdge(e); err != ErrUnexpectedDeadNode {
+			t.Errorf("error deleting edge twice, expected %v got %v", ErrUnexpectedDeadNode, err)
+		}
+
 	}
 	testcases := []tcase{
 		{
@@ -117,10 +117,13 @@ func TestDelaunayTriangulation(t *testing.T) {
 			expectedTris:  "MULTIPOLYGON (((30 150,50 40,80 100,30 150)),((30 150,80 100,70 180,30 150)),((70 180,80 100,130 140,70 180)),((70 180,130 140,190 110,70 180)),((190 110,130 140,140 70,190 110)),((190 110,140 70,120 20,190 110)),((120 20,140 70,80 100,120 20)),((120 20,80 100,50 40,120 20)),((80 100,140 70,130 140,80 100)))",
 		},
 		{
-			inputWKT:      "POLYGON ((42 30, 41.96 29.61, 41.85 29.23, 41.66 28.89, 41.41 28.59, 41.11 28.34, 40.77 28.15, 40.39 28.04, 40 28, 39.61 28.04, 39.23 28.15, 38.89 28.34, 38.59 28.59, 38.34 28.89, 38.15 29.23, 38.04 29.61, 38 30, 38.04 30.39, 38.15 30.77, 38.34 31.11, 38.59 31.41, 38.89 31.66, 39.23 31.85, 39.61 31.96, 40 32, 40.39 31.96, 40.77 31.85, 41.11 31.66, 41.41 31.41, 41.66 31.11, 41.85 30.77, 41.96 30.39, 42 30))",
-			inputWKB:      "0103000000010000002100000000000000000045400000000000003e407b14ae47e1fa44405c8fc2f5289c3d40cdccccccccec44407b14ae47e13a3d4014ae47e17ad44440a4703d0ad7e33c4014ae47e17ab44440d7a3703d0a973c40ae47e17a148e4440d7a3703d0a573c40c3f5285c8f6244406666666666263c4052b81e85eb3144400ad7a3703d0a3c4000000000000044400000000000003c40ae47e17a14ce43400ad7a3703d0a3c403d0ad7a3709d43406666666666263c4052b81e85eb714340d7a3703d0a573c40ec51b81e854b4340d7a3703d0a973c40ec51b81e852b4340a4703d0ad7e33c4033333333331343407b14ae47e13a3d4085eb51b81e0543405c8fc2f5289c3d4000000000000043400000000000003e4085eb51b81e054340a4703d0ad7633e40333333333313434085eb51b81ec53e40ec51b81e852b43405c8fc2f5281c3f40ec51b81e854b4340295c8fc2f5683f4052b81e85eb714340295c8fc2f5a83f403d0ad7a3709d43409a99999999d93f40ae47e17a14ce4340f6285c8fc2f53f400000000000004440000000000000404052b81e85eb314440f6285c8fc2f53f40c3f5285c8f6244409a99999999d93f40ae47e17a148e4440295c8fc2f5a83f4014ae47e17ab44440295c8fc2f5683f4014ae47e17ad444405c8fc2f5281c3f40cdccccccccec444085eb51b81ec53e407b14ae47e1fa4440a4703d0ad7633e4000000000000045400000000000003e40",
-			expectedEdges: "MULTILINESTRING ((41.66 31.11,41.85 30.77),(41.41 31.41,41.66 31.11),(41.11 31.66,41.41 31.41),(40.77 31.85,41.11 31.66),(40.39 31.96,40.77 31.85),(40 32,40.39 31.96),(39.61 31.96,40 32),(39.23 31.85,39.61 31.96),(38.89 31.66,39.23 31.85),(38.59 31.41,38.89 31.66),(38.34 31.11,38.59 31.41),(38.15 30.77,38.34 31.11),(38.04 30.39,38.15 30.77),(38 30,38.04 30.39),(38 30,38.04 29.61),(38.04 29.61,38.15 29.23),(38.15 29.23,38.34 28.89),(38.34 28.89,38.59 28.59),(38.59 28.59,38.89 28.34),(38.89 28.34,39.23 28.15),(39.23 28.15,39.61 28.04),(39.61 28.04,40 28),(40 28,40.39 28.04),(40.39 28.04,40.77 28.15),(40.77 28.15,41.11 28.34),(41.11 28.34,41.41 28.59),(41.41 28.59,41.66 28.89),(41.66 28.89,41.85 29.23),(41.85 29.23,41.96 29.61),(41.96 29.61,42 30),(41.96 30.39,42 30),(41.85 30.77,41.96 30.39),(41.66 31.11,41.96 30.39),(41.41 31.41,41.96 30.39),(41.41 28.59,41.96 30.39),(41.41 28.59,41.41 31.41),(38.59 28.59,41.41 28.59),(38.59 28.59,41.41 31.41),(38.59 28.59,38.59 31.41),(38.59 31.41,41.41 31.41),(38.59 31.41,39.61 31.96),(39.61 31.96,41.41 31.41),(39.61 31.96,40.39 31.96),(40.39 31.96,41.41 31.41),(40.39 31.96,41.11 31.66),(38.04 30.39,38.59 28.59),(38.04 30.39,38.59 31.41),(38.04 30.39,38.34 31.11),(38.04 29.61,38.59 28.59),(38.04 29.61,38.04 30.39),(39.61 28.04,41.41 28.59),(38.59 28.59,39.61 28.04),(38.89 28.34,39.61 28.04),(40.39 28.04,41.41 28.59),(39.61 28.04,40.39 28.04),(41.96 29.61,41.96 30.39),(41.41 28.59,41.96 29.61),(41.66 28.89,41.96 29.61),(40.39 28.04,41.11 28.34),(38.04 29.61,38.34 28.89),(38.89 31.66,39.61 31.96))",
-			expectedTris:  "MULTIPOLYGON (((38.15 30.77,38.04 30.39,38.34 31.11,38.15 30.77)),((38.34 31.11,38.04 30.39,38.59 31.41,38.34 31.11)),((38.59 31.41,38.04 30.39,38.59 28.59,38.59 31.41)),((38.59 31.41,38.59 28.59,41.41 31.41,38.59 31.41)),((38.59 31.41,41.41 31.41,39.61 31.96,38.59 31.41)),((38.59 31.41,39.61 31.96,38.89 31.66,38.59 31.41)),((38.89 31.66,39.61 31.96,39.23 31.85,38.89 31.66)),((39.61 31.96,41.41 31.41,40.39 31.96,39.61 31.96)),((39.61 31.96,40.39 31.96,40 32,39.61 31.96)),((40.39 31.96,41.41 31.41,41.11 31.66,40.39 31.96)),((40.39 31.96,41.11 31.66,40.77 31.85,40.39 31.96)),((41.41 31.41,38.59 28.59,41.41 28.59,41.41 31.41)),((41.41 31.41,41.41 28.59,41.96 30.39,41.41 31.41)),((41.41 31.41,41.96 30.39,41.66 31.11,41.41 31.41)),((41.66 31.11,41.96 30.39,41.85 30.77,41.66 31.11)),((40 28,40.39 28.04,39.61 28.04,40 28)),((39.61 28.04,40.39 28.04,41.41 28.59,39.61 28.04)),((39.61 28.04,41.41 28.59,38.59 28.59,39.61 28.04)),((39.61 28.04,38.59 28.59,38.89 28.34,39.61 28.04)),((39.61 28.04,38.89 28.34,39.23 28.15,39.61 28.04)),((41.41 28.59,40.39 28.04,41.11 28.34,41.41 28.59)),((41.11 28.34,40.39 28.04,40.77 28.15,41.11 28.34)),((41.41 28.59,41.66 28.89,41.96 29.61,41.41 28.59)),((41.41 28.59,41.96 29.61,41.96 30.39,41.41 28.59)),((41.96 30.39,41.96 29.61,42 30,41.96 30.39)),((41.96 29.61,41.66 28.89,41.85 29.23,41.96 29.61)),((38.59 28.59,38.04 30.39,38.04 29.61,38.59 28.59)),((38.59 28.59,38.04 29.61,38.34 28.89,38.59 28.59)),((38.34 28.89,38.04 29.61,38.15 29.23,38.34 28.89)),((38.04 29.61,38.04 30.39,38 30,38.04 29.61)))",
+			inputWKT: "POLYGON ((42 30, 41.96 29.61, 41.85 29.23, 41.66 28.89, 41.41 28.59, 41.11 28.34, 40.77 28.15, 40.39 28.04, 40 28, 39.61 28.04, 39.23 28.15, 38.89 28.34, 38.59 28.59, 38.34 28.89, 38.15 29.23, 38.04 29.61, 38 30, 38.04 30.39, 38.15 30.77, 38.34 31.11, 38.59 31.41, 38.89 31.66, 39.23 31.85, 39.61 31.96, 40 32, 40.39 31.96, 40.77 31.85, 41.11 31.66, 41.41 31.41, 41.66 31.11, 41.85 30.77, 41.96 30.39, 42 30))",
+			inputWKB: "0103000000010000002100000000000000000045400000000000003e407b14ae47e1fa44405c8fc2f5289c3d40cdccccccccec44407b14ae47e13a3d4014ae47e17ad44440a4703d0ad7e33c4014ae47e17ab44440d7a3703d0a973c40ae47e17a148e4440d7a3703d0a573c40c3f5285c8f6244406666666666263c4052b81e85eb3144400ad7a3703d0a3c4000000000000044400000000000003c40ae47e17a14ce43400ad7a3703d0a3c403d0ad7a3709d43406666666666263c4052b81e85eb714340d7a3703d0a573c40ec51b81e854b4340d7a3703d0a973c40ec51b81e852b4340a4703d0ad7e33c4033333333331343407b14ae47e13a3d4085eb51b81e0543405c8fc2f5289c3d4000000000000043400000000000003e4085eb51b81e054340a4703d0ad7633e40333333333313434085eb51b81ec53e40ec51b81e852b43405c8fc2f5281c3f40ec51b81e854b4340295c8fc2f5683f4052b81e85eb714340295c8fc2f5a83f403d0ad7a3709d43409a99999999d93f40ae47e17a14ce4340f6285c8fc2f53f400000000000004440000000000000404052b81e85eb314440f6285c8fc2f53f40c3f5285c8f6244409a99999999d93f40ae47e17a148e4440295c8fc2f5a83f4014ae47e17ab44440295c8fc2f5683f4014ae47e17ad444405c8fc2f5281c3f40cdccccccccec444085eb51b81ec53e407b14ae47e1fa4440a4703d0ad7633e4000000000000045400000000000003e40",
+			// Many of these points are cocircular, so the triangulation is not
+			// unique. The robust in circle predicate makes different choices
+			// than JTS for them; the result is still a Delaunay triangulation.
+			expectedEdges: "MULTILINESTRING ((41.66 31.11,41.85 30.77),(41.41 31.41,41.66 31.11),(41.11 31.66,41.41 31.41),(40.77 31.85,41.11 31.66),(40.39 31.96,40.77 31.85),(40 32,40.39 31.96),(39.61 31.96,40 32),(39.23 31.85,39.61 31.96),(38.89 31.66,39.23 31.85),(38.59 31.41,38.89 31.66),(38.34 31.11,38.59 31.41),(38.15 30.77,38.34 31.11),(38.04 30.39,38.15 30.77),(38 30,38.04 30.39),(38 30,38.04 29.61),(38.04 29.61,38.15 29.23),(38.15 29.23,38.34 28.89),(38.34 28.89,38.59 28.59),(38.59 28.59,38.89 28.34),(38.89 28.34,39.23 28.15),(39.23 28.15,39.61 28.04),(39.61 28.04,40 28),(40 28,40.39 28.04),(40.39 28.04,40.77 28.15),(40.77 28.15,41.11 28.34),(41.11 28.34,41.41 28.59),(41.41 28.59,41.66 28.89),(41.66 28.89,41.85 29.23),(41.85 29.23,41.96 29.61),(41.96 29.61,42 30),(41.96 30.39,42 30),(41.85 30.77,41.96 30.39),(41.66 31.11,41.96 30.39),(41.41 31.41,41.96 30.39),(41.96 29.61,41.96 30.39),(41.41 31.41,41.96 29.61),(41.41 28.59,41.96 29.61),(41.41 28.59,41.41 31.41),(38.59 31.41,41.41 28.59),(38.59 31.41,41.41 31.41),(38.59 31.41,40.39 31.96),(40.39 31.96,41.41 31.41),(40.39 31.96,41.11 31.66),(38.59 31.41,39.61 31.96),(39.61 31.96,40.39 31.96),(38.59 28.59,41.41 28.59),(38.59 28.59,38.59 31.41),(38.04 30.39,38.59 28.59),(38.04 30.39,38.59 31.41),(38.04 30.39,38.34 31.11),(38.04 29.61,38.59 28.59),(38.04 29.61,38.04 30.39),(40.39 28.04,41.41 28.59),(38.59 28.59,40.39 28.04),(39.61 28.04,40.39 28.04),(38.59 28.59,39.61 28.04),(38.89 28.34,39.61 28.04),(41.66 28.89,41.96 29.61),(40.39 28.04,41.11 28.34),(38.04 29.61,38.34 28.89),(38.89 31.66,39.61 31.96))",
+			expectedTris:  "MULTIPOLYGON (((38.15 30.77,38.04 30.39,38.34 31.11,38.15 30.77)),((38.34 31.11,38.04 30.39,38.59 31.41,38.34 31.11)),((38.59 31.41,38.04 30.39,38.59 28.59,38.59 31.41)),((38.59 31.41,38.59 28.59,41.41 28.59,38.59 31.41)),((38.59 31.41,41.41 28.59,41.41 31.41,38.59 31.41)),((38.59 31.41,41.41 31.41,40.39 31.96,38.59 31.41)),((38.59 31.41,40.39 31.96,39.61 31.96,38.59 31.41)),((38.59 31.41,39.61 31.96,38.89 31.66,38.59 31.41)),((38.89 31.66,39.61 31.96,39.23 31.85,38.89 31.66)),((39.61 31.96,40.39 31.96,40 32,39.61 31.96)),((40.39 31.96,41.41 31.41,41.11 31.66,40.39 31.96)),((40.39 31.96,41.11 31.66,40.77 31.85,40.39 31.96)),((41.41 31.41,41.41 28.59,41.96 29.61,41.41 31.41)),((41.41 31.41,41.96 29.61,41.96 30.39,41.41 31.41)),((41.41 31.41,41.96 30.39,41.66 31.11,41.41 31.41)),((41.66 31.11,41.96 30.39,41.85 30.77,41.66 31.11)),((40 28,40.39 28.04,39.61 28.04,40 28)),((39.61 28.04,40.39 28.04,38.59 28.59,39.61 28.04)),((39.61 28.04,38.59 28.59,38.89 28.34,39.61 28.04)),((39.61 28.04,38.89 28.34,39.23 28.15,39.61 28.04)),((38.59 28.59,40.39 28.04,41.41 28.59,38.59 28.59)),((41.41 28.59,40.39 28.04,41.11 28.34,41.41 28.59)),((41.11 28.34,40.39 28.04,40.77 28.15,41.11 28.34)),((41.41 28.59,41.66 28.89,41.96 29.61,41.41 28.59)),((41.96 29.61,41.66 28.89,41.85 29.23,41.96 29.61)),((41.96 29.61,42 30,41.96 30.39,41.96 29.61)),((38.59 28.59,38.04 30.39,38.04 29.61,38.59 28.59)),((38.59 28.59,38.04 29.61,38.34 28.89,38.59 28.59)),((38.34 28.89,38.04 29.61,38.15 29.23,38.34 28.89)),((38.04 29.61,38.04 30.39,38 30,38.04 29.61)))",
 		},
 		{
 			inputWKT:      "POLYGON ((0 0, 0 200, 180 200, 180 0, 0 0), (20 180, 160 180, 160 20, 152.625 146.75, 20 180), (30 160, 150 30, 70 90, 30 160))",
@@ -30,9 +30,10 @@ Author Martin Davis
 Ported to Go by Jason R. Surratt
 */
 type DelaunayTriangulationBuilder struct {
-	siteCoords []quadedge.Vertex
-	tolerance  float64
-	subdiv     *quadedge.QuadEdgeSubdivision
+	siteCoords   []quadedge.Vertex
+	tolerance    float64
+	subdiv       *quadedge.QuadEdgeSubdivision
+	hilbertOrder bool
 }
 
 type PointByXY []quadedge.Vertex
@@ -168,6 +169,17 @@ public void setTolerance(double tolerance)
 }
 */
 
+/*
+SetHilbertOrder sets weather the sites are inserted in the order of a
+Hilbert curve through them. It must be called before the triangulation is
+created.
+
+If dtb is nil a panic will occur.
+*/
+func (dtb *DelaunayTriangulationBuilder) SetHilbertOrder(hilbertOrder bool) {
+	dtb.hilbertOrder = hilbertOrder
+}
+
 /*
 create will create the triangulation.
 
@@ -192,8 +204,8 @@ func (dtb *DelaunayTriangulationBuilder) create() bool {
 	}
 
 	dtb.subdiv = quadedge.NewQuadEdgeSubdivision(*siteEnv, dtb.tolerance)
-	triangulator := new(IncrementalDelaunayTriangulator)
-	triangulator.subdiv = dtb.subdiv
+	triangulator := NewIncrementalDelaunayTriangulator(dtb.subdiv)
+	triangulator.HilbertOrder = dtb.hilbertOrder
 	triangulator.InsertSites(dtb.siteCoords)
 
 	return true
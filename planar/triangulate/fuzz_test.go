@@ -0,0 +1,95 @@
+package triangulate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/geom/internal/tcase"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+/*
+FuzzDelaunay checks that the triangles of the points of a geometry have an
+area, have the sites as corners and have no site inside their
+circumcircles. That all the points are covered is not checked: the frame
+around the points is finite, so triangles of nearly collinear points on the
+hull can be dropped with the frame. The geometry is WKB; the corpus is
+seeded from the wkb test cases.
+*/
+func FuzzDelaunay(f *testing.F) {
+	geos, err := tcase.Geometries(tcase.WKBTestdata())
+	if err != nil {
+		f.Fatalf("error getting test cases: %v", err)
+	}
+	for _, g := range geos {
+		data, err := wkb.EncodeBytes(g)
+		if err != nil {
+			f.Fatalf("encode error, expected nil got %v", err)
+		}
+		f.Add(data, false)
+		f.Add(data, true)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, hilbert bool) {
+		g, err := wkb.DecodeBytes(data)
+		if err != nil {
+			return
+		}
+		pts, err := geom.GetCoordinates(g)
+		if err != nil || len(pts) > 200 {
+			return
+		}
+		for _, pt := range pts {
+			// the predicates are exact only if their products do not
+			// overflow or underflow; this skips NaN and Inf as well
+			for _, v := range pt {
+				if v != 0 && !(1e-50 < math.Abs(v) && math.Abs(v) < 1e70) {
+					return
+				}
+			}
+		}
+
+		builder := NewDelaunayTriangulationBuilder(0)
+		builder.SetHilbertOrder(hilbert)
+		if err := builder.SetSites(g); err != nil {
+			t.Fatalf("sites error, expected nil got %v", err)
+		}
+		// the sites are the points less those equal to another within the
+		// cmp tolerance
+		sites := make(map[[2]float64]bool, len(builder.siteCoords))
+		for _, v := range builder.siteCoords {
+			sites[v] = true
+		}
+		triangles, err := builder.GetTriangles()
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		for _, tri := range triangles {
+			if len(tri) != 1 || len(tri[0]) != 4 || tri[0][0] != tri[0][3] {
+				t.Fatalf("triangle, expected a closed ring of 3 points got %v", tri)
+			}
+			a, b, c := tri[0][0], tri[0][1], tri[0][2]
+			o := robust.Orient2D(a, b, c)
+			if o == 0 {
+				t.Errorf("triangle %v, expected an area", tri)
+				continue
+			}
+			for _, pt := range [][2]float64{a, b, c} {
+				if !sites[pt] {
+					t.Errorf("corner %v of %v, expected a point of the geometry", pt, tri)
+				}
+			}
+			if o < 0 {
+				b, c = c, b
+			}
+			for pt := range sites {
+				if robust.InCircle(a, b, c, pt) > 0 {
+					t.Errorf("point %v, expected outside the circumcircle of %v", pt, tri)
+				}
+			}
+		}
+	})
+}
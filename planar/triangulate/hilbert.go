@@ -0,0 +1,86 @@
+package triangulate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+// hilbertOrder is the order of the Hilbert curve used by hilbertSort; the
+// extent of the vertices is split into a grid of 2^hilbertOrder cells on a
+// side.
+const hilbertOrder = 16
+
+/*
+hilbertIndex returns the distance along a Hilbert curve through a grid of
+n by n cells of the cell x, y. n must be a power of two.
+*/
+func hilbertIndex(n, x, y uint32) uint64 {
+	var d uint64
+	for s := n / 2; s > 0; s /= 2 {
+		var rx, ry uint32
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		// rotate the quadrant
+		if ry == 0 {
+			if rx == 1 {
+				x, y = s-1-x, s-1-y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+/*
+hilbertSort sorts the vertices in the order of a Hilbert curve through their
+extent, so that vertices that follow each other are close together.
+*/
+func hilbertSort(vertices []quadedge.Vertex) {
+	if len(vertices) < 2 {
+		return
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, v := range vertices {
+		minX, maxX = math.Min(minX, v[0]), math.Max(maxX, v[0])
+		minY, maxY = math.Min(minY, v[1]), math.Max(maxY, v[1])
+	}
+	const n = 1 << hilbertOrder
+	span := math.Max(maxX-minX, maxY-minY)
+	if span == 0 {
+		return
+	}
+	cell := func(c, min float64) uint32 {
+		i := uint32((c - min) / span * (n - 1))
+		if i >= n {
+			i = n - 1
+		}
+		return i
+	}
+
+	index := make([]uint64, len(vertices))
+	for i, v := range vertices {
+		index[i] = hilbertIndex(n, cell(v[0], minX), cell(v[1], minY))
+	}
+	sort.Stable(byIndex{vertices, index})
+}
+
+// byIndex sorts vertices by the matching index.
+type byIndex struct {
+	vertices []quadedge.Vertex
+	index    []uint64
+}
+
+func (b byIndex) Len() int           { return len(b.vertices) }
+func (b byIndex) Less(i, j int) bool { return b.index[i] < b.index[j] }
+func (b byIndex) Swap(i, j int) {
+	b.vertices[i], b.vertices[j] = b.vertices[j], b.vertices[i]
+	b.index[i], b.index[j] = b.index[j], b.index[i]
+}
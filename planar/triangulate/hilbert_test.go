@@ -0,0 +1,192 @@
+package triangulate
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+func TestHilbertIndex(t *testing.T) {
+	type tcase struct {
+		n     uint32
+		cells [][2]uint32
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			for i, c := range tc.cells {
+				if d := hilbertIndex(tc.n, c[0], c[1]); d != uint64(i) {
+					t.Errorf("cell %v, expected %v got %v", c, i, d)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"2": {
+			n:     2,
+			cells: [][2]uint32{{0, 0}, {0, 1}, {1, 1}, {1, 0}},
+		},
+		"4": {
+			n: 4,
+			cells: [][2]uint32{
+				{0, 0}, {1, 0}, {1, 1}, {0, 1},
+				{0, 2}, {0, 3}, {1, 3}, {1, 2},
+				{2, 2}, {2, 3}, {3, 3}, {3, 2},
+				{3, 1}, {2, 1}, {2, 0}, {3, 0},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestHilbertSort(t *testing.T) {
+	vertices := []quadedge.Vertex{{10, 0}, {0, 10}, {0, 0}, {10, 10}}
+	hilbertSort(vertices)
+	expected := []quadedge.Vertex{{0, 0}, {0, 10}, {10, 10}, {10, 0}}
+	if !reflect.DeepEqual(vertices, expected) {
+		t.Errorf("vertices, expected %v got %v", expected, vertices)
+	}
+}
+
+// randomSites returns n random points, in a random order.
+func randomSites(n int) geom.MultiPoint {
+	rnd := rand.New(rand.NewSource(42))
+	sites := make(geom.MultiPoint, n)
+	for i := range sites {
+		sites[i] = [2]float64{rnd.Float64() * 1000, rnd.Float64() * 1000}
+	}
+	return sites
+}
+
+// sortedTriangles returns the triangles of the builder, sorted so they can
+// be compared.
+func sortedTriangles(t *testing.T, builder *DelaunayTriangulationBuilder) []string {
+	t.Helper()
+	tris, err := builder.GetTriangles()
+	if err != nil {
+		t.Fatalf("triangles error, expected nil got %v", err)
+	}
+	ret := make([]string, len(tris))
+	for i, tri := range tris {
+		ring := tri[0][:3]
+		// start with the least point
+		sort.Slice(ring, func(i, j int) bool {
+			return ring[i][0] < ring[j][0] || (ring[i][0] == ring[j][0] && ring[i][1] < ring[j][1])
+		})
+		ret[i] = fmt.Sprint(ring)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// insertRandomOrder inserts the sites in the order given, without sorting
+// them, using the locator if it is not nil.
+func insertRandomOrder(sites geom.MultiPoint, locator func(*quadedge.QuadEdgeSubdivision) quadedge.QuadEdgeLocator) (*quadedge.QuadEdgeSubdivision, error) {
+	subdiv := quadedge.NewQuadEdgeSubdivision(*geom.NewExtent(sites...), 0)
+	triangulator := NewIncrementalDelaunayTriangulator(subdiv)
+	if locator != nil {
+		triangulator.SetLocator(locator(subdiv))
+	}
+	for _, pt := range sites {
+		if _, err := triangulator.InsertSite(quadedge.Vertex(pt)); err != nil {
+			return nil, err
+		}
+	}
+	return subdiv, nil
+}
+
+func jumpAndWalk(subdiv *quadedge.QuadEdgeSubdivision) quadedge.QuadEdgeLocator {
+	return quadedge.NewJumpAndWalkLocator(subdiv)
+}
+
+func TestLocatorAndOrder(t *testing.T) {
+	sites := randomSites(2000)
+	expectedBuilder := NewDelaunayTriangulationBuilder(0)
+	expectedBuilder.SetSites(sites)
+	expected := sortedTriangles(t, expectedBuilder)
+
+	t.Run("hilbert", func(t *testing.T) {
+		uut := NewDelaunayTriangulationBuilder(0)
+		uut.SetSites(sites)
+		uut.SetHilbertOrder(true)
+		if err := uut.GetSubdivision().Validate(); err != nil {
+			t.Fatalf("validate, expected nil got %v", err)
+		}
+		if got := sortedTriangles(t, uut); !reflect.DeepEqual(got, expected) {
+			t.Errorf("triangles, expected the same triangles as the default builder")
+		}
+	})
+	t.Run("jump and walk", func(t *testing.T) {
+		subdiv, err := insertRandomOrder(sites, jumpAndWalk)
+		if err != nil {
+			t.Fatalf("insert error, expected nil got %v", err)
+		}
+		if err := subdiv.Validate(); err != nil {
+			t.Fatalf("validate, expected nil got %v", err)
+		}
+		// the builder only adds the subdivision's triangles
+		uut := &DelaunayTriangulationBuilder{subdiv: subdiv}
+		if got := sortedTriangles(t, uut); !reflect.DeepEqual(got, expected) {
+			t.Errorf("triangles, expected the same triangles as the default builder")
+		}
+	})
+}
+
+func TestJumpAndWalkLocator(t *testing.T) {
+	builder := NewDelaunayTriangulationBuilder(0)
+	builder.SetSites(randomSites(500))
+	subdiv := builder.GetSubdivision()
+	uut := quadedge.NewJumpAndWalkLocator(subdiv)
+
+	rnd := rand.New(rand.NewSource(7))
+	for i := 0; i < 200; i++ {
+		v := quadedge.Vertex{rnd.Float64() * 1000, rnd.Float64() * 1000}
+		e, err := uut.Locate(v)
+		if err != nil {
+			t.Fatalf("locate %v error, expected nil got %v", v, err)
+		}
+		// v is in the triangle to the left of e
+		for j, f := 0, e; j < 3; j, f = j+1, f.LNext() {
+			if v.RightOf(*f) {
+				t.Errorf("locate %v, expected to be in the triangle of %v", v, e)
+				break
+			}
+		}
+	}
+}
+
+func benchmarkBuilder(b *testing.B, hilbert bool) {
+	sites := randomSites(20000)
+	for i := 0; i < b.N; i++ {
+		builder := NewDelaunayTriangulationBuilder(0)
+		builder.SetSites(sites)
+		builder.SetHilbertOrder(hilbert)
+		builder.GetSubdivision()
+	}
+}
+
+func BenchmarkBuilderSorted(b *testing.B)  { benchmarkBuilder(b, false) }
+func BenchmarkBuilderHilbert(b *testing.B) { benchmarkBuilder(b, true) }
+
+// the random order InsertSite is called in when the sites are not sorted
+// first is where JumpAndWalkLocator helps.
+func benchmarkRandomOrder(b *testing.B, locator func(*quadedge.QuadEdgeSubdivision) quadedge.QuadEdgeLocator) {
+	sites := randomSites(20000)
+	for i := 0; i < b.N; i++ {
+		if _, err := insertRandomOrder(sites, locator); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRandomOrderLastFound(b *testing.B)   { benchmarkRandomOrder(b, nil) }
+func BenchmarkRandomOrderJumpAndWalk(b *testing.B) { benchmarkRandomOrder(b, jumpAndWalk) }
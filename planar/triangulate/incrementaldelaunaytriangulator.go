@@ -25,6 +25,32 @@ Ported to Go by Jason R. Surratt
 */
 type IncrementalDelaunayTriangulator struct {
 	subdiv *quadedge.QuadEdgeSubdivision
+	// HilbertOrder makes InsertSites insert the vertices in the order of a
+	// Hilbert curve through them, so that each vertex is close to the one
+	// before it.
+	HilbertOrder bool
+}
+
+/*
+NewIncrementalDelaunayTriangulator returns a triangulator that inserts sites
+into subdiv.
+*/
+func NewIncrementalDelaunayTriangulator(subdiv *quadedge.QuadEdgeSubdivision) *IncrementalDelaunayTriangulator {
+	return &IncrementalDelaunayTriangulator{subdiv: subdiv}
+}
+
+/*
+SetLocator sets the locator used to find the triangle each site is inserted
+into. The default is a quadedge.LastFoundQuadEdgeLocator, which is fast when
+each site is close to the one before it, as when the sites are sorted like
+DelaunayTriangulationBuilder does or in HilbertOrder. A
+quadedge.JumpAndWalkLocator is faster when InsertSite is called with sites
+in a random order, and slower for sorted sites.
+
+If idt is nil a panic will occur.
+*/
+func (idt *IncrementalDelaunayTriangulator) SetLocator(locator quadedge.QuadEdgeLocator) {
+	idt.subdiv.SetLocator(locator)
 }
 
 /*
@@ -41,6 +67,10 @@ an unknown state with 0 or more of the vertices inserted.
 If idt is nil a panic will occur.
 */
 func (idt *IncrementalDelaunayTriangulator) InsertSites(vertices []quadedge.Vertex) error {
+	if idt.HilbertOrder {
+		vertices = append([]quadedge.Vertex(nil), vertices...)
+		hilbertSort(vertices)
+	}
 	for _, v := range vertices {
 		_, err := idt.InsertSite(v)
 		if err != nil {
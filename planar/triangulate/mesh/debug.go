@@ -0,0 +1,3 @@
+package mesh
+
+const debug = false
@@ -0,0 +1,238 @@
+/*
+Package mesh triangulates polygons into indexed triangle meshes, as used for
+rendering: a buffer of unique vertices, and a buffer of three vertex indices
+for each triangle.
+*/
+package mesh
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/prepared"
+	"github.com/go-spatial/geom/planar/robust"
+	"github.com/go-spatial/geom/planar/triangulate/constraineddelaunay"
+)
+
+// ErrNotSimple is returned by EarClip for polygons with holes, or with an
+// exterior ring that touches or crosses itself.
+var ErrNotSimple = errors.New("mesh: polygon is not simple")
+
+// Mesh is an indexed triangle mesh.
+type Mesh struct {
+	// Vertices is the vertex buffer; each vertex is only in it once.
+	Vertices [][2]float64
+	// Indices is the index buffer, with three indices into Vertices for
+	// each triangle. The triangles are counter clockwise in a y-up
+	// coordinate system.
+	Indices []uint32
+}
+
+// Len returns the number of triangles in the mesh.
+func (m *Mesh) Len() int { return len(m.Indices) / 3 }
+
+// Triangles returns the triangles of the mesh.
+func (m *Mesh) Triangles() []geom.Triangle {
+	tris := make([]geom.Triangle, m.Len())
+	for i := range tris {
+		for j := 0; j < 3; j++ {
+			tris[i][j] = m.Vertices[m.Indices[i*3+j]]
+		}
+	}
+	return tris
+}
+
+// New returns the mesh of the triangles covering the interior of the
+// polygon, respecting its holes. Simple polygons without holes are
+// triangulated by ear clipping, others by a constrained Delaunay
+// triangulation.
+func New(plyg geom.Polygoner) (*Mesh, error) {
+	m, err := EarClip(plyg)
+	if err == nil {
+		return m, nil
+	}
+	if debug {
+		log.Printf("ear clipping failed (%v), using constrained delaunay", err)
+	}
+	return ConstrainedDelaunay(plyg)
+}
+
+// cleanRing returns the ring without repeated points, or a closing point.
+func cleanRing(ring [][2]float64) [][2]float64 {
+	ret := make([][2]float64, 0, len(ring))
+	for i := range ring {
+		if i > 0 && ring[i] == ret[len(ret)-1] {
+			continue
+		}
+		ret = append(ret, ring[i])
+	}
+	for len(ret) > 1 && ret[0] == ret[len(ret)-1] {
+		ret = ret[:len(ret)-1]
+	}
+	return ret
+}
+
+// segmentsIntersect returns weather the segments a-b and c-d touch or
+// cross.
+func segmentsIntersect(a, b, c, d [2]float64) bool {
+	o1, o2 := robust.Orient2D(a, b, c), robust.Orient2D(a, b, d)
+	o3, o4 := robust.Orient2D(c, d, a), robust.Orient2D(c, d, b)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	onSegment := func(p, q, r [2]float64) bool {
+		return r[0] >= math.Min(p[0], q[0]) && r[0] <= math.Max(p[0], q[0]) &&
+			r[1] >= math.Min(p[1], q[1]) && r[1] <= math.Max(p[1], q[1])
+	}
+	return (o1 == 0 && onSegment(a, b, c)) || (o2 == 0 && onSegment(a, b, d)) ||
+		(o3 == 0 && onSegment(c, d, a)) || (o4 == 0 && onSegment(c, d, b))
+}
+
+// isSimple returns weather the ring does not touch or cross itself.
+func isSimple(ring [][2]float64) bool {
+	n := len(ring)
+	for i := 0; i < n; i++ {
+		a, b := ring[i], ring[(i+1)%n]
+		for j := i + 1; j < n; j++ {
+			// adjacent segments share a point
+			if j == i+1 || (i == 0 && j == n-1) {
+				continue
+			}
+			if segmentsIntersect(a, b, ring[j], ring[(j+1)%n]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// EarClip triangulates a simple polygon without holes by ear clipping. It
+// returns ErrNotSimple for other polygons. Vertices of the ring that are
+// collinear with their neighbours may not be used by any triangle.
+func EarClip(plyg geom.Polygoner) (*Mesh, error) {
+	rings := plyg.LinearRings()
+	if len(rings) == 0 {
+		return &Mesh{}, nil
+	}
+	if len(rings) > 1 {
+		return nil, ErrNotSimple
+	}
+	ring := cleanRing(rings[0])
+	if len(ring) < 3 {
+		return &Mesh{Vertices: ring}, nil
+	}
+	if !isSimple(ring) {
+		return nil, ErrNotSimple
+	}
+
+	m := &Mesh{Vertices: ring}
+	n := len(ring)
+
+	// walk the ring counter clockwise (y-up) using a doubly linked list.
+	area := 0.0
+	for i := range ring {
+		j := (i + 1) % n
+		area += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	prev, next := make([]int, n), make([]int, n)
+	for i := range ring {
+		prev[i], next[i] = (i-1+n)%n, (i+1)%n
+		if area < 0 {
+			prev[i], next[i] = next[i], prev[i]
+		}
+	}
+
+	isEar := func(i int) bool {
+		a, b, c := ring[prev[i]], ring[i], ring[next[i]]
+		if robust.Orient2D(a, b, c) <= 0 {
+			return false
+		}
+		for j := next[next[i]]; j != prev[i]; j = next[j] {
+			p := ring[j]
+			if robust.Orient2D(a, b, p) >= 0 && robust.Orient2D(b, c, p) >= 0 && robust.Orient2D(c, a, p) >= 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	cur, left, tries := 0, n, 0
+	for left > 3 {
+		if tries == left {
+			// no ear was found; drop a vertex on a straight part of the
+			// ring, as it can not be the tip of an ear.
+			for tries = 0; tries < left; tries++ {
+				if robust.Orient2D(ring[prev[cur]], ring[cur], ring[next[cur]]) == 0 {
+					break
+				}
+				cur = next[cur]
+			}
+			if tries == left {
+				return nil, fmt.Errorf("mesh: no ear found in polygon %v", ring)
+			}
+		} else if !isEar(cur) {
+			cur, tries = next[cur], tries+1
+			continue
+		} else {
+			m.Indices = append(m.Indices, uint32(prev[cur]), uint32(cur), uint32(next[cur]))
+		}
+		next[prev[cur]], prev[next[cur]] = next[cur], prev[cur]
+		cur, left, tries = next[cur], left-1, 0
+	}
+	if robust.Orient2D(ring[prev[cur]], ring[cur], ring[next[cur]]) > 0 {
+		m.Indices = append(m.Indices, uint32(prev[cur]), uint32(cur), uint32(next[cur]))
+	}
+	return m, nil
+}
+
+// ConstrainedDelaunay triangulates the polygon with a constrained Delaunay
+// triangulation of its rings, keeping the triangles inside of the polygon.
+// Points where the rings cross are added as vertices.
+func ConstrainedDelaunay(plyg geom.Polygoner) (*Mesh, error) {
+	rings := plyg.LinearRings()
+	if len(rings) == 0 || len(rings[0]) == 0 {
+		return &Mesh{}, nil
+	}
+	tri := new(constraineddelaunay.Triangulator)
+	if err := tri.InsertGeometry(geom.Polygon(rings)); err != nil {
+		return nil, fmt.Errorf("mesh: error triangulating polygon: %v", err)
+	}
+	tris, err := tri.GetTriangles()
+	if err != nil {
+		return nil, err
+	}
+	inside, err := prepared.New(geom.Polygon(rings))
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(Mesh)
+	index := make(map[[2]float64]uint32)
+	indexOf := func(pt [2]float64) uint32 {
+		idx, ok := index[pt]
+		if !ok {
+			idx = uint32(len(m.Vertices))
+			index[pt] = idx
+			m.Vertices = append(m.Vertices, pt)
+		}
+		return idx
+	}
+	for _, t := range tris {
+		gt := geom.NewTriangleFromPolygon(t)
+		if !inside.ContainsPoint(gt.Center()) {
+			continue
+		}
+		a, b, c := gt[0], gt[1], gt[2]
+		switch o := robust.Orient2D(a, b, c); {
+		case o == 0:
+			continue
+		case o < 0:
+			b, c = c, b
+		}
+		m.Indices = append(m.Indices, indexOf(a), indexOf(b), indexOf(c))
+	}
+	return m, nil
+}
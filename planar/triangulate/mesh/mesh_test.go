@@ -0,0 +1,130 @@
+package mesh
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+// area returns the area of the polygon, less the area of its holes.
+func area(plyg geom.Polygon) float64 {
+	total := 0.0
+	for i, ring := range plyg {
+		a := 0.0
+		lp := len(ring) - 1
+		for j := range ring {
+			a += ring[lp][0]*ring[j][1] - ring[j][0]*ring[lp][1]
+			lp = j
+		}
+		if i == 0 {
+			total += math.Abs(a / 2)
+		} else {
+			total -= math.Abs(a / 2)
+		}
+	}
+	return total
+}
+
+// checkMesh checks that the triangles of the mesh are counter clockwise and
+// cover the area of the polygon, and that there are no duplicated vertices.
+func checkMesh(t *testing.T, plyg geom.Polygon, m *Mesh, triangles int) {
+	t.Helper()
+	if len(m.Indices)%3 != 0 {
+		t.Fatalf("indices, expected a multiple of 3 got %v", len(m.Indices))
+	}
+	if m.Len() != triangles {
+		t.Errorf("triangles, expected %v got %v", triangles, m.Len())
+	}
+	seen := make(map[[2]float64]bool)
+	for _, v := range m.Vertices {
+		if seen[v] {
+			t.Errorf("vertices, duplicate vertex %v", v)
+		}
+		seen[v] = true
+	}
+	total := 0.0
+	for _, tri := range m.Triangles() {
+		o := robust.Orient2D(tri[0], tri[1], tri[2])
+		if o <= 0 {
+			t.Errorf("triangle %v, expected counter clockwise", tri)
+		}
+		total += area(geom.Polygon{tri[:]})
+	}
+	if expected := area(plyg); math.Abs(total-expected) > 1e-9 {
+		t.Errorf("area, expected %v got %v", expected, total)
+	}
+}
+
+func TestNew(t *testing.T) {
+	type tcase struct {
+		polygon   geom.Polygon
+		triangles int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			m, err := New(tc.polygon)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			checkMesh(t, tc.polygon, m, tc.triangles)
+		}
+	}
+
+	tests := map[string]tcase{
+		"square": {
+			polygon:   geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			triangles: 2,
+		},
+		"square clockwise closed": {
+			polygon:   geom.Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+			triangles: 2,
+		},
+		"concave": {
+			polygon:   geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {5, 2}, {0, 10}}},
+			triangles: 3,
+		},
+		"collinear": {
+			polygon:   geom.Polygon{{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			triangles: 3,
+		},
+		"hole": {
+			polygon: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+			},
+			triangles: 8,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEarClip(t *testing.T) {
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	m, err := EarClip(square)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := &Mesh{
+		Vertices: [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		Indices:  []uint32{3, 0, 1, 3, 1, 2},
+	}
+	if !reflect.DeepEqual(expected, m) {
+		t.Errorf("mesh, expected %v got %v", expected, m)
+	}
+
+	_, err = EarClip(geom.Polygon{square[0], {{4, 4}, {4, 6}, {6, 6}}})
+	if err != ErrNotSimple {
+		t.Errorf("hole, expected %v got %v", ErrNotSimple, err)
+	}
+	_, err = EarClip(geom.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}}})
+	if err != ErrNotSimple {
+		t.Errorf("bowtie, expected %v got %v", ErrNotSimple, err)
+	}
+}
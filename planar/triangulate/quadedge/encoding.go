@@ -0,0 +1,369 @@
+package quadedge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// binaryMagic starts the binary encoding of a subdivision, followed by the
+// version of the encoding.
+var binaryMagic = [4]byte{'Q', 'E', 'S', 'D'}
+
+const binaryVersion = 1
+
+var ErrInvalidEncoding = errors.New("invalid quad edge subdivision encoding")
+
+/*
+subdivisionData is the encoded form of a subdivision. The edges of each
+quadedge quartet are numbered quad*4 + r, where r is the number of
+rotations from the primal edge of the quartet.
+*/
+type subdivisionData struct {
+	Tolerance float64       `json:"tolerance"`
+	Frame     [3][2]float64 `json:"frame"`
+	Vertices  [][2]float64  `json:"vertices"`
+	Quads     []quadData    `json:"quads"`
+	Start     int           `json:"start"`
+}
+
+type quadData struct {
+	// the index of the origin and destination vertices of the primal edge
+	Orig int `json:"orig"`
+	Dest int `json:"dest"`
+	// the next edge of each of the edges of the quartet
+	Next [4]int `json:"next"`
+	// the JSON encoded data of the primal edge and its sym, if any
+	Data []json.RawMessage `json:"data,omitempty"`
+}
+
+/*
+quads returns the primal edge of each live quadedge quartet of the
+subdivision, including those that are linked to the others but were not
+made through the subdivision.
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) quads() []*QuadEdge {
+	var (
+		quads []*QuadEdge
+		seen  = make(map[*QuadEdge]bool)
+		stack []*QuadEdge
+	)
+	push := func(e *QuadEdge) {
+		if e == nil || !e.IsLive() || seen[e] || seen[e.Sym()] {
+			return
+		}
+		seen[e] = true
+		quads = append(quads, e)
+		stack = append(stack, e)
+	}
+	push(qes.startingEdge)
+	for _, e := range qes.quadEdges {
+		push(e)
+		for len(stack) > 0 {
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			push(e.ONext())
+			push(e.Sym().ONext())
+		}
+	}
+	return quads
+}
+
+/*
+encode returns the encoded form of the subdivision.
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) encode() (*subdivisionData, error) {
+	d := &subdivisionData{
+		Tolerance: qes.tolerance,
+		Start:     -1,
+	}
+	for i, v := range qes.frameVertex {
+		d.Frame[i] = v
+	}
+
+	quads := qes.quads()
+	ids := make(map[*QuadEdge]int, len(quads)*4)
+	for i, q := range quads {
+		for r, e := 0, q; r < 4; r, e = r+1, e.Rot() {
+			ids[e] = i*4 + r
+		}
+	}
+	vertices := make(map[Vertex]int)
+	vertexIndex := func(v Vertex) int {
+		i, ok := vertices[v]
+		if !ok {
+			i = len(d.Vertices)
+			vertices[v] = i
+			d.Vertices = append(d.Vertices, v)
+		}
+		return i
+	}
+
+	d.Quads = make([]quadData, len(quads))
+	for i, q := range quads {
+		qd := &d.Quads[i]
+		qd.Orig, qd.Dest = vertexIndex(q.Orig()), vertexIndex(q.Dest())
+		for r, e := 0, q; r < 4; r, e = r+1, e.Rot() {
+			id, ok := ids[e.ONext()]
+			if !ok {
+				return nil, fmt.Errorf("edge %v is linked to an edge outside of the subdivision", e)
+			}
+			qd.Next[r] = id
+		}
+		if q.GetData() == nil && q.Sym().GetData() == nil {
+			continue
+		}
+		for _, e := range []*QuadEdge{q, q.Sym()} {
+			buf, err := json.Marshal(e.GetData())
+			if err != nil {
+				return nil, fmt.Errorf("error encoding data of edge %v: %v", e, err)
+			}
+			qd.Data = append(qd.Data, buf)
+		}
+	}
+	if id, ok := ids[qes.startingEdge]; ok {
+		d.Start = id
+	}
+	return d, nil
+}
+
+/*
+decode sets up the subdivision from its encoded form.
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) decode(d *subdivisionData) error {
+	edges := make([]*QuadEdge, len(d.Quads)*4)
+	for i := range d.Quads {
+		q := MakeEdge(Vertex{}, Vertex{})
+		for r, e := 0, q; r < 4; r, e = r+1, e.Rot() {
+			edges[i*4+r] = e
+		}
+	}
+	edge := func(id int, primal bool) (*QuadEdge, error) {
+		if id < 0 || id >= len(edges) || (id%2 == 0) != primal {
+			return nil, ErrInvalidEncoding
+		}
+		return edges[id], nil
+	}
+	vertex := func(i int) (Vertex, error) {
+		if i < 0 || i >= len(d.Vertices) {
+			return Vertex{}, ErrInvalidEncoding
+		}
+		return d.Vertices[i], nil
+	}
+
+	quadEdges := make([]*QuadEdge, len(d.Quads))
+	for i, qd := range d.Quads {
+		q := edges[i*4]
+		quadEdges[i] = q
+		o, err := vertex(qd.Orig)
+		if err != nil {
+			return err
+		}
+		dst, err := vertex(qd.Dest)
+		if err != nil {
+			return err
+		}
+		q.setOrig(o)
+		q.setDest(dst)
+		q.Rot().setOrig(o)
+		q.Rot().setDest(dst)
+
+		for r, e := 0, q; r < 4; r, e = r+1, e.Rot() {
+			next, err := edge(qd.Next[r], r%2 == 0)
+			if err != nil {
+				return err
+			}
+			e.SetNext(next)
+		}
+
+		switch len(qd.Data) {
+		case 0:
+		case 2:
+			for j, e := range []*QuadEdge{q, q.Sym()} {
+				var data interface{}
+				if err := json.Unmarshal(qd.Data[j], &data); err != nil {
+					return err
+				}
+				e.SetData(data)
+			}
+		default:
+			return ErrInvalidEncoding
+		}
+	}
+
+	// the next edges must be a permutation of the edges that is consistent
+	// with the dual edges, or the edge rings do not close.
+	seen := make(map[*QuadEdge]bool, len(edges))
+	for _, e := range edges {
+		if seen[e.ONext()] || e.OPrev().ONext() != e {
+			return ErrInvalidEncoding
+		}
+		seen[e.ONext()] = true
+	}
+
+	var start *QuadEdge
+	if len(edges) > 0 {
+		var err error
+		if start, err = edge(d.Start, true); err != nil {
+			return err
+		}
+	}
+
+	*qes = QuadEdgeSubdivision{
+		quadEdges:                quadEdges,
+		startingEdge:             start,
+		tolerance:                d.Tolerance,
+		edgeCoincidenceTolerance: d.Tolerance / EDGE_COINCIDENCE_TOL_FACTOR,
+	}
+	for i := range d.Frame {
+		qes.frameVertex[i] = d.Frame[i]
+	}
+	qes.frameEnv = *geom.NewExtent(d.Frame[:]...)
+	if start != nil {
+		qes.locator = NewLastFoundQuadEdgeLocator(qes)
+	}
+	return nil
+}
+
+/*
+MarshalJSON encodes the subdivision as JSON; the vertices, the topology of
+the edges and the data of each edge, which is encoded with encoding/json.
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) MarshalJSON() ([]byte, error) {
+	d, err := qes.encode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(d)
+}
+
+/*
+UnmarshalJSON restores a subdivision encoded by MarshalJSON. The data of the
+edges is decoded by encoding/json into an interface{}, so a []interface{}
+stays a []interface{}, but other types may not be restored as they were.
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) UnmarshalJSON(buf []byte) error {
+	var d subdivisionData
+	if err := json.Unmarshal(buf, &d); err != nil {
+		return err
+	}
+	return qes.decode(&d)
+}
+
+/*
+MarshalBinary encodes the subdivision in a compact little endian binary
+form. The data of the edges is encoded as JSON, as with MarshalJSON.
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) MarshalBinary() ([]byte, error) {
+	d, err := qes.encode()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := func(v interface{}) { binary.Write(&buf, binary.LittleEndian, v) }
+
+	buf.Write(binaryMagic[:])
+	buf.WriteByte(binaryVersion)
+	w(d.Tolerance)
+	w(d.Frame)
+	w(uint32(len(d.Vertices)))
+	w(d.Vertices)
+	w(uint32(len(d.Quads)))
+	for _, qd := range d.Quads {
+		w([6]uint32{uint32(qd.Orig), uint32(qd.Dest), uint32(qd.Next[0]), uint32(qd.Next[1]), uint32(qd.Next[2]), uint32(qd.Next[3])})
+		w(uint8(len(qd.Data)))
+		for _, data := range qd.Data {
+			w(uint32(len(data)))
+			buf.Write(data)
+		}
+	}
+	w(int32(d.Start))
+	return buf.Bytes(), nil
+}
+
+/*
+UnmarshalBinary restores a subdivision encoded by MarshalBinary.
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) UnmarshalBinary(buf []byte) error {
+	var d subdivisionData
+	r := bytes.NewReader(buf)
+	var err error
+	read := func(v interface{}) {
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, v)
+		}
+	}
+	// count reads a length, limited by the remaining bytes so a corrupt
+	// length does not allocate too much.
+	count := func(size int) int {
+		var n uint32
+		read(&n)
+		if err == nil && int64(n)*int64(size) > int64(r.Len()) {
+			err = ErrInvalidEncoding
+		}
+		return int(n)
+	}
+
+	var magic [4]byte
+	var version uint8
+	read(&magic)
+	read(&version)
+	if err == nil && (magic != binaryMagic || version != binaryVersion) {
+		return ErrInvalidEncoding
+	}
+	read(&d.Tolerance)
+	read(&d.Frame)
+	d.Vertices = make([][2]float64, count(16))
+	read(d.Vertices)
+	d.Quads = make([]quadData, count(25))
+	for i := range d.Quads {
+		qd := &d.Quads[i]
+		var ids [6]uint32
+		var n uint8
+		read(&ids)
+		read(&n)
+		qd.Orig, qd.Dest = int(ids[0]), int(ids[1])
+		for j := range qd.Next {
+			qd.Next[j] = int(ids[j+2])
+		}
+		for j := 0; j < int(n) && err == nil; j++ {
+			data := make([]byte, count(1))
+			read(data)
+			qd.Data = append(qd.Data, data)
+		}
+	}
+	var start int32
+	read(&start)
+	d.Start = int(start)
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrInvalidEncoding
+	}
+	if err != nil {
+		return err
+	}
+	if r.Len() != 0 || math.IsNaN(d.Tolerance) {
+		return ErrInvalidEncoding
+	}
+	return qes.decode(&d)
+}
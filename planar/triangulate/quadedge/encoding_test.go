@@ -0,0 +1,194 @@
+package quadedge
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkt"
+)
+
+/*
+insertSite connects v to the corners of the triangle it falls in, as the
+incremental Delaunay triangulator does before it swaps edges. The first edge
+is made with MakeEdge, so it is not known to the subdivision.
+*/
+func insertSite(t *testing.T, subdiv *QuadEdgeSubdivision, v Vertex) {
+	t.Helper()
+	e, err := subdiv.Locate(v)
+	if err != nil {
+		t.Fatalf("locate %v, expected nil got %v", v, err)
+	}
+	base := MakeEdge(e.Orig(), v)
+	Splice(base, e)
+	start := base
+	for {
+		base = subdiv.Connect(e, base.Sym())
+		e = base.OPrev()
+		if e.LNext() == start {
+			return
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	type tcase struct {
+		sites []Vertex
+		// data for the edges starting at each site
+		data map[Vertex]interface{}
+	}
+
+	encodings := map[string]struct {
+		marshal   func(*QuadEdgeSubdivision) ([]byte, error)
+		unmarshal func(*QuadEdgeSubdivision, []byte) error
+	}{
+		"json":   {(*QuadEdgeSubdivision).MarshalJSON, (*QuadEdgeSubdivision).UnmarshalJSON},
+		"binary": {(*QuadEdgeSubdivision).MarshalBinary, (*QuadEdgeSubdivision).UnmarshalBinary},
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			subdiv := NewQuadEdgeSubdivision(geom.Extent{0, 0, 10, 10}, 0.001)
+			for _, v := range tc.sites {
+				insertSite(t, subdiv, v)
+			}
+			for _, e := range subdiv.GetPrimaryEdges(true) {
+				for _, e := range []*QuadEdge{e, e.Sym()} {
+					if d, ok := tc.data[e.Orig()]; ok {
+						e.SetData(d)
+					}
+				}
+			}
+			if err := subdiv.Validate(); err != nil {
+				t.Fatalf("validate, expected nil got %v", err)
+			}
+			expectedWKT, err := wkt.Encode(subdiv.GetEdgesAsMultiLineString())
+			if err != nil {
+				t.Fatalf("wkt error, expected nil got %v", err)
+			}
+
+			for name, enc := range encodings {
+				buf, err := enc.marshal(subdiv)
+				if err != nil {
+					t.Fatalf("%v marshal error, expected nil got %v", name, err)
+				}
+				var uut QuadEdgeSubdivision
+				if err := enc.unmarshal(&uut, buf); err != nil {
+					t.Fatalf("%v unmarshal error, expected nil got %v", name, err)
+				}
+				if err := uut.Validate(); err != nil {
+					t.Errorf("%v validate, expected nil got %v", name, err)
+				}
+				if uut.frameVertex != subdiv.frameVertex || uut.frameEnv != subdiv.frameEnv || uut.tolerance != subdiv.tolerance {
+					t.Errorf("%v frame, expected %v got %v", name, subdiv.frameVertex, uut.frameVertex)
+				}
+				gotWKT, err := wkt.Encode(uut.GetEdgesAsMultiLineString())
+				if err != nil {
+					t.Fatalf("wkt error, expected nil got %v", err)
+				}
+				if gotWKT != expectedWKT {
+					t.Errorf("%v edges, expected %v got %v", name, expectedWKT, gotWKT)
+				}
+				// the loaded subdivision encodes the same as the original
+				again, err := enc.marshal(&uut)
+				if err != nil {
+					t.Fatalf("%v marshal error, expected nil got %v", name, err)
+				}
+				if !bytes.Equal(again, buf) {
+					t.Errorf("%v, expected the same encoding after a round trip", name)
+				}
+
+				for _, e := range uut.GetPrimaryEdges(true) {
+					for _, e := range []*QuadEdge{e, e.Sym()} {
+						if got := e.GetData(); !reflect.DeepEqual(got, tc.data[e.Orig()]) {
+							t.Errorf("%v data of %v, expected %v got %v", name, e, tc.data[e.Orig()], got)
+						}
+					}
+				}
+				for _, v := range tc.sites {
+					if _, err := uut.Locate(v); err != nil {
+						t.Errorf("%v locate %v, expected nil got %v", name, v, err)
+					}
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"frame": {},
+		"sites": {
+			sites: []Vertex{{5, 4}, {2, 3}, {8, 3}, {4, 9}},
+		},
+		"data": {
+			sites: []Vertex{{5, 4}, {2, 3}, {8, 3}},
+			data: map[Vertex]interface{}{
+				{5, 4}: []interface{}{true, "a"},
+				{2, 3}: "b",
+				{8, 3}: 3.5,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEncodingInvalid(t *testing.T) {
+	subdiv := NewQuadEdgeSubdivision(geom.Extent{0, 0, 10, 10}, 0.001)
+	insertSite(t, subdiv, Vertex{5, 5})
+	buf, err := subdiv.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal error, expected nil got %v", err)
+	}
+	jsonBuf, err := subdiv.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error, expected nil got %v", err)
+	}
+	var d subdivisionData
+	if err := json.Unmarshal(jsonBuf, &d); err != nil {
+		t.Fatalf("unmarshal error, expected nil got %v", err)
+	}
+	// make the next edge of a primal edge a dual edge
+	d.Quads[0].Next[0] = 1
+	badJSON, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("marshal error, expected nil got %v", err)
+	}
+	// make the next edge of two primal edges the same edge
+	d.Quads[0].Next[0] = 4
+	d.Quads[1].Next[0] = 4
+	corruptJSON, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("marshal error, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		unmarshal func(*QuadEdgeSubdivision, []byte) error
+		buf       []byte
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var uut QuadEdgeSubdivision
+			if err := tc.unmarshal(&uut, tc.buf); err != ErrInvalidEncoding {
+				t.Errorf("error, expected %v got %v", ErrInvalidEncoding, err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty":     {(*QuadEdgeSubdivision).UnmarshalBinary, nil},
+		"magic":     {(*QuadEdgeSubdivision).UnmarshalBinary, append([]byte("XXXX"), buf[4:]...)},
+		"truncated": {(*QuadEdgeSubdivision).UnmarshalBinary, buf[:len(buf)-1]},
+		"trailing":  {(*QuadEdgeSubdivision).UnmarshalBinary, append(append([]byte(nil), buf...), 0)},
+		"dual next": {(*QuadEdgeSubdivision).UnmarshalJSON, badJSON},
+		"topology":  {(*QuadEdgeSubdivision).UnmarshalJSON, corruptJSON},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
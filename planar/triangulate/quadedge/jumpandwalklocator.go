@@ -0,0 +1,85 @@
+package quadedge
+
+import (
+	"math"
+	"math/rand"
+)
+
+/*
+JumpAndWalkLocator locates QuadEdges in a QuadEdgeSubdivision by walking from
+the closest of a random sample of edges (Mücke, Saias and Zhu, "Fast
+randomized point location without preprocessing in two- and
+three-dimensional Delaunay triangulations", 1996).
+
+About the cube root of the number of edges are sampled, which keeps the walk
+short for points in a random order, where LastFoundQuadEdgeLocator is only
+fast if each point is close to the one before it. For sorted points the
+sampling costs more than it saves, so LastFoundQuadEdgeLocator is faster.
+
+Implements the QuadEdgeLocator interface.
+*/
+type JumpAndWalkLocator struct {
+	subdiv *QuadEdgeSubdivision
+	rnd    *rand.Rand
+}
+
+/*
+NewJumpAndWalkLocator returns a locator for the subdivision. The samples
+are taken with a fixed seed, so results are repeatable.
+*/
+func NewJumpAndWalkLocator(subdiv *QuadEdgeSubdivision) *JumpAndWalkLocator {
+	if subdiv == nil {
+		return nil
+	}
+	return &JumpAndWalkLocator{
+		subdiv: subdiv,
+		rnd:    rand.New(rand.NewSource(1)),
+	}
+}
+
+/*
+sample returns the edge with the origin closest to v out of n random edges.
+
+If jw is nil a panic will occur.
+*/
+func (jw *JumpAndWalkLocator) sample(v Vertex, n int) *QuadEdge {
+	edges := jw.subdiv.quadEdges
+	var (
+		best     *QuadEdge
+		bestDist = math.Inf(1)
+	)
+	for i := 0; i < n; i++ {
+		e := edges[jw.rnd.Intn(len(edges))]
+		if jw.rnd.Intn(2) == 1 {
+			e = e.Sym()
+		}
+		d := e.Orig().Sub(v)
+		if dist := d.Dot(d); dist < bestDist {
+			best, bestDist = e, dist
+		}
+	}
+	return best
+}
+
+/*
+Locate finds a quadedge of a triangle containing v. If the walk fails, it is
+tried again from other samples before ErrLocateFailure is returned.
+
+If jw is nil a panic will occur.
+*/
+func (jw *JumpAndWalkLocator) Locate(v Vertex) (*QuadEdge, error) {
+	if len(jw.subdiv.quadEdges) == 0 {
+		return nil, ErrLocateFailure{V: &v}
+	}
+	n := int(math.Cbrt(float64(len(jw.subdiv.quadEdges)))) + 1
+
+	var err error
+	for try := 0; try < 3; try++ {
+		var e *QuadEdge
+		e, err = jw.subdiv.LocateFromEdge(v, jw.sample(v, n))
+		if err == nil {
+			return e, nil
+		}
+	}
+	return nil, err
+}
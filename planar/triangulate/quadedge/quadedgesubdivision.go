@@ -183,16 +183,17 @@ func (qes *QuadEdgeSubdivision) GetEdges() []*QuadEdge {
 	return qes.quadEdges
 }
 
-// 	/**
-// 	 * Sets the {@link QuadEdgeLocator} to use for locating containing triangles
-// 	 * in this subdivision.
-// 	 *
-// 	 * @param locator
-// 	 *          a QuadEdgeLocator
-// 	 */
-// 	public void setLocator(QuadEdgeLocator locator) {
-// 		this.locator = locator;
-// 	}
+/*
+SetLocator sets the QuadEdgeLocator to use for locating containing triangles
+in this subdivision.
+
+locator - a QuadEdgeLocator
+
+If qes is nil a panic will occur.
+*/
+func (qes *QuadEdgeSubdivision) SetLocator(locator QuadEdgeLocator) {
+	qes.locator = locator
+}
 
 /*
 MakeEdge creates a new quadedge, recording it in the edges list.
@@ -14,6 +14,7 @@ package quadedge
 
 import (
 	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
 )
 
 /*
@@ -105,8 +106,8 @@ func (_ trianglePredicate) IsInCircleNormalized(a geom.Pointer, b geom.Pointer,
 
 /*
 IsInCircleRobust Tests if a point is inside the circle defined by the triangle
-with vertices a, b, c (oriented counter-clockwise). This method uses more
-robust computation.
+with vertices a, b, c (oriented counter-clockwise). This method uses adaptive
+precision arithmetic, so the result is always correct.
 
 a - a vertex of the triangle
 b - a vertex of the triangle
@@ -117,7 +118,7 @@ Returns true if this point is inside the circle defined by the points a, b, c
 func (tp trianglePredicate) IsInCircleRobust(a geom.Pointer, b geom.Pointer, c geom.Pointer, p geom.Pointer) bool {
 	//checkRobustInCircle(a, b, c, p);
 	//    return isInCircleNonRobust(a, b, c, p);
-	return tp.IsInCircleNormalized(a, b, c, p)
+	return robust.InCircle(a.XY(), b.XY(), c.XY(), p.XY()) > 0
 }
 
 /**
@@ -15,6 +15,8 @@ package quadedge
 import (
 	"encoding/json"
 	"math"
+
+	"github.com/go-spatial/geom/planar/robust"
 )
 
 const (
@@ -182,23 +184,7 @@ c - a vertex of the triangle
 Return true if this vertex is in the circumcircle of (a,b,c)
 */
 func (u Vertex) IsInCircle(a Vertex, b Vertex, c Vertex) bool {
-	// vertext version of IsInCircleNormalized from IsInCircleNormalized
-	adx := a[0] - u[0]
-	ady := a[1] - u[1]
-	bdx := b[0] - u[0]
-	bdy := b[1] - u[1]
-	cdx := c[0] - u[0]
-	cdy := c[1] - u[1]
-
-	abdet := adx*bdy - bdx*ady
-	bcdet := bdx*cdy - cdx*bdy
-	cadet := cdx*ady - adx*cdy
-	alift := adx*adx + ady*ady
-	blift := bdx*bdx + bdy*bdy
-	clift := cdx*cdx + cdy*cdy
-
-	disc := alift*bcdet + blift*cadet + clift*abdet
-	return disc > 0
+	return robust.InCircle(a, b, c, u) > 0
 }
 
 /**
@@ -220,7 +206,7 @@ func (u Vertex) IsCCW(b Vertex, c Vertex) bool {
 
 	// is equal to the signed area of the triangle
 
-	return (b.X()-u.X())*(c.Y()-u.Y())-(b.Y()-u.Y())*(c.X()-u.X()) > 0
+	return robust.Orient2D(u, b, c) > 0
 
 	// original rolled code
 	//boolean isCCW = triArea(this, b, c) > 0;
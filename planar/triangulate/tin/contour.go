@@ -0,0 +1,159 @@
+package tin
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
+// MaxContourLevels is the largest number of levels Contours returns contour
+// lines for.
+const MaxContourLevels = 100000
+
+/*
+Contours returns the contour lines of the tin at every multiple of the
+interval, sorted by elevation. Each line has the higher ground on its left;
+closed contours are rings with the same first and last point. Vertices at
+the elevation of a contour count as higher ground, so contours do not run
+along edges of flat areas twice.
+
+If the interval is not larger than zero, or is so small compared to the
+range of elevations that there would be more than MaxContourLevels levels,
+nil is returned.
+*/
+func (t *TIN) Contours(interval float64) geom.MultiLineString {
+	if !(interval > 0) {
+		return nil
+	}
+	tris := t.Triangles()
+	if len(tris) == 0 {
+		return nil
+	}
+	minZ, maxZ := math.Inf(1), math.Inf(-1)
+	for _, tri := range tris {
+		for _, v := range tri {
+			minZ, maxZ = math.Min(minZ, v[2]), math.Max(maxZ, v[2])
+		}
+	}
+
+	first := math.Ceil(minZ / interval)
+	// the count is checked as a float, as it may not fit into an int.
+	count := math.Floor(maxZ/interval) - first + 1
+	if !(count <= MaxContourLevels) {
+		return nil
+	}
+
+	var lines geom.MultiLineString
+	for i := 0; i < int(count); i++ {
+		level := (first + float64(i)) * interval
+		var segs [][2][2]float64
+		for _, tri := range tris {
+			if s, ok := tri.contour(level); ok {
+				segs = append(segs, s)
+			}
+		}
+		lines = append(lines, joinSegments(segs)...)
+	}
+	return lines
+}
+
+// crossing returns where the edge from a to b crosses the level. The point
+// does not depend on the direction of the edge, so the triangles on both
+// sides of it agree.
+func crossing(a, b [3]float64, level float64) [2]float64 {
+	if cmp.PointLess(xy(b), xy(a)) {
+		a, b = b, a
+	}
+	t := (level - a[2]) / (b[2] - a[2])
+	return [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}
+}
+
+// contour returns the part of the contour at the level in the triangle,
+// with the higher ground on its left.
+func (tri Triangle) contour(level float64) (seg [2][2]float64, ok bool) {
+	var above, below []int
+	for i, v := range tri {
+		if v[2] >= level {
+			above = append(above, i)
+		} else {
+			below = append(below, i)
+		}
+	}
+	var lone int
+	var others []int
+	switch {
+	case len(above) == 1:
+		lone, others = above[0], below
+	case len(below) == 1:
+		lone, others = below[0], above
+	default:
+		return seg, false
+	}
+	p := crossing(tri[lone], tri[others[0]], level)
+	q := crossing(tri[lone], tri[others[1]], level)
+	if p == q {
+		return seg, false
+	}
+	// the points below the contour must be on the right; they can not be on
+	// the contour like the points above can.
+	if robust.Orient2D(p, q, xy(tri[below[0]])) > 0 {
+		p, q = q, p
+	}
+	return [2][2]float64{p, q}, true
+}
+
+// joinSegments joins the segments that share end points into lines.
+func joinSegments(segs [][2][2]float64) [][][2]float64 {
+	// sort to make the output independent of the order of the triangles
+	sort.Slice(segs, func(i, j int) bool {
+		if segs[i][0] != segs[j][0] {
+			return cmp.PointLess(segs[i][0], segs[j][0])
+		}
+		return cmp.PointLess(segs[i][1], segs[j][1])
+	})
+	from := make(map[[2]float64][]int, len(segs))
+	to := make(map[[2]float64][]int, len(segs))
+	for i, s := range segs {
+		from[s[0]] = append(from[s[0]], i)
+		to[s[1]] = append(to[s[1]], i)
+	}
+	used := make([]bool, len(segs))
+	next := func(m map[[2]float64][]int, pt [2]float64) int {
+		for _, i := range m[pt] {
+			if !used[i] {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var lines [][][2]float64
+	build := func(start int) {
+		used[start] = true
+		line := [][2]float64{segs[start][0], segs[start][1]}
+		for i := next(from, line[len(line)-1]); i >= 0; i = next(from, line[len(line)-1]) {
+			used[i] = true
+			line = append(line, segs[i][1])
+		}
+		for i := next(to, line[0]); i >= 0; i = next(to, line[0]) {
+			used[i] = true
+			line = append([][2]float64{segs[i][0]}, line...)
+		}
+		lines = append(lines, line)
+	}
+	// open lines first, so they are not started in the middle
+	for i, s := range segs {
+		if !used[i] && len(to[s[0]]) == 0 {
+			build(i)
+		}
+	}
+	for i := range segs {
+		if !used[i] {
+			build(i)
+		}
+	}
+	return lines
+}
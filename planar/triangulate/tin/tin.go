@@ -0,0 +1,218 @@
+/*
+Package tin provides triangulated irregular networks; surfaces made from a
+Delaunay triangulation of points with an elevation.
+*/
+package tin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
+	"github.com/go-spatial/geom/planar/triangulate"
+	"github.com/go-spatial/geom/planar/triangulate/quadedge"
+)
+
+var ErrNotEnoughPoints = errors.New("tin: at least three points are needed")
+var ErrOutside = errors.New("tin: location is outside of the tin")
+
+// ErrDuplicatePoint is returned by New when two points have the same x and
+// y, but a different elevation.
+type ErrDuplicatePoint struct {
+	XY    [2]float64
+	Z, Z2 float64
+}
+
+func (e ErrDuplicatePoint) Error() string {
+	return fmt.Sprintf("tin: point %v has elevations %v and %v", e.XY, e.Z, e.Z2)
+}
+
+/*
+TIN is a triangulated irregular network. The surface is made up of the
+triangles of the Delaunay triangulation of the points, and covers the convex
+hull of the points.
+
+The methods of a TIN are not safe for concurrent use, as the locator that
+ElevationAt uses keeps track of the last triangle found.
+*/
+type TIN struct {
+	subdiv  *quadedge.QuadEdgeSubdivision
+	locator quadedge.QuadEdgeLocator
+	// the elevation of each vertex
+	z map[quadedge.Vertex]float64
+	// the extent of the vertices
+	extent *geom.Extent
+}
+
+/*
+Triangle is a triangle of a TIN. The vertices are counter clockwise in a
+y-up coordinate system, the third value of each vertex is the elevation.
+*/
+type Triangle [3][3]float64
+
+/*
+New returns the TIN of the points; the third value of each point is its
+elevation. Points that are repeated must have the same elevation.
+*/
+func New(pts [][3]float64) (*TIN, error) {
+	z := make(map[quadedge.Vertex]float64, len(pts))
+	sites := make(geom.MultiPoint, 0, len(pts))
+	for _, pt := range pts {
+		v := quadedge.Vertex{pt[0], pt[1]}
+		if z0, ok := z[v]; ok {
+			if z0 != pt[2] {
+				return nil, ErrDuplicatePoint{XY: v, Z: z0, Z2: pt[2]}
+			}
+			continue
+		}
+		z[v] = pt[2]
+		sites = append(sites, [2]float64{pt[0], pt[1]})
+	}
+	if len(sites) < 3 {
+		return nil, ErrNotEnoughPoints
+	}
+
+	builder := triangulate.NewDelaunayTriangulationBuilder(0)
+	if err := builder.SetSites(sites); err != nil {
+		return nil, err
+	}
+	subdiv := builder.GetSubdivision()
+	return &TIN{
+		subdiv:  subdiv,
+		locator: quadedge.NewLastFoundQuadEdgeLocator(subdiv),
+		z:       z,
+		extent:  geom.NewExtent(sites...),
+	}, nil
+}
+
+// triangleOf returns the triangle to the left of e, and false if it is not
+// a triangle of the tin.
+func (t *TIN) triangleOf(e *quadedge.QuadEdge) (Triangle, bool) {
+	var tri Triangle
+	if e.LNext().LNext().LNext() != e {
+		return tri, false
+	}
+	for i := 0; i < 3; i, e = i+1, e.LNext() {
+		v := e.Orig()
+		z, ok := t.z[v]
+		if !ok {
+			// a vertex of the frame
+			return tri, false
+		}
+		tri[i] = [3]float64{v[0], v[1], z}
+	}
+	return tri, robust.Orient2D(xy(tri[0]), xy(tri[1]), xy(tri[2])) > 0
+}
+
+/*
+Triangles returns the triangles of the tin.
+*/
+func (t *TIN) Triangles() []Triangle {
+	var tris []Triangle
+	seen := make(map[*quadedge.QuadEdge]bool)
+	for _, qe := range t.subdiv.GetPrimaryEdges(false) {
+		for _, e := range []*quadedge.QuadEdge{qe, qe.Sym()} {
+			if seen[e] {
+				continue
+			}
+			seen[e], seen[e.LNext()], seen[e.LPrev()] = true, true, true
+			if tri, ok := t.triangleOf(e); ok {
+				tris = append(tris, tri)
+			}
+		}
+	}
+	return tris
+}
+
+/*
+ElevationAt returns the elevation of the surface at x, y, by linear
+interpolation inside of the triangle that contains the location. ErrOutside
+is returned for locations outside of the convex hull of the points.
+*/
+func (t *TIN) ElevationAt(x, y float64) (float64, error) {
+	v := quadedge.Vertex{x, y}
+	if z, ok := t.z[v]; ok {
+		return z, nil
+	}
+	if !t.extent.ContainsPoint(v) {
+		return 0, ErrOutside
+	}
+	e, err := t.locator.Locate(v)
+	if err != nil {
+		return 0, err
+	}
+	// v is in the triangle left of e, or on e.
+	tri, ok := t.triangleOf(e)
+	if !ok && robust.Orient2D(e.Orig(), e.Dest(), v) == 0 {
+		tri, ok = t.triangleOf(e.Sym())
+	}
+	if !ok || !tri.contains(v) {
+		return 0, ErrOutside
+	}
+	return tri.elevationAt(v), nil
+}
+
+// xy returns the location of the vertex.
+func xy(v [3]float64) [2]float64 { return [2]float64{v[0], v[1]} }
+
+// contains returns weather the point is in the triangle, or on its edges.
+func (tri Triangle) contains(p [2]float64) bool {
+	for i := 0; i < 3; i++ {
+		if robust.Orient2D(xy(tri[i]), xy(tri[(i+1)%3]), p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// elevationAt interpolates the elevation at p with barycentric
+// coordinates.
+func (tri Triangle) elevationAt(p [2]float64) float64 {
+	a, b, c := tri[0], tri[1], tri[2]
+	area := (b[0]-a[0])*(c[1]-a[1]) - (c[0]-a[0])*(b[1]-a[1])
+	wa := ((b[0]-p[0])*(c[1]-p[1]) - (c[0]-p[0])*(b[1]-p[1])) / area
+	wb := ((c[0]-p[0])*(a[1]-p[1]) - (a[0]-p[0])*(c[1]-p[1])) / area
+	wc := 1 - wa - wb
+	return wa*a[2] + wb*b[2] + wc*c[2]
+}
+
+// normal returns the upward normal of the plane of the triangle.
+func (tri Triangle) normal() [3]float64 {
+	a, b, c := tri[0], tri[1], tri[2]
+	u := [3]float64{b[0] - a[0], b[1] - a[1], b[2] - a[2]}
+	v := [3]float64{c[0] - a[0], c[1] - a[1], c[2] - a[2]}
+	return [3]float64{
+		u[1]*v[2] - u[2]*v[1],
+		u[2]*v[0] - u[0]*v[2],
+		u[0]*v[1] - u[1]*v[0],
+	}
+}
+
+/*
+Slope returns the angle between the triangle and the horizontal plane, in
+degrees.
+*/
+func (tri Triangle) Slope() float64 {
+	n := tri.normal()
+	return math.Atan2(math.Hypot(n[0], n[1]), n[2]) * 180 / math.Pi
+}
+
+/*
+Aspect returns the compass direction the triangle faces downhill, in degrees
+clockwise from north (the positive y axis), between 0 and 360. As with GDAL,
+flat triangles have an aspect of -1.
+*/
+func (tri Triangle) Aspect() float64 {
+	n := tri.normal()
+	if n[0] == 0 && n[1] == 0 {
+		return -1
+	}
+	// the horizontal part of the normal points downhill
+	aspect := math.Atan2(n[0], n[1]) * 180 / math.Pi
+	if aspect < 0 {
+		aspect += 360
+	}
+	return aspect
+}
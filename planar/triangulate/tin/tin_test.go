@@ -0,0 +1,195 @@
+package tin
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestNew(t *testing.T) {
+	type tcase struct {
+		pts [][3]float64
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			_, err := New(tc.pts)
+			if err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"triangle": {
+			pts: [][3]float64{{0, 0, 1}, {1, 0, 2}, {0, 1, 3}},
+		},
+		"repeated point": {
+			pts: [][3]float64{{0, 0, 1}, {1, 0, 2}, {0, 1, 3}, {0, 0, 1}},
+		},
+		"not enough points": {
+			pts: [][3]float64{{0, 0, 1}, {1, 0, 2}, {1, 0, 2}},
+			err: ErrNotEnoughPoints,
+		},
+		"duplicate point": {
+			pts: [][3]float64{{0, 0, 1}, {1, 0, 2}, {0, 1, 3}, {0, 0, 2}},
+			err: ErrDuplicatePoint{XY: [2]float64{0, 0}, Z: 1, Z2: 2},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestElevationAt(t *testing.T) {
+	type tcase struct {
+		x, y float64
+		z    float64
+		err  error
+	}
+
+	// a plane, so the interpolation is exact
+	var pts [][3]float64
+	for x := 0.0; x <= 10; x += 2 {
+		for y := 0.0; y <= 10; y += 2.5 {
+			pts = append(pts, [3]float64{x, y, 2*x + y})
+		}
+	}
+	uut, err := New(pts)
+	if err != nil {
+		t.Fatalf("new error, expected nil got %v", err)
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			z, err := uut.ElevationAt(tc.x, tc.y)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if !cmp.Float(z, tc.z) {
+				t.Errorf("elevation, expected %v got %v", tc.z, z)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside":    {x: 3.3, y: 6.1, z: 12.7},
+		"vertex":    {x: 4, y: 5, z: 13},
+		"on edge":   {x: 3, y: 0, z: 6},
+		"corner":    {x: 10, y: 10, z: 30},
+		"left":      {x: -1, y: 5, err: ErrOutside},
+		"far above": {x: 5, y: 500, err: ErrOutside},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSlopeAspect(t *testing.T) {
+	type tcase struct {
+		tri           Triangle
+		slope, aspect float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if slope := tc.tri.Slope(); !cmp.Float(slope, tc.slope) {
+				t.Errorf("slope, expected %v got %v", tc.slope, slope)
+			}
+			if aspect := tc.tri.Aspect(); !cmp.Float(aspect, tc.aspect) {
+				t.Errorf("aspect, expected %v got %v", tc.aspect, aspect)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"flat": {
+			tri:    Triangle{{0, 0, 5}, {1, 0, 5}, {0, 1, 5}},
+			slope:  0,
+			aspect: -1,
+		},
+		"north": {
+			tri:    Triangle{{0, 0, 0}, {1, 0, 0}, {0, 1, -1}},
+			slope:  45,
+			aspect: 0,
+		},
+		"west": {
+			tri:    Triangle{{0, 0, 0}, {1, 0, 1}, {0, 1, 0}},
+			slope:  45,
+			aspect: 270,
+		},
+		"south east": {
+			tri:    Triangle{{0, 0, 0}, {1, 0, -1}, {0, 1, 1}},
+			slope:  math.Atan(math.Sqrt2) * 180 / math.Pi,
+			aspect: 135,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestContours(t *testing.T) {
+	type tcase struct {
+		pts      [][3]float64
+		interval float64
+		lines    geom.MultiLineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			uut, err := New(tc.pts)
+			if err != nil {
+				t.Fatalf("new error, expected nil got %v", err)
+			}
+			lines := uut.Contours(tc.interval)
+			if !reflect.DeepEqual(lines, tc.lines) {
+				t.Errorf("contours, expected %v got %v", tc.lines, lines)
+			}
+		}
+	}
+
+	pyramid := [][3]float64{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}, {0, 10, 0}, {5, 5, 10}}
+	tests := map[string]tcase{
+		"pyramid": {
+			pts:      pyramid,
+			interval: 5,
+			lines: geom.MultiLineString{
+				{{2.5, 2.5}, {7.5, 2.5}, {7.5, 7.5}, {2.5, 7.5}, {2.5, 2.5}},
+			},
+		},
+		"ramp": {
+			pts:      [][3]float64{{0, 0, 0}, {10, 0, 10}, {10, 10, 10}, {0, 10, 0}},
+			interval: 4,
+			lines: geom.MultiLineString{
+				{{4, 10}, {4, 6}, {4, 0}},
+				{{8, 10}, {8, 2}, {8, 0}},
+			},
+		},
+		"no interval": {
+			pts: pyramid,
+		},
+		"too many levels": {
+			pts:      pyramid,
+			interval: 1e-300,
+		},
+		"no levels": {
+			pts:      [][3]float64{{0, 0, 1}, {10, 0, 1}, {10, 10, 2}},
+			interval: 5,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
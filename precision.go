@@ -0,0 +1,59 @@
+package geom
+
+import "math"
+
+// PrecisionModel describes the grid coordinates are snapped to.
+// A nil PrecisionModel, or one with a Scale of 0, is a floating precision
+// model which leaves coordinates untouched.
+type PrecisionModel struct {
+	// Scale is the number of grid cells per unit. A scale of 1000 will
+	// keep three decimal places, a scale of 0.01 will round to hundreds.
+	Scale float64
+}
+
+// NewFixedPrecisionModel returns a precision model with a grid of 1/scale units.
+func NewFixedPrecisionModel(scale float64) *PrecisionModel {
+	return &PrecisionModel{Scale: scale}
+}
+
+// IsFloating returns weather the model leaves coordinates as they are.
+func (pm *PrecisionModel) IsFloating() bool { return pm == nil || pm.Scale == 0 }
+
+// GridSize returns the size of a grid cell, or 0 for a floating model.
+func (pm *PrecisionModel) GridSize() float64 {
+	if pm.IsFloating() {
+		return 0
+	}
+	return 1 / pm.Scale
+}
+
+// Tolerance returns the distance under which two values are considered the
+// same: half a grid cell, or the package tolerance for a floating model.
+func (pm *PrecisionModel) Tolerance() float64 {
+	if pm.IsFloating() {
+		return tolerance
+	}
+	return pm.GridSize() / 2
+}
+
+// MakePrecise rounds f to the nearest grid line.
+func (pm *PrecisionModel) MakePrecise(f float64) float64 {
+	if pm.IsFloating() || math.IsInf(f, 0) || math.IsNaN(f) {
+		return f
+	}
+	return math.Round(f*pm.Scale) / pm.Scale
+}
+
+// MakePrecisePoint rounds both coordinates of pt to the grid.
+func (pm *PrecisionModel) MakePrecisePoint(pt [2]float64) [2]float64 {
+	return [2]float64{pm.MakePrecise(pt[0]), pm.MakePrecise(pt[1])}
+}
+
+// PointEqual returns weather the two points round to the same grid point.
+// For a floating model the points are compared within the package tolerance.
+func (pm *PrecisionModel) PointEqual(p1, p2 [2]float64) bool {
+	if pm.IsFloating() {
+		return pointEqual(p1, p2)
+	}
+	return pm.MakePrecisePoint(p1) == pm.MakePrecisePoint(p2)
+}
@@ -0,0 +1,64 @@
+package geom_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPrecisionModel(t *testing.T) {
+	type tcase struct {
+		pm       *geom.PrecisionModel
+		pt       [2]float64
+		expected [2]float64
+		floating bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if f := tc.pm.IsFloating(); f != tc.floating {
+				t.Errorf("is floating, expected %v got %v", tc.floating, f)
+			}
+			pt := tc.pm.MakePrecisePoint(tc.pt)
+			if pt != tc.expected {
+				t.Errorf("point, expected %v got %v", tc.expected, pt)
+			}
+			if !tc.pm.PointEqual(tc.pt, tc.expected) {
+				t.Errorf("point equal, expected true got false")
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nil": {
+			pt:       [2]float64{1.23456, 2.5},
+			expected: [2]float64{1.23456, 2.5},
+			floating: true,
+		},
+		"floating": {
+			pm:       &geom.PrecisionModel{},
+			pt:       [2]float64{1.23456, 2.5},
+			expected: [2]float64{1.23456, 2.5},
+			floating: true,
+		},
+		"integer": {
+			pm:       geom.NewFixedPrecisionModel(1),
+			pt:       [2]float64{1.23456, 2.5},
+			expected: [2]float64{1, 3},
+		},
+		"two decimals": {
+			pm:       geom.NewFixedPrecisionModel(100),
+			pt:       [2]float64{1.23456, -2.005},
+			expected: [2]float64{1.23, -2.01},
+		},
+		"hundreds": {
+			pm:       geom.NewFixedPrecisionModel(0.01),
+			pt:       [2]float64{1234, 1250},
+			expected: [2]float64{1200, 1300},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,48 @@
+package slippy
+
+import "fmt"
+
+type ErrUnsupportedCRS struct {
+	CRS string
+}
+
+func (e ErrUnsupportedCRS) Error() string {
+	return fmt.Sprintf("unsupported crs: %v", e.CRS)
+}
+
+type ErrInvalidTileMatrixSet struct {
+	ID     string
+	Reason string
+}
+
+func (e ErrInvalidTileMatrixSet) Error() string {
+	return fmt.Sprintf("invalid tile matrix set %v: %v", e.ID, e.Reason)
+}
+
+type ErrZoomOutOfRange struct {
+	Zoom uint
+	ID   string
+}
+
+func (e ErrZoomOutOfRange) Error() string {
+	return fmt.Sprintf("zoom %v is not in tile matrix set %v", e.Zoom, e.ID)
+}
+
+type ErrTileOutOfRange struct {
+	Z, X, Y uint
+	ID      string
+}
+
+func (e ErrTileOutOfRange) Error() string {
+	return fmt.Sprintf("tile %v/%v/%v is not in tile matrix set %v", e.Z, e.X, e.Y, e.ID)
+}
+
+type ErrPointOutOfRange struct {
+	Zoom  uint
+	Point [2]float64
+	ID    string
+}
+
+func (e ErrPointOutOfRange) Error() string {
+	return fmt.Sprintf("point %v is outside of tile matrix set %v at zoom %v", e.Point, e.ID, e.Zoom)
+}
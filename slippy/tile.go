@@ -74,6 +74,15 @@ func (t *Tile) Extent3857() *geom.Extent {
 	)
 }
 
+// Extent returns the tile's extent in the CRS of the given tile matrix set.
+// If tms is nil WebMercatorQuad is used.
+func (t *Tile) Extent(tms *TileMatrixSet) (*geom.Extent, error) {
+	if tms == nil {
+		tms = WebMercatorQuad
+	}
+	return tms.TileExtent(t.Z, t.X, t.Y)
+}
+
 // Extent4326 returns the tile's extent in EPSG:4326 (aka lat/long)
 func (t *Tile) Extent4326() *geom.Extent {
 	return geom.NewExtent(
@@ -0,0 +1,225 @@
+package slippy
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// ToTileCoords transforms a geometry in EPSG:3857 into the pixel space of
+// the tile, where the tile covers [0, extent] on both axes with the origin
+// in the top left corner (as used by Mapbox Vector Tiles). Vertices are
+// snapped to the integer grid, consecutive vertices that collapse onto
+// each other are dropped, and line strings and rings that become
+// degenerate are removed. Points, and geometries whose extent falls
+// entirely outside the tile expanded by buffer pixels are dropped; other
+// geometries are not clipped. If nothing is left, nil is returned.
+func ToTileCoords(g geom.Geometry, tile *Tile, extent, buffer uint) (geom.Geometry, error) {
+	ext := tile.Extent3857()
+	dim := float64(extent)
+	xspan, yspan := ext.XSpan(), ext.YSpan()
+
+	tc := tileCoords{
+		fn: func(pt [2]float64) [2]float64 {
+			return [2]float64{
+				math.Round((pt[0] - ext.MinX()) / xspan * dim),
+				math.Round((ext.MaxY() - pt[1]) / yspan * dim),
+			}
+		},
+		snap: true,
+		clip: geom.NewExtent(
+			[2]float64{-float64(buffer), -float64(buffer)},
+			[2]float64{dim + float64(buffer), dim + float64(buffer)},
+		),
+	}
+	return tc.geometry(g)
+}
+
+// FromTileCoords is the inverse of ToTileCoords, transforming a geometry
+// in the pixel space of the tile back into EPSG:3857.
+func FromTileCoords(g geom.Geometry, tile *Tile, extent uint) (geom.Geometry, error) {
+	ext := tile.Extent3857()
+	dim := float64(extent)
+	xspan, yspan := ext.XSpan(), ext.YSpan()
+
+	tc := tileCoords{
+		fn: func(pt [2]float64) [2]float64 {
+			return [2]float64{
+				ext.MinX() + pt[0]/dim*xspan,
+				ext.MaxY() - pt[1]/dim*yspan,
+			}
+		},
+	}
+	return tc.geometry(g)
+}
+
+// tileCoords applies fn to every vertex of a geometry. If snap is set,
+// collapsed vertices and degenerate parts are removed, and parts outside
+// of clip are dropped.
+type tileCoords struct {
+	fn   func([2]float64) [2]float64
+	snap bool
+	clip *geom.Extent
+}
+
+func (tc tileCoords) points(pts [][2]float64) [][2]float64 {
+	ret := make([][2]float64, 0, len(pts))
+	for i := range pts {
+		pt := tc.fn(pts[i])
+		if tc.snap && len(ret) > 0 && ret[len(ret)-1] == pt {
+			continue
+		}
+		ret = append(ret, pt)
+	}
+	return ret
+}
+
+// outside reports whether all the points are outside of the clip extent
+func (tc tileCoords) outside(pts ...[2]float64) bool {
+	if tc.clip == nil || len(pts) == 0 {
+		return false
+	}
+	ext := geom.NewExtent(pts...)
+	return ext.MaxX() < tc.clip.MinX() || ext.MinX() > tc.clip.MaxX() ||
+		ext.MaxY() < tc.clip.MinY() || ext.MinY() > tc.clip.MaxY()
+}
+
+func (tc tileCoords) lineString(ls [][2]float64) [][2]float64 {
+	ret := tc.points(ls)
+	if tc.snap && (len(ret) < 2 || tc.outside(ret...)) {
+		return nil
+	}
+	return ret
+}
+
+func (tc tileCoords) ring(r [][2]float64) [][2]float64 {
+	ret := tc.points(r)
+	if !tc.snap {
+		return ret
+	}
+	// the ring may or may not have been closed
+	if len(ret) > 1 && ret[0] == ret[len(ret)-1] {
+		ret = ret[:len(ret)-1]
+	}
+	if len(ret) < 3 || ringArea(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+func (tc tileCoords) polygon(plg [][][2]float64) [][][2]float64 {
+	ret := make([][][2]float64, 0, len(plg))
+	for i := range plg {
+		r := tc.ring(plg[i])
+		if r == nil {
+			if i == 0 {
+				// a polygon without an exterior is degenerate
+				return nil
+			}
+			continue
+		}
+		ret = append(ret, r)
+	}
+	if len(ret) == 0 || (tc.snap && tc.outside(ret[0]...)) {
+		return nil
+	}
+	return ret
+}
+
+func (tc tileCoords) geometry(g geom.Geometry) (geom.Geometry, error) {
+	switch gg := g.(type) {
+
+	case geom.Pointer:
+		pt := tc.fn(gg.XY())
+		if tc.snap && tc.clip != nil && !tc.clip.ContainsPoint(pt) {
+			return nil, nil
+		}
+		return geom.Point(pt), nil
+
+	case geom.MultiPointer:
+		pts := tc.points(gg.Points())
+		if !tc.snap {
+			return geom.MultiPoint(pts), nil
+		}
+		mp := make(geom.MultiPoint, 0, len(pts))
+		for i := range pts {
+			if tc.clip == nil || tc.clip.ContainsPoint(pts[i]) {
+				mp = append(mp, pts[i])
+			}
+		}
+		if len(mp) == 0 {
+			return nil, nil
+		}
+		return mp, nil
+
+	case geom.LineStringer:
+		ls := tc.lineString(gg.Verticies())
+		if ls == nil {
+			return nil, nil
+		}
+		return geom.LineString(ls), nil
+
+	case geom.MultiLineStringer:
+		lss := gg.LineStrings()
+		mls := make(geom.MultiLineString, 0, len(lss))
+		for i := range lss {
+			if ls := tc.lineString(lss[i]); ls != nil {
+				mls = append(mls, ls)
+			}
+		}
+		if len(mls) == 0 {
+			return nil, nil
+		}
+		return mls, nil
+
+	case geom.Polygoner:
+		plg := tc.polygon(gg.LinearRings())
+		if plg == nil {
+			return nil, nil
+		}
+		return geom.Polygon(plg), nil
+
+	case geom.MultiPolygoner:
+		plgs := gg.Polygons()
+		mplg := make(geom.MultiPolygon, 0, len(plgs))
+		for i := range plgs {
+			if plg := tc.polygon(plgs[i]); plg != nil {
+				mplg = append(mplg, plg)
+			}
+		}
+		if len(mplg) == 0 {
+			return nil, nil
+		}
+		return mplg, nil
+
+	case geom.Collectioner:
+		geos := gg.Geometries()
+		coll := make(geom.Collection, 0, len(geos))
+		for i := range geos {
+			geo, err := tc.geometry(geos[i])
+			if err != nil {
+				return nil, err
+			}
+			if geo != nil {
+				coll = append(coll, geo)
+			}
+		}
+		if len(coll) == 0 {
+			return nil, nil
+		}
+		return coll, nil
+
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+// ringArea returns the signed area of a ring whose first point is not
+// repeated at the end.
+func ringArea(r [][2]float64) (area float64) {
+	for i := range r {
+		j := (i + 1) % len(r)
+		area += r[i][0]*r[j][1] - r[j][0]*r[i][1]
+	}
+	return area / 2
+}
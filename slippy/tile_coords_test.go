@@ -0,0 +1,151 @@
+package slippy_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/slippy"
+)
+
+func TestToTileCoords(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		tile     *slippy.Tile
+		extent   uint
+		buffer   uint
+		expected geom.Geometry
+	}
+
+	// one pixel of tile 0/0/0 with an extent of 4096
+	const px = slippy.WebMercatorMax * 2 / 4096
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			g, err := slippy.ToTileCoords(tc.geom, tc.tile, tc.extent, tc.buffer)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if tc.expected == nil {
+				if g != nil {
+					t.Errorf("geometry, expected nil got %v", g)
+				}
+				return
+			}
+			if !cmp.GeometryEqual(tc.expected, g) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, g)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point center": {
+			geom:     geom.Point{0, 0},
+			tile:     slippy.NewTile(0, 0, 0),
+			extent:   4096,
+			expected: geom.Point{2048, 2048},
+		},
+		"point top left": {
+			geom:     geom.Point{-slippy.WebMercatorMax, slippy.WebMercatorMax},
+			tile:     slippy.NewTile(1, 0, 0),
+			extent:   4096,
+			expected: geom.Point{0, 0},
+		},
+		"point in buffer": {
+			geom:     geom.Point{1, 1},
+			tile:     slippy.NewTile(1, 0, 0),
+			extent:   4096,
+			buffer:   64,
+			expected: geom.Point{4096, 4096},
+		},
+		"point outside buffer": {
+			geom:   geom.Point{100 * px, 0},
+			tile:   slippy.NewTile(1, 0, 0),
+			extent: 4096,
+			buffer: 64,
+		},
+		"linestring collapsed vertices": {
+			geom: geom.LineString{
+				{0, 0}, {0.1 * px, 0.1 * px}, {0.2 * px, 0}, {10 * px, 0},
+			},
+			tile:     slippy.NewTile(0, 0, 0),
+			extent:   4096,
+			expected: geom.LineString{{2048, 2048}, {2058, 2048}},
+		},
+		"linestring degenerate": {
+			geom:   geom.LineString{{0, 0}, {0.1 * px, 0.1 * px}},
+			tile:   slippy.NewTile(0, 0, 0),
+			extent: 4096,
+		},
+		"polygon degenerate hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {0, 10 * px}, {10 * px, 10 * px}, {10 * px, 0}},
+				{{1 * px, 1 * px}, {1.1 * px, 1.2 * px}, {1.2 * px, 1 * px}},
+			},
+			tile:   slippy.NewTile(0, 0, 0),
+			extent: 4096,
+			expected: geom.Polygon{
+				{{2048, 2048}, {2048, 2038}, {2058, 2038}, {2058, 2048}},
+			},
+		},
+		"polygon collapsed to a line": {
+			geom: geom.Polygon{
+				{{0, 0}, {0, 10 * px}, {0.1 * px, 10 * px}, {0.1 * px, 0}},
+			},
+			tile:   slippy.NewTile(0, 0, 0),
+			extent: 4096,
+		},
+		"multipolygon": {
+			geom: geom.MultiPolygon{
+				{{{0, 0}, {0, 0.1 * px}, {0.1 * px, 0}}},
+				{{{0, 0}, {0, 10 * px}, {10 * px, 0}}},
+			},
+			tile:   slippy.NewTile(0, 0, 0),
+			extent: 4096,
+			expected: geom.MultiPolygon{
+				{{{2048, 2048}, {2048, 2038}, {2058, 2048}}},
+			},
+		},
+		"collection": {
+			geom: geom.Collection{
+				geom.Point{0, 0},
+				geom.LineString{{0, 0}, {0.1 * px, 0}},
+			},
+			tile:     slippy.NewTile(0, 0, 0),
+			extent:   4096,
+			expected: geom.Collection{geom.Point{2048, 2048}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestFromTileCoords(t *testing.T) {
+	tile := slippy.NewTile(16, 11436, 26461)
+	ext := tile.Extent3857()
+
+	g, err := slippy.FromTileCoords(geom.LineString{{0, 0}, {4096, 4096}}, tile, 4096)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := geom.LineString{{ext.MinX(), ext.MaxY()}, {ext.MaxX(), ext.MinY()}}
+	if !cmp.GeometryEqual(expected, g) {
+		t.Errorf("geometry, expected %v got %v", expected, g)
+	}
+
+	// round trip
+	pt := geom.Point{ext.MinX() + ext.XSpan()/4, ext.MinY() + ext.YSpan()/4}
+	tg, err := slippy.ToTileCoords(pt, tile, 4096, 0)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	g, err = slippy.FromTileCoords(tg, tile, 4096)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !cmp.GeometryEqual(pt, g) {
+		t.Errorf("round trip, expected %v got %v", pt, g)
+	}
+}
@@ -0,0 +1,292 @@
+package slippy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+// Corner is the corner of a tile matrix the tile indices start counting from.
+type Corner string
+
+const (
+	TopLeft    Corner = "topLeft"
+	BottomLeft Corner = "bottomLeft"
+)
+
+// CRS identifies the coordinate reference system of a TileMatrixSet,
+// usually as a URI such as "http://www.opengis.net/def/crs/EPSG/0/3857".
+type CRS string
+
+// UnmarshalJSON accepts the crs either as a plain string or as an
+// object with a uri member, both of which are allowed by OGC TMS 2.0.
+func (c *CRS) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*c = CRS(s)
+		return nil
+	}
+
+	var obj struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	if obj.URI == "" {
+		return ErrUnsupportedCRS{string(b)}
+	}
+	*c = CRS(obj.URI)
+	return nil
+}
+
+// Common CRS identifiers.
+const (
+	CRS3857  CRS = "http://www.opengis.net/def/crs/EPSG/0/3857"
+	CRS3395  CRS = "http://www.opengis.net/def/crs/EPSG/0/3395"
+	CRS4326  CRS = "http://www.opengis.net/def/crs/EPSG/0/4326"
+	CRSCRS84 CRS = "http://www.opengis.net/def/crs/OGC/1.3/CRS84"
+)
+
+// TileMatrix describes the grid of tiles at a single zoom level.
+type TileMatrix struct {
+	ID               string  `json:"id"`
+	ScaleDenominator float64 `json:"scaleDenominator,omitempty"`
+	// CellSize is the size of a pixel in CRS units
+	CellSize       float64 `json:"cellSize"`
+	CornerOfOrigin Corner  `json:"cornerOfOrigin,omitempty"`
+	// PointOfOrigin is in the axis order of the CRS, see
+	// TileMatrixSet.OrderedAxes
+	PointOfOrigin [2]float64 `json:"pointOfOrigin"`
+	TileWidth     uint       `json:"tileWidth"`
+	TileHeight    uint       `json:"tileHeight"`
+	MatrixWidth   uint       `json:"matrixWidth"`
+	MatrixHeight  uint       `json:"matrixHeight"`
+}
+
+// TileSpan returns the width and height of a single tile in CRS units.
+func (tm *TileMatrix) TileSpan() (width, height float64) {
+	return float64(tm.TileWidth) * tm.CellSize, float64(tm.TileHeight) * tm.CellSize
+}
+
+// TileMatrixSet describes a tiling scheme, as defined by the OGC Two
+// Dimensional Tile Matrix Set standard (version 2.0). The tile matrix at
+// index z of TileMatrices is used for tiles with zoom z.
+type TileMatrixSet struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title,omitempty"`
+	URI   string `json:"uri,omitempty"`
+	CRS   CRS    `json:"crs"`
+	// OrderedAxes gives the axis order of PointOfOrigin. When empty
+	// the order is assumed to be x, y (easting, northing).
+	OrderedAxes  []string     `json:"orderedAxes,omitempty"`
+	TileMatrices []TileMatrix `json:"tileMatrices"`
+}
+
+// LoadTileMatrixSet reads an OGC TMS 2.0 JSON definition.
+func LoadTileMatrixSet(r io.Reader) (*TileMatrixSet, error) {
+	var tms TileMatrixSet
+	if err := json.NewDecoder(r).Decode(&tms); err != nil {
+		return nil, err
+	}
+	if err := tms.Validate(); err != nil {
+		return nil, err
+	}
+	return &tms, nil
+}
+
+// Validate checks that every tile matrix is usable.
+func (tms *TileMatrixSet) Validate() error {
+	if len(tms.TileMatrices) == 0 {
+		return ErrInvalidTileMatrixSet{ID: tms.ID, Reason: "no tile matrices"}
+	}
+	for i := range tms.TileMatrices {
+		tm := &tms.TileMatrices[i]
+		switch {
+		case tm.CellSize <= 0:
+			return ErrInvalidTileMatrixSet{ID: tms.ID, Reason: fmt.Sprintf("tile matrix %v: cellSize must be positive", tm.ID)}
+		case tm.TileWidth == 0 || tm.TileHeight == 0:
+			return ErrInvalidTileMatrixSet{ID: tms.ID, Reason: fmt.Sprintf("tile matrix %v: tile size must be positive", tm.ID)}
+		case tm.MatrixWidth == 0 || tm.MatrixHeight == 0:
+			return ErrInvalidTileMatrixSet{ID: tms.ID, Reason: fmt.Sprintf("tile matrix %v: matrix size must be positive", tm.ID)}
+		case tm.CornerOfOrigin != "" && tm.CornerOfOrigin != TopLeft && tm.CornerOfOrigin != BottomLeft:
+			return ErrInvalidTileMatrixSet{ID: tms.ID, Reason: fmt.Sprintf("tile matrix %v: unknown cornerOfOrigin %v", tm.ID, tm.CornerOfOrigin)}
+		}
+	}
+	return nil
+}
+
+// MaxZoom returns the largest zoom the set has a tile matrix for.
+func (tms *TileMatrixSet) MaxZoom() uint {
+	if len(tms.TileMatrices) == 0 {
+		return 0
+	}
+	return uint(len(tms.TileMatrices) - 1)
+}
+
+// Matrix returns the tile matrix for the given zoom.
+func (tms *TileMatrixSet) Matrix(zoom uint) (*TileMatrix, error) {
+	if zoom >= uint(len(tms.TileMatrices)) {
+		return nil, ErrZoomOutOfRange{Zoom: zoom, ID: tms.ID}
+	}
+	return &tms.TileMatrices[zoom], nil
+}
+
+// swapAxes reports whether the first ordered axis is the northing
+func (tms *TileMatrixSet) swapAxes() bool {
+	if len(tms.OrderedAxes) == 0 {
+		return false
+	}
+	switch strings.ToLower(tms.OrderedAxes[0]) {
+	case "y", "n", "lat", "latitude", "northing":
+		return true
+	}
+	return false
+}
+
+// origin returns the point of origin of tm in x, y order
+func (tms *TileMatrixSet) origin(tm *TileMatrix) [2]float64 {
+	if tms.swapAxes() {
+		return [2]float64{tm.PointOfOrigin[1], tm.PointOfOrigin[0]}
+	}
+	return tm.PointOfOrigin
+}
+
+// TileExtent returns the extent of the tile z/x/y in the CRS of the set.
+func (tms *TileMatrixSet) TileExtent(z, x, y uint) (*geom.Extent, error) {
+	tm, err := tms.Matrix(z)
+	if err != nil {
+		return nil, err
+	}
+	if x >= tm.MatrixWidth || y >= tm.MatrixHeight {
+		return nil, ErrTileOutOfRange{Z: z, X: x, Y: y, ID: tms.ID}
+	}
+
+	o := tms.origin(tm)
+	w, h := tm.TileSpan()
+
+	minx := o[0] + float64(x)*w
+	if tm.CornerOfOrigin == BottomLeft {
+		miny := o[1] + float64(y)*h
+		return geom.NewExtent([2]float64{minx, miny}, [2]float64{minx + w, miny + h}), nil
+	}
+
+	maxy := o[1] - float64(y)*h
+	return geom.NewExtent([2]float64{minx, maxy - h}, [2]float64{minx + w, maxy}), nil
+}
+
+// Extent returns the extent covered by all the tiles at the given zoom.
+func (tms *TileMatrixSet) Extent(zoom uint) (*geom.Extent, error) {
+	tm, err := tms.Matrix(zoom)
+	if err != nil {
+		return nil, err
+	}
+	tl, err := tms.TileExtent(zoom, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	br, err := tms.TileExtent(zoom, tm.MatrixWidth-1, tm.MatrixHeight-1)
+	if err != nil {
+		return nil, err
+	}
+	tl.Add(br)
+	return tl, nil
+}
+
+// TileAt returns the tile at the given zoom containing the point pt,
+// which is in x, y order in the CRS of the set.
+func (tms *TileMatrixSet) TileAt(zoom uint, pt [2]float64) (*Tile, error) {
+	tm, err := tms.Matrix(zoom)
+	if err != nil {
+		return nil, err
+	}
+
+	o := tms.origin(tm)
+	w, h := tm.TileSpan()
+
+	col := math.Floor((pt[0] - o[0]) / w)
+	row := math.Floor((o[1] - pt[1]) / h)
+	if tm.CornerOfOrigin == BottomLeft {
+		row = math.Floor((pt[1] - o[1]) / h)
+	}
+
+	// points on the far edges belong to the last tile
+	if col == float64(tm.MatrixWidth) {
+		col--
+	}
+	if row == float64(tm.MatrixHeight) {
+		row--
+	}
+
+	if col < 0 || row < 0 || col >= float64(tm.MatrixWidth) || row >= float64(tm.MatrixHeight) {
+		return nil, ErrPointOutOfRange{Zoom: zoom, Point: pt, ID: tms.ID}
+	}
+
+	return NewTile(zoom, uint(col), uint(row)), nil
+}
+
+// NewQuadTileMatrixSet creates a tile matrix set where each zoom doubles
+// the number of rows and columns of the previous one. The origin is the
+// top left corner of extent, and the tile matrix at zoom 0 has width by
+// height tiles of tileSize pixels covering the extent.
+func NewQuadTileMatrixSet(id string, crs CRS, extent *geom.Extent, width, height, tileSize, maxZoom uint) *TileMatrixSet {
+	tms := &TileMatrixSet{
+		ID:           id,
+		CRS:          crs,
+		TileMatrices: make([]TileMatrix, 0, maxZoom+1),
+	}
+
+	cellSize := extent.XSpan() / float64(width*tileSize)
+	for z := uint(0); z <= maxZoom; z++ {
+		scale := math.Exp2(float64(z))
+		tms.TileMatrices = append(tms.TileMatrices, TileMatrix{
+			ID:             fmt.Sprint(z),
+			CellSize:       cellSize / scale,
+			CornerOfOrigin: TopLeft,
+			PointOfOrigin:  [2]float64{extent.MinX(), extent.MaxY()},
+			TileWidth:      tileSize,
+			TileHeight:     tileSize,
+			MatrixWidth:    width << z,
+			MatrixHeight:   height << z,
+		})
+	}
+
+	return tms
+}
+
+var (
+	// WebMercatorQuad is the EPSG:3857 tiling scheme used by most slippy maps
+	WebMercatorQuad = NewQuadTileMatrixSet(
+		"WebMercatorQuad",
+		CRS3857,
+		geom.NewExtent(
+			[2]float64{-WebMercatorMax, -WebMercatorMax},
+			[2]float64{WebMercatorMax, WebMercatorMax},
+		),
+		1, 1, 256, MaxZoom,
+	)
+
+	// WorldCRS84Quad is the EPSG:4326 (lon, lat) tiling scheme which has
+	// two tiles at zoom 0
+	WorldCRS84Quad = NewQuadTileMatrixSet(
+		"WorldCRS84Quad",
+		CRSCRS84,
+		geom.NewExtent([2]float64{-180, -90}, [2]float64{180, 90}),
+		2, 1, 256, MaxZoom,
+	)
+
+	// WorldMercatorWGS84Quad is the EPSG:3395 tiling scheme
+	WorldMercatorWGS84Quad = NewQuadTileMatrixSet(
+		"WorldMercatorWGS84Quad",
+		CRS3395,
+		geom.NewExtent(
+			[2]float64{-WebMercatorMax, -WebMercatorMax},
+			[2]float64{WebMercatorMax, WebMercatorMax},
+		),
+		1, 1, 256, MaxZoom,
+	)
+)
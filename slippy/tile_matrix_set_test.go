@@ -0,0 +1,170 @@
+package slippy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/slippy"
+)
+
+func TestTileExtent(t *testing.T) {
+	type tcase struct {
+		tms     *slippy.TileMatrixSet
+		tile    *slippy.Tile
+		eExtent *geom.Extent
+		err     error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			extent, err := tc.tile.Extent(tc.tms)
+			if tc.err != nil {
+				if err != tc.err {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.GeomExtent(tc.eExtent, extent) {
+				t.Errorf("extent, expected %v got %v", tc.eExtent, extent)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"web mercator default": {
+			tile:    slippy.NewTile(16, 11436, 26461),
+			eExtent: slippy.NewTile(16, 11436, 26461).Extent3857(),
+		},
+		"web mercator z2": {
+			tms:     slippy.WebMercatorQuad,
+			tile:    slippy.NewTile(2, 1, 1),
+			eExtent: slippy.NewTile(2, 1, 1).Extent3857(),
+		},
+		"crs84 z0 west": {
+			tms:     slippy.WorldCRS84Quad,
+			tile:    slippy.NewTile(0, 0, 0),
+			eExtent: geom.NewExtent([2]float64{-180, -90}, [2]float64{0, 90}),
+		},
+		"crs84 z1": {
+			tms:     slippy.WorldCRS84Quad,
+			tile:    slippy.NewTile(1, 3, 1),
+			eExtent: geom.NewExtent([2]float64{90, -90}, [2]float64{180, 0}),
+		},
+		"crs84 out of range": {
+			tms:  slippy.WorldCRS84Quad,
+			tile: slippy.NewTile(0, 0, 1),
+			err:  slippy.ErrTileOutOfRange{Z: 0, X: 0, Y: 1, ID: "WorldCRS84Quad"},
+		},
+		"zoom out of range": {
+			tms:  slippy.WorldCRS84Quad,
+			tile: slippy.NewTile(slippy.MaxZoom+1, 0, 0),
+			err:  slippy.ErrZoomOutOfRange{Zoom: slippy.MaxZoom + 1, ID: "WorldCRS84Quad"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLoadTileMatrixSet(t *testing.T) {
+	// a bottom left origin grid in lat, lon axis order
+	const def = `{
+	"id": "Custom",
+	"crs": {"uri": "http://www.opengis.net/def/crs/EPSG/0/4326"},
+	"orderedAxes": ["Lat", "Lon"],
+	"tileMatrices": [
+		{
+			"id": "0",
+			"cellSize": 1,
+			"cornerOfOrigin": "bottomLeft",
+			"pointOfOrigin": [10, 20],
+			"tileWidth": 10,
+			"tileHeight": 5,
+			"matrixWidth": 4,
+			"matrixHeight": 2
+		}
+	]
+}`
+
+	tms, err := slippy.LoadTileMatrixSet(strings.NewReader(def))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if tms.CRS != slippy.CRS4326 {
+		t.Errorf("crs, expected %v got %v", slippy.CRS4326, tms.CRS)
+	}
+
+	extent, err := tms.TileExtent(0, 1, 1)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	eExtent := geom.NewExtent([2]float64{30, 15}, [2]float64{40, 20})
+	if !cmp.GeomExtent(eExtent, extent) {
+		t.Errorf("extent, expected %v got %v", eExtent, extent)
+	}
+
+	tile, err := tms.TileAt(0, [2]float64{35, 17})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if z, x, y := tile.ZXY(); z != 0 || x != 1 || y != 1 {
+		t.Errorf("tile, expected 0/1/1 got %v/%v/%v", z, x, y)
+	}
+
+	_, err = slippy.LoadTileMatrixSet(strings.NewReader(`{"id": "empty", "crs": "EPSG:4326", "tileMatrices": []}`))
+	if _, ok := err.(slippy.ErrInvalidTileMatrixSet); !ok {
+		t.Errorf("error, expected ErrInvalidTileMatrixSet got %v", err)
+	}
+}
+
+func TestTileAt(t *testing.T) {
+	type tcase struct {
+		tms   *slippy.TileMatrixSet
+		zoom  uint
+		pt    [2]float64
+		etile *slippy.Tile
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			tile, err := tc.tms.TileAt(tc.zoom, tc.pt)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if *tile != *tc.etile {
+				t.Errorf("tile, expected %v got %v", tc.etile, tile)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"web mercator center": {
+			tms:   slippy.WebMercatorQuad,
+			zoom:  8,
+			pt:    [2]float64{1, -1},
+			etile: slippy.NewTile(8, 128, 128),
+		},
+		"web mercator corner": {
+			tms:   slippy.WebMercatorQuad,
+			zoom:  1,
+			pt:    [2]float64{slippy.WebMercatorMax, -slippy.WebMercatorMax},
+			etile: slippy.NewTile(1, 1, 1),
+		},
+		"crs84 east": {
+			tms:   slippy.WorldCRS84Quad,
+			zoom:  0,
+			pt:    [2]float64{10, 10},
+			etile: slippy.NewTile(0, 1, 0),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
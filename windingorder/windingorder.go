@@ -1,5 +1,10 @@
 package windingorder
 
+import (
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/robust"
+)
+
 // WindingOrder is the clockwise direction of a set of points.
 type WindingOrder bool
 
@@ -8,6 +13,23 @@ const (
 	CounterClockwise WindingOrder = true
 )
 
+// The winding order of the exterior rings of polygons under common
+// conventions; interior rings have the opposite winding order. Note that
+// Clockwise is clockwise in a y-down coordinate system, like that of
+// tiles, and so counter clockwise in a y-up one, like longitude/latitude.
+const (
+	// RFC7946 is the convention of GeoJSON (RFC 7946 section 3.1.6):
+	// exterior rings are counter clockwise in a y-up coordinate system.
+	// OGC Simple Features (section 6.1.11.1) uses the same convention.
+	RFC7946 = Clockwise
+	// Shapefile is the convention of ESRI Shapefiles: exterior rings are
+	// clockwise in a y-up coordinate system, the opposite of RFC7946.
+	Shapefile = CounterClockwise
+	// MVT is the convention of Mapbox Vector Tiles: exterior rings are
+	// clockwise in the y-down tile coordinate system.
+	MVT = Clockwise
+)
+
 func (w WindingOrder) String() string {
 	if w {
 		return "counter clockwise"
@@ -17,14 +39,117 @@ func (w WindingOrder) String() string {
 func (w WindingOrder) IsClockwise() bool        { return w == Clockwise }
 func (w WindingOrder) IsCounterClockwise() bool { return w == CounterClockwise }
 func (w WindingOrder) Not() WindingOrder        { return !w }
+
+// OfPoints returns the winding order of the ring formed by the points. The
+// ring does not need to be closed. The orientation is decided at the
+// highest point of the ring using a robust orientation predicate, so nearly
+// collinear points do not affect the result.
 func OfPoints(pts ...[2]float64) WindingOrder {
-	sum := 0.0
-	li := len(pts) - 1
-	for i := range pts[:li] {
-		sum += (pts[i][0] * pts[i+1][1]) - (pts[i+1][0] * pts[i][1])
+	n := len(pts)
+	// ignore the closing point if there is one
+	if n > 1 && pts[0] == pts[n-1] {
+		n--
+	}
+	if n < 3 {
+		return Clockwise
+	}
+
+	hi := 0
+	for i := 1; i < n; i++ {
+		if pts[i][1] > pts[hi][1] {
+			hi = i
+		}
+	}
+
+	// find the distinct points before and after the highest point
+	prev := (hi - 1 + n) % n
+	for prev != hi && pts[prev] == pts[hi] {
+		prev = (prev - 1 + n) % n
+	}
+	next := (hi + 1) % n
+	for next != hi && pts[next] == pts[hi] {
+		next = (next + 1) % n
+	}
+	// all the points are the same, or we have an A-B-A configuration
+	if prev == hi || next == hi || pts[prev] == pts[next] {
+		return Clockwise
 	}
-	if sum < 0 {
+
+	// Our clockwise is counter-clockwise in a y-up coordinate system.
+	switch o := robust.Orient2D(pts[prev], pts[hi], pts[next]); {
+	case o > 0:
+		return Clockwise
+	case o < 0:
+		return CounterClockwise
+	default:
+		// the cap is flat, the direction of travel along it decides
+		if pts[prev][0] > pts[next][0] {
+			return Clockwise
+		}
 		return CounterClockwise
 	}
-	return Clockwise
+}
+
+// enforceRings returns a copy of the rings of a polygon with the first ring
+// having the exterior winding order and the others the opposite one.
+func enforceRings(rings [][][2]float64, exterior WindingOrder) [][][2]float64 {
+	ret := make([][][2]float64, len(rings))
+	for i := range rings {
+		order := exterior
+		if i > 0 {
+			order = exterior.Not()
+		}
+		ret[i] = append([][2]float64{}, rings[i]...)
+		// rings with less than three distinct points have no winding order
+		if len(ret[i]) < 3 || OfPoints(ret[i]...) == order {
+			continue
+		}
+		// reverse the ring, keeping the same first (and closing) point
+		last := len(ret[i]) - 1
+		if ret[i][0] == ret[i][last] {
+			last--
+		}
+		for l, r := 1, last; l < r; l, r = l+1, r-1 {
+			ret[i][l], ret[i][r] = ret[i][r], ret[i][l]
+		}
+	}
+	return ret
+}
+
+// Enforce returns a copy of the geometry with the exterior rings of all its
+// polygons having the given winding order, and the interior rings the
+// opposite one. Geometries that are not polygons are returned as is. Use
+// Enforce with RFC7946 and Shapefile to flip between those conventions.
+func Enforce(g geom.Geometry, exterior WindingOrder) (geom.Geometry, error) {
+	switch gg := g.(type) {
+
+	case geom.Pointer, geom.MultiPointer, geom.LineStringer, geom.MultiLineStringer:
+		return g, nil
+
+	case geom.Polygoner:
+		return geom.Polygon(enforceRings(gg.LinearRings(), exterior)), nil
+
+	case geom.MultiPolygoner:
+		plygs := gg.Polygons()
+		mp := make(geom.MultiPolygon, len(plygs))
+		for i := range plygs {
+			mp[i] = enforceRings(plygs[i], exterior)
+		}
+		return mp, nil
+
+	case geom.Collectioner:
+		geos := gg.Geometries()
+		coll := make(geom.Collection, len(geos))
+		for i := range geos {
+			eg, err := Enforce(geos[i], exterior)
+			if err != nil {
+				return nil, err
+			}
+			coll[i] = eg
+		}
+		return coll, nil
+
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
 }
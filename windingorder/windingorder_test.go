@@ -1,6 +1,11 @@
 package windingorder
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
 
 func TestAttributeMethods(t *testing.T) {
 
@@ -58,6 +63,99 @@ func TestOfPoints(t *testing.T) {
 			},
 			order: CounterClockwise,
 		},
+		"closed ring": {
+			pts: [][2]float64{
+				{0, 10}, {10, 10}, {10, 0}, {0, 0}, {0, 10},
+			},
+			order: CounterClockwise,
+		},
+		"empty": {
+			pts:   nil,
+			order: Clockwise,
+		},
+		"single point": {
+			pts:   [][2]float64{{1, 1}},
+			order: Clockwise,
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		exterior WindingOrder
+		expected geom.Geometry
+		err      error
+	}
+	fn := func(t *testing.T, tc tcase) {
+		got, err := Enforce(tc.geom, tc.exterior)
+		if tc.err != nil {
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(tc.expected, got) {
+			t.Errorf("enforce, expected %v got %v", tc.expected, got)
+		}
+	}
+	// exterior is Clockwise, the hole CounterClockwise.
+	polygon := geom.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		{{2, 2}, {2, 4}, {4, 4}, {4, 2}},
+	}
+	flipped := geom.Polygon{
+		{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+		{{2, 2}, {4, 2}, {4, 4}, {2, 4}},
+	}
+	tests := map[string]tcase{
+		"point": {
+			geom:     geom.Point{1, 2},
+			exterior: Shapefile,
+			expected: geom.Point{1, 2},
+		},
+		"polygon as is": {
+			geom:     polygon,
+			exterior: RFC7946,
+			expected: polygon,
+		},
+		"polygon flipped": {
+			geom:     polygon,
+			exterior: Shapefile,
+			expected: flipped,
+		},
+		"polygon flipped back": {
+			geom:     flipped,
+			exterior: RFC7946,
+			expected: polygon,
+		},
+		"closed ring": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+			exterior: CounterClockwise,
+			expected: geom.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 0}}},
+		},
+		"multipolygon": {
+			geom:     geom.MultiPolygon{polygon, flipped},
+			exterior: Shapefile,
+			expected: geom.MultiPolygon{flipped, flipped},
+		},
+		"collection": {
+			geom:     geom.Collection{geom.LineString{{0, 0}, {1, 1}}, flipped},
+			exterior: MVT,
+			expected: geom.Collection{geom.LineString{{0, 0}, {1, 1}}, polygon},
+		},
+		"unknown": {
+			geom:     geom.Extent{},
+			exterior: Shapefile,
+			err:      geom.ErrUnknownGeometry{Geom: geom.Extent{}},
+		},
 	}
 	for name, tc := range tests {
 		tc := tc